@@ -0,0 +1,90 @@
+package visigoth
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AnalysisStage holds the tokens output by a component of an analyzer.
+type AnalysisStage struct {
+	// Name is the type of the component, e.g. "UnicodeTokenizer".
+	Name   string  `json:"name"`
+	Tokens []Token `json:"tokens"`
+}
+
+// Analysis details how a text is analyzed, stage by stage: first the output
+// of the tokenizer, then the output of every filter.
+type Analysis struct {
+	// Analyzer is the name of the analyzer, when taken from a registry.
+	Analyzer string `json:"analyzer,omitempty"`
+	// Language is the language the text was analyzed as, if any.
+	Language Language `json:"language,omitempty"`
+	// CharFiltered is the text given to the tokenizer, when char filters
+	// modified it.
+	CharFiltered string          `json:"char_filtered,omitempty"`
+	Stages       []AnalysisStage `json:"stages"`
+}
+
+// Analyzable is implemented by indices able to detail how they analyze text.
+type Analyzable interface {
+	Analyze(text string) Analysis
+}
+
+// Tokens returns the tokens output by the last stage.
+func (a Analysis) Tokens() []Token {
+	if len(a.Stages) == 0 {
+		return nil
+	}
+	return a.Stages[len(a.Stages)-1].Tokens
+}
+
+// Analyze runs text through the pipeline keeping the output of every stage.
+func (p *TokenizationPipeline) Analyze(text string) Analysis {
+	var analysis Analysis
+	if len(p.charFilters) > 0 {
+		if filtered, _ := chainCharFilters(text, p.charFilters); filtered != text {
+			analysis.CharFiltered = filtered
+		}
+	}
+	tokens := p.tokenize(text)
+	analysis.Stages = make([]AnalysisStage, 0, len(p.streamFilters)+1)
+	analysis.Stages = append(analysis.Stages, AnalysisStage{
+		Name:   componentName(p.tokenizer),
+		Tokens: tokens,
+	})
+	for i, filter := range p.streamFilters {
+		tokens = filter.FilterStream(tokens)
+		analysis.Stages = append(analysis.Stages, AnalysisStage{
+			Name:   componentName(p.filters[i]),
+			Tokens: tokens,
+		})
+	}
+	return analysis
+}
+
+// analyze details how tkr analyzes text, whatever its kind.
+func analyze(tkr tokenizer, text string) Analysis {
+	switch t := tkr.(type) {
+	case *TokenizationPipeline:
+		return t.Analyze(text)
+	case *MultilingualAnalyzer:
+		lang := t.Detect(text)
+		analysis := t.Analyzer(lang).Analyze(text)
+		analysis.Language = lang
+		return analysis
+	default:
+		return Analysis{Stages: []AnalysisStage{{
+			Name:   componentName(tkr),
+			Tokens: AdaptTokenizer(tkr).TokenStream(text),
+		}}}
+	}
+}
+
+func componentName(component any) string {
+	name := fmt.Sprintf("%T", component)
+	name = strings.TrimPrefix(name, "*")
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
@@ -0,0 +1,83 @@
+package visigoth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenizationPipeline_Analyze(t *testing.T) {
+	analyzer := NewTokenizationPipeline(
+		NewKeepAlphanumericTokenizer(),
+		NewLowerCaseTokenizer(),
+		NewStopWordsFilter(SpanishStopWords),
+	)
+	analysis := analyzer.Analyze("El Go")
+
+	assert.Equal(t, []AnalysisStage{
+		{Name: "CleanTokenizer", Tokens: []Token{
+			{Term: "El", Position: 0, PositionIncrement: 1, Start: 0, End: 2, Type: TokenWord},
+			{Term: "Go", Position: 1, PositionIncrement: 1, Start: 3, End: 5, Type: TokenWord},
+		}},
+		{Name: "LowerCaseFilter", Tokens: []Token{
+			{Term: "el", Position: 0, PositionIncrement: 1, Start: 0, End: 2, Type: TokenWord},
+			{Term: "go", Position: 1, PositionIncrement: 1, Start: 3, End: 5, Type: TokenWord},
+		}},
+		{Name: "StopWordsFilter", Tokens: []Token{
+			{Term: "go", Position: 1, PositionIncrement: 2, Start: 3, End: 5, Type: TokenWord},
+		}},
+	}, analysis.Stages)
+	assert.Equal(t, analyzer.TokenStream("El Go"), analysis.Tokens())
+}
+
+func TestIndexRepo_Analyze(t *testing.T) {
+	es, err := NewLanguageAnalyzer(Spanish)
+	assert.NoError(t, err)
+	en, err := NewLanguageAnalyzer(English)
+	assert.NoError(t, err)
+	ml := NewMultilingualAnalyzer(
+		NewTokenizationPipeline(NewKeepAlphanumericTokenizer()),
+		map[Language]*TokenizationPipeline{Spanish: es, English: en},
+	)
+	repo := NewIndexRepo(NewMemoryIndexBuilder(ml))
+	repo.Put("courses", NewDocRequest("go", "Go"))
+	repo.Put("other", NewDocRequest("go", "Go"))
+	repo.Alias("all", "courses")
+	repo.Alias("all", "other")
+
+	analysis, err := repo.Analyze("courses", "Aprendiendo programación con Go y los canales")
+	assert.NoError(t, err)
+	assert.Equal(t, Spanish, analysis.Language)
+	assert.Len(t, analysis.Stages, 4)
+	assert.Equal(t, []string{"aprend", "program", "go", "canal"}, Terms(analysis.Tokens()))
+
+	analysis, err = repo.Analyze(StandardAnalyzer, "Hello, World!")
+	assert.NoError(t, err)
+	assert.Equal(t, StandardAnalyzer, analysis.Analyzer)
+	assert.Equal(t, []string{"hello", "world"}, Terms(analysis.Tokens()))
+
+	_, err = repo.Analyze("all", "go")
+	assert.Error(t, err, "aliases pointing to several indices are ambiguous")
+	_, err = repo.Analyze("klingon", "go")
+	assert.Error(t, err)
+}
+
+func TestIndexRepo_Analyze_Registry(t *testing.T) {
+	registry := NewAnalyzerRegistry()
+	assert.NoError(t, registry.Define("codes", AnalyzerConfig{
+		Tokenizer: ComponentConfig{Type: "keyword"},
+	}))
+	builder, err := NewAnalyzerMemoryIndexBuilder(registry, "codes")
+	assert.NoError(t, err)
+	repo := NewIndexRepo(builder, WithAnalyzerRegistry(registry))
+	repo.Put("products", NewDocRequest("1", "SKU-1"))
+
+	analysis, err := repo.Analyze("products", "SKU-1 B")
+	assert.NoError(t, err)
+	assert.Equal(t, "codes", analysis.Analyzer)
+	assert.Equal(t, []string{"SKU-1 B"}, Terms(analysis.Tokens()))
+
+	analysis, err = repo.Analyze("codes", "SKU-2")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"SKU-2"}, Terms(analysis.Tokens()))
+}
@@ -0,0 +1,233 @@
+package visigoth
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// CharFilter transforms text before it is tokenized, e.g. to strip markup.
+// Along with the output text it returns how to map offsets in the output back
+// to the input, so that tokens keep offsets in the original text.
+type CharFilter interface {
+	FilterChars(text string) (string, OffsetMap)
+}
+
+// OffsetMap maps byte offsets in the output of a CharFilter to offsets in its
+// input. Start offsets of text that replaced something else map to the start
+// of what was replaced, and end offsets to its end.
+type OffsetMap interface {
+	CorrectStart(offset int) int
+	CorrectEnd(offset int) int
+}
+
+// offsetSegment relates a range of the output to the range of the input it
+// comes from, either verbatim or as a replacement.
+type offsetSegment struct {
+	outStart, outEnd int
+	inStart, inEnd   int
+	verbatim         bool
+}
+
+// offsetSegments covers the whole output with non-empty segments, in order.
+type offsetSegments []offsetSegment
+
+func (s offsetSegments) beyond(offset int) int {
+	if len(s) == 0 {
+		return offset
+	}
+	last := s[len(s)-1]
+	return last.inEnd + offset - last.outEnd
+}
+
+func (s offsetSegments) CorrectStart(offset int) int {
+	i := sort.Search(len(s), func(i int) bool { return s[i].outEnd > offset })
+	if i == len(s) {
+		return s.beyond(offset)
+	}
+	if seg := s[i]; seg.verbatim {
+		return seg.inStart + offset - seg.outStart
+	}
+	return s[i].inStart
+}
+
+func (s offsetSegments) CorrectEnd(offset int) int {
+	if offset <= 0 {
+		return s.CorrectStart(offset)
+	}
+	i := sort.Search(len(s), func(i int) bool { return s[i].outEnd >= offset })
+	if i == len(s) {
+		return s.beyond(offset)
+	}
+	if seg := s[i]; seg.verbatim {
+		return seg.inStart + offset - seg.outStart
+	}
+	return s[i].inEnd
+}
+
+// offsetChain maps offsets through several char filters, the last one first.
+type offsetChain []OffsetMap
+
+func (c offsetChain) CorrectStart(offset int) int {
+	for i := len(c) - 1; i >= 0; i-- {
+		offset = c[i].CorrectStart(offset)
+	}
+	return offset
+}
+
+func (c offsetChain) CorrectEnd(offset int) int {
+	for i := len(c) - 1; i >= 0; i-- {
+		offset = c[i].CorrectEnd(offset)
+	}
+	return offset
+}
+
+// offsetsBuilder writes the output of a char filter as it walks its input,
+// keeping track of where every part of the output comes from.
+type offsetsBuilder struct {
+	text     string
+	out      strings.Builder
+	segments offsetSegments
+	// copied is the input offset up to which the input has been processed.
+	copied int
+}
+
+func (b *offsetsBuilder) emit(inStart, inEnd int, s string, verbatim bool) {
+	if s == "" {
+		return
+	}
+	outStart := b.out.Len()
+	b.out.WriteString(s)
+	if n := len(b.segments); verbatim && n > 0 {
+		if last := &b.segments[n-1]; last.verbatim && last.inEnd == inStart {
+			last.outEnd, last.inEnd = b.out.Len(), inEnd
+			return
+		}
+	}
+	b.segments = append(b.segments, offsetSegment{
+		outStart: outStart,
+		outEnd:   b.out.Len(),
+		inStart:  inStart,
+		inEnd:    inEnd,
+		verbatim: verbatim,
+	})
+}
+
+// copyTo copies the input verbatim up to offset.
+func (b *offsetsBuilder) copyTo(offset int) {
+	if offset > b.copied {
+		b.emit(b.copied, offset, b.text[b.copied:offset], true)
+		b.copied = offset
+	}
+}
+
+// replace copies the input up to from, and then writes s in place of the
+// input from from to to.
+func (b *offsetsBuilder) replace(from, to int, s string) {
+	b.copyTo(from)
+	b.emit(from, to, s, false)
+	b.copied = max(b.copied, to)
+}
+
+// keep removes the input up to from, and copies it verbatim from from to to.
+func (b *offsetsBuilder) keep(from, to int) {
+	b.copied = max(b.copied, from)
+	b.copyTo(to)
+}
+
+func (b *offsetsBuilder) finish() (string, OffsetMap) {
+	b.copyTo(len(b.text))
+	return b.out.String(), b.segments
+}
+
+func newOffsetsBuilder(text string) *offsetsBuilder {
+	b := &offsetsBuilder{text: text}
+	b.out.Grow(len(text))
+	return b
+}
+
+// PatternReplaceCharFilter replaces every match of a regular expression with a
+// replacement, in which $1 or ${name} stand for submatches as in
+// regexp.Regexp.Expand.
+type PatternReplaceCharFilter struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+func (p PatternReplaceCharFilter) FilterChars(text string) (string, OffsetMap) {
+	b := newOffsetsBuilder(text)
+	for _, match := range p.pattern.FindAllStringSubmatchIndex(text, -1) {
+		expanded := p.pattern.ExpandString(nil, p.replacement, text, match)
+		b.replace(match[0], match[1], string(expanded))
+	}
+	return b.finish()
+}
+
+func NewPatternReplaceCharFilter(
+	pattern *regexp.Regexp,
+	replacement string,
+) PatternReplaceCharFilter {
+	return PatternReplaceCharFilter{pattern: pattern, replacement: replacement}
+}
+
+// stripPattern removes every match of a regular expression but for the first
+// submatch found in it, if any. It is a building block of markup strippers.
+type stripPattern struct {
+	pattern *regexp.Regexp
+	// accept, if set, tells whether a match is to be removed. Matches it
+	// rejects are kept, and searched again from their second character on.
+	accept func(text string, match []int) bool
+}
+
+func (s stripPattern) FilterChars(text string) (string, OffsetMap) {
+	b := newOffsetsBuilder(text)
+	for _, match := range s.matches(text) {
+		b.copyTo(match[0])
+		for g := 2; g < len(match); g += 2 {
+			if match[g] >= 0 {
+				b.keep(match[g], match[g+1])
+				break
+			}
+		}
+		b.keep(match[1], match[1])
+	}
+	return b.finish()
+}
+
+func (s stripPattern) matches(text string) [][]int {
+	if s.accept == nil {
+		return s.pattern.FindAllStringSubmatchIndex(text, -1)
+	}
+	var matches [][]int
+	for pos := 0; pos < len(text); {
+		match := s.pattern.FindStringSubmatchIndex(text[pos:])
+		if match == nil {
+			break
+		}
+		for i := range match {
+			if match[i] >= 0 {
+				match[i] += pos
+			}
+		}
+		if !s.accept(text, match) {
+			_, size := utf8.DecodeRuneInString(text[match[0]:])
+			pos = match[0] + size
+			continue
+		}
+		matches = append(matches, match)
+		pos = max(match[1], match[0]+1)
+	}
+	return matches
+}
+
+// chainCharFilters runs text through char filters in order.
+func chainCharFilters(text string, filters []CharFilter) (string, OffsetMap) {
+	chain := make(offsetChain, 0, len(filters))
+	for _, filter := range filters {
+		var offsets OffsetMap
+		text, offsets = filter.FilterChars(text)
+		chain = append(chain, offsets)
+	}
+	return text, chain
+}
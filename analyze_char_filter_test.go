@@ -0,0 +1,147 @@
+package visigoth
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// assertOffsets checks that the offsets of every token point to the text the
+// token comes from.
+func assertOffsets(t *testing.T, text string, tokens []Token, expected ...string) {
+	t.Helper()
+	found := make([]string, len(tokens))
+	for i, tok := range tokens {
+		found[i] = text[tok.Start:tok.End]
+	}
+	assert.Equal(t, expected, found)
+}
+
+func TestHTMLStripCharFilter(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"<p>Hello <b>wor</b>ld</p>", " Hello world "},
+		{`<a href="x>y" title='>'>link</a>`, "link"},
+		{"caf&eacute; &amp; t&#233; &#x41;", "café & té A"},
+		{"a<br/>b<!-- <p>c</p> -->d", "a b d"},
+		{"x<script>if (a < b) {}</script>y<STYLE>p{}</STYLE>z", "x y z"},
+		{"1 < 2 & 3 > 2 &unknown; <", "1 < 2 & 3 > 2 &unknown; <"},
+		{`a <b title="x <i>y</i> &amp; z`, `a <b title="x <i>y</i> & z`},
+		{"<p>x</p><!-- y <b>z</b>", " x <!-- y <b>z</b>"},
+	}
+	for _, test := range tests {
+		out, _ := NewHTMLStripCharFilter().FilterChars(test.text)
+		assert.Equal(t, test.expected, out, test.text)
+	}
+}
+
+func TestHTMLStripCharFilter_LongText(t *testing.T) {
+	// Tags and comments never closed are scanned once, rather than once per
+	// "<" that follows them
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{strings.Repeat("<a ", 100_000), strings.Repeat("<a ", 100_000)},
+		{strings.Repeat("<!-- ", 100_000), strings.Repeat("<!-- ", 100_000)},
+		{"<script>" + strings.Repeat("</script ", 100_000), " "},
+	}
+	for _, test := range tests {
+		out, _ := NewHTMLStripCharFilter().FilterChars(test.text)
+		assert.Equal(t, test.expected, out)
+	}
+}
+
+func TestMarkdownStripCharFilter(t *testing.T) {
+	text := "# Title #\n\n" +
+		"> Quoted **bold** and _em_ text\n" +
+		"- [x] item with `code`\n" +
+		"1. [link](http://example.com) ![alt](img.png) <https://go.dev>\n" +
+		"snake_case_name \\*literal\\*\n" +
+		"2*3*4 and a * b * c, but *(em)* and ***strong em***\n" +
+		"---\n" +
+		"```go\nfmt.Println()\n```\n" +
+		"[ref]: http://example.com\n"
+	out, _ := NewMarkdownStripCharFilter().FilterChars(text)
+	assert.Equal(t, "Title\n\n"+
+		"Quoted bold and em text\n"+
+		"item with code\n"+
+		"link alt https://go.dev\n"+
+		"snake_case_name *literal*\n"+
+		"2*3*4 and a * b * c, but (em) and strong em\n"+
+		"\n"+
+		"\nfmt.Println()\n\n"+
+		"\n", out)
+}
+
+func TestPatternReplaceCharFilter(t *testing.T) {
+	filter := NewPatternReplaceCharFilter(regexp.MustCompile(`(\d+)-(\d+)`), "$1$2")
+	out, offsets := filter.FilterChars("call 555-1234 now")
+	assert.Equal(t, "call 5551234 now", out)
+	assert.Equal(t, 5, offsets.CorrectStart(5))
+	assert.Equal(t, 13, offsets.CorrectEnd(12))
+	assert.Equal(t, 14, offsets.CorrectStart(13))
+}
+
+func TestTokenizationPipeline_CharFilterOffsets(t *testing.T) {
+	analyzer := NewTokenizationPipeline(
+		NewUnicodeTokenizer(),
+		NewLowerCaseTokenizer(),
+	).WithCharFilters(NewMarkdownStripCharFilter(), NewHTMLStripCharFilter())
+
+	text := "## Café <b>con</b> le&ntilde;a\n\nVisit [our **shop**](http://shop.example)"
+	tokens := analyzer.TokenStream(text)
+	assert.Equal(t, []string{"café", "con", "leña", "visit", "our", "shop"}, Terms(tokens))
+	assertOffsets(t, text, tokens, "Café", "con", "le&ntilde;a", "Visit", "our", "shop")
+
+	analysis := analyzer.Analyze(text)
+	assert.Equal(t, "Café con leña\n\nVisit our shop", analysis.CharFiltered)
+	assert.Equal(t, tokens, analysis.Tokens())
+}
+
+func TestHighlighter_CharFilters(t *testing.T) {
+	analyzer := NewTokenizationPipeline(
+		NewUnicodeTokenizer(),
+		NewLowerCaseTokenizer(),
+	).WithCharFilters(NewHTMLStripCharFilter())
+	highlighter := NewHighlighter(analyzer, DefaultHighlightOptions())
+	// Fragments show the text with no markup, which they could cut
+	assert.Equal(t,
+		[]string{"Learn <em>Go</em> con leña"},
+		highlighter.Highlight("go", `<p>Learn <a href="/go"><b>Go</b></a> con le&ntilde;a</p>`),
+	)
+}
+
+func TestAnalyzerRegistry_CharFilters(t *testing.T) {
+	config, err := ParseAnalyzersYAML([]byte(`
+analyzers:
+  pages:
+    char_filters:
+      - html_strip
+      - type: pattern_replace
+        pattern: "(\\d+)-(\\d+)"
+        replacement: "$1$2"
+    tokenizer: standard
+`))
+	assert.NoError(t, err)
+	registry := NewAnalyzerRegistry()
+	assert.NoError(t, registry.Load(config))
+	analyzer, err := registry.Analyzer("pages")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Call", "5551234"}, analyzer.Tokenize("<i>Call</i> 555-1234"))
+
+	assert.Error(t, registry.Define("broken", AnalyzerConfig{
+		CharFilters: []ComponentConfig{
+			{Type: "pattern_replace", Params: ComponentParams{"pattern": "("}},
+		},
+		Tokenizer: ComponentConfig{Type: "standard"},
+	}))
+	assert.Error(t, registry.Define("unknown", AnalyzerConfig{
+		CharFilters: []ComponentConfig{{Type: "unknown"}},
+		Tokenizer:   ComponentConfig{Type: "standard"},
+	}))
+}
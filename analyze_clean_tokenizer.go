@@ -15,15 +15,51 @@ func (c *CleanTokenizer) register(fn cleanFunc) {
 	c.fns = append(c.fns, fn)
 }
 
+func (c *CleanTokenizer) keep(r rune) bool {
+	for _, fn := range c.fns {
+		if fn(r) {
+			return true
+		}
+	}
+	return false
+}
+
 func (c *CleanTokenizer) Tokenize(text string) []string {
 	return strings.FieldsFunc(text, func(r rune) bool {
-		for _, fn := range c.fns {
-			if fn(r) {
-				return false
+		return !c.keep(r)
+	})
+}
+
+func (c *CleanTokenizer) TokenStream(text string) []Token {
+	var (
+		res   []Token
+		start = -1
+	)
+	emit := func(end int) {
+		term := text[start:end]
+		res = append(res, Token{
+			Term:              term,
+			Position:          len(res),
+			PositionIncrement: 1,
+			Start:             start,
+			End:               end,
+			Type:              tokenType(term),
+		})
+		start = -1
+	}
+	for i, r := range text {
+		if c.keep(r) {
+			if start < 0 {
+				start = i
 			}
+		} else if start >= 0 {
+			emit(i)
 		}
-		return true
-	})
+	}
+	if start >= 0 {
+		emit(len(text))
+	}
+	return res
 }
 
 func NewCleanTokenizer(fns ...cleanFunc) CleanTokenizer {
@@ -0,0 +1,53 @@
+package visigoth
+
+// commonGramSeparator joins the terms of common grams, e.g. "the_who".
+const commonGramSeparator = "_"
+
+// CommonGramsFilter adds, for every pair of adjacent tokens at least one of
+// which is a common word, a token joining both, such as "the_who". Placed
+// before a StopWordsFilter with the same words, it keeps phrases made of or
+// including stopwords searchable while single stopwords are still removed.
+//
+// Grams share the position of their first token and span two positions, so
+// the same filter serves at index and query time: a query for "the who"
+// looks for "the_who", and a query for "who" still finds "who".
+type CommonGramsFilter struct {
+	common StopWords
+}
+
+func (c CommonGramsFilter) Filter(tokens []string) []string {
+	stream := make([]Token, len(tokens))
+	for i, term := range tokens {
+		stream[i] = Token{Term: term, Position: i, PositionIncrement: 1, Type: tokenType(term)}
+	}
+	return Terms(c.FilterStream(stream))
+}
+
+func (c CommonGramsFilter) FilterStream(tokens []Token) []Token {
+	res := make([]Token, 0, len(tokens))
+	for i, tok := range tokens {
+		res = append(res, tok)
+		if i+1 == len(tokens) {
+			break
+		}
+		next := tokens[i+1]
+		if next.PositionIncrement != 1 ||
+			!(c.common.Contains(tok.Term) || c.common.Contains(next.Term)) {
+			continue
+		}
+		res = append(res, Token{
+			Term:              tok.Term + commonGramSeparator + next.Term,
+			Position:          tok.Position,
+			PositionIncrement: 0,
+			PositionLength:    2,
+			Start:             tok.Start,
+			End:               next.End,
+			Type:              TokenGram,
+		})
+	}
+	return res
+}
+
+func NewCommonGramsFilter(common StopWords) CommonGramsFilter {
+	return CommonGramsFilter{common: common}
+}
@@ -1,21 +1,71 @@
 package visigoth
 
 type TokenizationPipeline struct {
-	tokenizer Tokenizer
-	filters   []Filter
+	charFilters []CharFilter
+	tokenizer   Tokenizer
+	filters     []Filter
+
+	streamTokenizer StreamTokenizer
+	streamFilters   []StreamFilter
 }
 
 func (p *TokenizationPipeline) Tokenize(text string) []string {
-	var res = p.tokenizer.Tokenize(text)
-	for _, filter := range p.filters {
-		res = filter.Filter(res)
+	return Terms(p.TokenStream(text))
+}
+
+// TokenStream analyzes text keeping positions, offsets and types of tokens.
+// Offsets always refer to text, even if char filters modified it.
+func (p *TokenizationPipeline) TokenStream(text string) []Token {
+	var res = p.tokenize(text)
+	for _, filter := range p.streamFilters {
+		res = filter.FilterStream(res)
+	}
+	return res
+}
+
+// tokenize runs char filters and the tokenizer over text.
+func (p *TokenizationPipeline) tokenize(text string) []Token {
+	if len(p.charFilters) == 0 {
+		return p.streamTokenizer.TokenStream(text)
+	}
+	filtered, offsets := chainCharFilters(text, p.charFilters)
+	res := p.streamTokenizer.TokenStream(filtered)
+	for i := range res {
+		res[i].Start = offsets.CorrectStart(res[i].Start)
+		res[i].End = offsets.CorrectEnd(res[i].End)
 	}
 	return res
 }
 
+// filterChars runs text through the char filters, returning it along with a
+// copy of the pipeline analyzing it as the pipeline does the original text.
+func (p *TokenizationPipeline) filterChars(text string) (string, *TokenizationPipeline) {
+	if len(p.charFilters) == 0 {
+		return text, p
+	}
+	filtered, _ := chainCharFilters(text, p.charFilters)
+	res := *p
+	res.charFilters = nil
+	return filtered, &res
+}
+
+// WithCharFilters returns a copy of the pipeline running text through the
+// given char filters, after any it already had, before tokenizing it.
+func (p *TokenizationPipeline) WithCharFilters(cf ...CharFilter) *TokenizationPipeline {
+	res := *p
+	res.charFilters = append(append([]CharFilter(nil), p.charFilters...), cf...)
+	return &res
+}
+
 func NewTokenizationPipeline(t Tokenizer, f ...Filter) *TokenizationPipeline {
+	sf := make([]StreamFilter, len(f))
+	for i, filter := range f {
+		sf[i] = AdaptFilter(filter)
+	}
 	return &TokenizationPipeline{
-		tokenizer: t,
-		filters:   f,
+		tokenizer:       t,
+		filters:         f,
+		streamTokenizer: AdaptTokenizer(t),
+		streamFilters:   sf,
 	}
 }
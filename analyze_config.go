@@ -0,0 +1,188 @@
+package visigoth
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// AnalyzersConfig declares custom analyzers by name:
+//
+//	analyzers:
+//	  products:
+//	    char_filters:
+//	      - html_strip
+//	    tokenizer: standard
+//	    filters:
+//	      - lowercase
+//	      - type: stopwords
+//	        language: spanish
+//	      - type: edge_ngram
+//	        min_gram: 2
+//	        max_gram: 10
+type AnalyzersConfig struct {
+	Analyzers map[string]AnalyzerConfig `json:"analyzers" yaml:"analyzers"`
+}
+
+// AnalyzerConfig declares an analyzer as char filters, a tokenizer and
+// filters, all of them referring to components registered in an
+// AnalyzerRegistry.
+type AnalyzerConfig struct {
+	CharFilters []ComponentConfig `json:"char_filters,omitempty" yaml:"char_filters"`
+	Tokenizer   ComponentConfig   `json:"tokenizer"              yaml:"tokenizer"`
+	Filters     []ComponentConfig `json:"filters"                yaml:"filters"`
+}
+
+// ComponentConfig names a registered component, such as a tokenizer, along with its
+// parameters. It is written either as the bare component name, or as an
+// object whose "type" is the name and whose other keys are the parameters.
+type ComponentConfig struct {
+	Type   string
+	Params ComponentParams
+}
+
+func (c *ComponentConfig) fromMap(params map[string]any) error {
+	typ, ok := params["type"].(string)
+	if !ok || typ == "" {
+		return fmt.Errorf("component with no 'type': %v", params)
+	}
+	delete(params, "type")
+	c.Type, c.Params = typ, params
+	return nil
+}
+
+func (c *ComponentConfig) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &c.Type); err == nil {
+		c.Params = nil
+		return nil
+	}
+	var params map[string]any
+	if err := json.Unmarshal(data, &params); err != nil {
+		return err
+	}
+	return c.fromMap(params)
+}
+
+func (c ComponentConfig) MarshalJSON() ([]byte, error) {
+	if len(c.Params) == 0 {
+		return json.Marshal(c.Type)
+	}
+	params := make(map[string]any, len(c.Params)+1)
+	for k, v := range c.Params {
+		params[k] = v
+	}
+	params["type"] = c.Type
+	return json.Marshal(params)
+}
+
+func (c *ComponentConfig) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		c.Params = nil
+		return node.Decode(&c.Type)
+	}
+	var params map[string]any
+	if err := node.Decode(&params); err != nil {
+		return err
+	}
+	return c.fromMap(params)
+}
+
+// ComponentParams holds the parameters of a tokenizer or filter, as decoded
+// from JSON or YAML.
+type ComponentParams map[string]any
+
+// Int returns the integer parameter key, or def if missing.
+func (p ComponentParams) Int(key string, def int) (int, error) {
+	switch v := p[key].(type) {
+	case nil:
+		return def, nil
+	case int:
+		return v, nil
+	case float64:
+		if v != float64(int(v)) {
+			return 0, fmt.Errorf("parameter '%s' should be an integer, got %v", key, v)
+		}
+		return int(v), nil
+	default:
+		return 0, fmt.Errorf("parameter '%s' should be an integer, got %v", key, v)
+	}
+}
+
+// Bool returns the boolean parameter key, or def if missing.
+func (p ComponentParams) Bool(key string, def bool) (bool, error) {
+	switch v := p[key].(type) {
+	case nil:
+		return def, nil
+	case bool:
+		return v, nil
+	default:
+		return false, fmt.Errorf("parameter '%s' should be a boolean, got %v", key, v)
+	}
+}
+
+// String returns the string parameter key, or def if missing.
+func (p ComponentParams) String(key string, def string) (string, error) {
+	switch v := p[key].(type) {
+	case nil:
+		return def, nil
+	case string:
+		return v, nil
+	default:
+		return "", fmt.Errorf("parameter '%s' should be a string, got %v", key, v)
+	}
+}
+
+// Strings returns the list of strings parameter key, or nil if missing.
+func (p ComponentParams) Strings(key string) ([]string, error) {
+	switch v := p[key].(type) {
+	case nil:
+		return nil, nil
+	case []string:
+		return v, nil
+	case []any:
+		res := make([]string, len(v))
+		for i, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("parameter '%s' should be a list of strings", key)
+			}
+			res[i] = s
+		}
+		return res, nil
+	default:
+		return nil, fmt.Errorf("parameter '%s' should be a list of strings, got %v", key, v)
+	}
+}
+
+// ParseAnalyzersJSON decodes analyzer definitions in JSON.
+func ParseAnalyzersJSON(data []byte) (AnalyzersConfig, error) {
+	var config AnalyzersConfig
+	err := json.Unmarshal(data, &config)
+	return config, err
+}
+
+// ParseAnalyzersYAML decodes analyzer definitions in YAML.
+func ParseAnalyzersYAML(data []byte) (AnalyzersConfig, error) {
+	var config AnalyzersConfig
+	err := yaml.Unmarshal(data, &config)
+	return config, err
+}
+
+// LoadAnalyzersConfig reads analyzer definitions from a file, in YAML if its
+// extension is ".yaml" or ".yml" and in JSON otherwise.
+func LoadAnalyzersConfig(path string) (AnalyzersConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return AnalyzersConfig{}, err
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return ParseAnalyzersYAML(data)
+	default:
+		return ParseAnalyzersJSON(data)
+	}
+}
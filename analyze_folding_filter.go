@@ -0,0 +1,135 @@
+package visigoth
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// asciiFoldings holds the Latin letters with no decomposition into a base
+// letter plus combining marks.
+var asciiFoldings = map[rune]string{
+	'ß': "ss", 'ẞ': "SS",
+	'æ': "ae", 'Æ': "AE",
+	'œ': "oe", 'Œ': "OE",
+	'ø': "o", 'Ø': "O",
+	'đ': "d", 'Đ': "D",
+	'ð': "d", 'Ð': "D",
+	'ł': "l", 'Ł': "L",
+	'ŀ': "l", 'Ŀ': "L",
+	'ħ': "h", 'Ħ': "H",
+	'þ': "th", 'Þ': "TH",
+	'ŋ': "n", 'Ŋ': "N",
+	'ı': "i", 'ĸ': "k", 'ſ': "s",
+}
+
+// FoldingFilter removes diacritics from the Latin letters of tokens, so that
+// "programación" and "programacion" produce the same term. Letters of other
+// scripts are left untouched.
+type FoldingFilter struct {
+	// ascii folds every Latin letter to its ASCII counterpart, including
+	// compatibility characters such as ligatures or full width letters.
+	ascii bool
+	// preserve lists the runes left untouched, such as "ñ" for Spanish.
+	preserve map[rune]struct{}
+	// preserveOriginal keeps the original token along with the folded one.
+	preserveOriginal bool
+}
+
+func (f FoldingFilter) fold(term string) string {
+	ascii := true
+	for i := 0; i < len(term); i++ {
+		if term[i] >= utf8.RuneSelf {
+			ascii = false
+			break
+		}
+	}
+	if ascii {
+		return term
+	}
+
+	form := norm.NFD
+	if f.ascii {
+		form = norm.NFKD
+	}
+	var buf strings.Builder
+	buf.Grow(len(term))
+	// latin tells whether the last letter is Latin, so that combining marks
+	// left after composing are only removed from Latin letters
+	latin := false
+	for _, r := range norm.NFC.String(term) {
+		if unicode.Is(unicode.Mn, r) {
+			if !latin {
+				buf.WriteRune(r)
+			}
+			continue
+		}
+		latin = unicode.Is(unicode.Latin, r)
+		if _, ok := f.preserve[r]; ok || r < utf8.RuneSelf {
+			buf.WriteRune(r)
+			continue
+		}
+		if folded, ok := asciiFoldings[r]; ok && f.ascii {
+			buf.WriteString(folded)
+			continue
+		}
+		decomposed := form.String(string(r))
+		if base, _ := utf8.DecodeRuneInString(decomposed); !unicode.Is(unicode.Latin, base) {
+			// Marks of other scripts, such as the breve of "й", the dakuten
+			// of "が" or Devanagari vowel signs, are part of the letter
+			buf.WriteRune(r)
+			continue
+		}
+		for _, d := range decomposed {
+			if !unicode.Is(unicode.Mn, d) {
+				buf.WriteRune(d)
+			}
+		}
+	}
+	return buf.String()
+}
+
+func (f FoldingFilter) Filter(tokens []string) []string {
+	r := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		folded := f.fold(token)
+		if f.preserveOriginal && folded != token {
+			r = append(r, token)
+		}
+		r = append(r, folded)
+	}
+	return r
+}
+
+func (f FoldingFilter) FilterStream(tokens []Token) []Token {
+	r := make([]Token, 0, len(tokens))
+	for _, token := range tokens {
+		folded := f.fold(token.Term)
+		if f.preserveOriginal && folded != token.Term {
+			r = append(r, token)
+			token.PositionIncrement = 0
+		}
+		token.Term = folded
+		r = append(r, token)
+	}
+	return r
+}
+
+// NewASCIIFoldingFilter folds Latin letters to ASCII, removing diacritics and
+// expanding letters like "ß" or "æ". When preserveOriginal is set, tokens
+// changed by folding are kept too, at the same position.
+func NewASCIIFoldingFilter(preserveOriginal bool) FoldingFilter {
+	return FoldingFilter{ascii: true, preserveOriginal: preserveOriginal}
+}
+
+// NewDiacriticFoldingFilter removes combining marks from letters, except for
+// the given runes, e.g. NewDiacriticFoldingFilter('ñ', 'Ñ') for Spanish.
+func NewDiacriticFoldingFilter(preserve ...rune) FoldingFilter {
+	f := FoldingFilter{preserve: make(map[rune]struct{}, len(preserve))}
+	for _, r := range preserve {
+		f.preserve[r] = struct{}{}
+	}
+	return f
+}
@@ -0,0 +1,89 @@
+package visigoth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizationFilter(t *testing.T) {
+	decomposed := "café"
+	assert.Equal(t, []string{"café"}, NewNFCFilter().Filter([]string{decomposed}))
+	assert.Equal(t, []string{"file", "x2"}, NewNFKCFilter().Filter([]string{"ﬁle", "x²"}))
+	assert.Equal(t, []string{"ﬁle"}, NewNFCFilter().Filter([]string{"ﬁle"}))
+}
+
+func TestFoldingFilter(t *testing.T) {
+	ascii := NewASCIIFoldingFilter(false)
+	assert.Equal(
+		t,
+		[]string{"programacion", "strasse", "aeroskobing", "lodz", "file", "cafe", "москва"},
+		ascii.Filter(
+			[]string{"programación", "straße", "ærøskøbing", "łódź", "ﬁle", "café", "москва"},
+		),
+	)
+
+	spanish := NewDiacriticFoldingFilter('ñ', 'Ñ')
+	assert.Equal(t,
+		[]string{"cañon", "año", "AÑO", "ﬁn"},
+		spanish.Filter([]string{"cañón", "año", "AÑO", "ﬁn"}),
+	)
+	assert.Equal(t, []string{"ANO"}, NewDiacriticFoldingFilter('ñ').Filter([]string{"AÑO"}))
+
+	// Only marks on Latin letters are removed
+	for _, f := range []FoldingFilter{ascii, NewDiacriticFoldingFilter()} {
+		assert.Equal(t,
+			[]string{"йогурт", "がっこう", "हिंदी", "cafe", "q"},
+			f.Filter([]string{"йогурт", "がっこう", "हिंदी", "café", "q̃"}),
+		)
+	}
+}
+
+func TestFoldingFilter_PreserveOriginal(t *testing.T) {
+	filter := NewASCIIFoldingFilter(true)
+	assert.Equal(
+		t,
+		[]string{"canción", "cancion", "nueva"},
+		filter.Filter([]string{"canción", "nueva"}),
+	)
+
+	tokens := filter.FilterStream([]Token{
+		{Term: "canción", Position: 0, PositionIncrement: 1, Start: 0, End: 8},
+		{Term: "nueva", Position: 1, PositionIncrement: 1, Start: 9, End: 14},
+	})
+	assert.Equal(t, []Token{
+		{Term: "canción", Position: 0, PositionIncrement: 1, Start: 0, End: 8},
+		{Term: "cancion", Position: 0, PositionIncrement: 0, Start: 0, End: 8},
+		{Term: "nueva", Position: 1, PositionIncrement: 1, Start: 9, End: 14},
+	}, tokens)
+}
+
+func TestStopWordsFilter_Folding(t *testing.T) {
+	tokens := []string{"tambien", "también", "programacion"}
+	assert.Equal(
+		t,
+		[]string{"tambien", "programacion"},
+		NewStopWordsFilter(SpanishStopWords).Filter(tokens),
+	)
+	assert.Equal(t,
+		[]string{"programacion"},
+		NewStopWordsFilter(SpanishStopWords, WithStopWordsFolding()).Filter(tokens),
+	)
+}
+
+func TestFoldingFilter_Search(t *testing.T) {
+	analyzer := NewTokenizationPipeline(
+		NewUnicodeTokenizer(),
+		NewLowerCaseTokenizer(),
+		NewASCIIFoldingFilter(false),
+		NewStopWordsFilter(SpanishStopWords, WithStopWordsFolding()),
+	)
+	in := NewMemoryIndex("courses", analyzer)
+	in.Put(NewDocRequest("go", "Programación en Go, también concurrente"))
+	in.Put(NewDocRequest("java", "Programas en Java"))
+
+	results := in.Search("programacion tambien", HitsSearch)
+	assert.Equal(t, 1, results.Len())
+	res, _ := results.Get(0)
+	assert.Equal(t, "go", res.Doc().ID())
+}
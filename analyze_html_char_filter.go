@@ -0,0 +1,173 @@
+package visigoth
+
+import (
+	"html"
+	"strings"
+)
+
+// inlineTags are the HTML elements that do not break words, so they are
+// removed with no trace. Any other tag is replaced with a space.
+var inlineTags = map[string]struct{}{
+	"a": {}, "abbr": {}, "b": {}, "bdi": {}, "bdo": {}, "cite": {}, "code": {},
+	"del": {}, "dfn": {}, "em": {}, "font": {}, "i": {}, "ins": {}, "kbd": {},
+	"mark": {}, "q": {}, "s": {}, "samp": {}, "small": {}, "span": {},
+	"strong": {}, "sub": {}, "sup": {}, "time": {}, "u": {}, "var": {}, "wbr": {},
+}
+
+// rawTextTags are the HTML elements whose content is not text.
+var rawTextTags = map[string]struct{}{"script": {}, "style": {}}
+
+// HTMLStripCharFilter removes HTML tags, comments and the content of script and
+// style elements, and decodes character references such as "&amp;" or
+// "&#233;".
+type HTMLStripCharFilter struct{}
+
+func (h HTMLStripCharFilter) FilterChars(text string) (string, OffsetMap) {
+	b := newOffsetsBuilder(text)
+	// unclosed is set once a tag or comment runs to the end of text with no
+	// closing ">" or "-->", as any other would, so the rest is kept as text
+	// rather than scanned again for every "<"
+	var unclosed bool
+	for i := 0; i < len(text); {
+		switch text[i] {
+		case '<':
+			if unclosed {
+				i++
+				continue
+			}
+			end, name, closing := scanTag(text, i)
+			if end == unclosedTag {
+				unclosed = true
+			}
+			if end < 0 {
+				i++
+				continue
+			}
+			if _, ok := rawTextTags[name]; ok && !closing {
+				end = skipRawText(text, end, name)
+			}
+			if _, ok := inlineTags[name]; ok {
+				b.replace(i, end, "")
+			} else {
+				b.replace(i, end, " ")
+			}
+			i = end
+		case '&':
+			end, decoded := scanCharRef(text, i)
+			if end < 0 {
+				i++
+				continue
+			}
+			b.replace(i, end, decoded)
+			i = end
+		default:
+			i++
+		}
+	}
+	return b.finish()
+}
+
+// Ends returned by scanTag when there is no tag: either the text is not a
+// tag, or the tag is never closed.
+const (
+	notATag     = -1
+	unclosedTag = -2
+)
+
+// scanTag returns the end of the tag, comment or declaration starting at
+// start, along with the lowercase tag name and whether it is a closing tag.
+// The end is notATag or unclosedTag if there is no tag at start.
+func scanTag(text string, start int) (end int, name string, closing bool) {
+	rest := text[start:]
+	if strings.HasPrefix(rest, "<!--") {
+		if i := strings.Index(rest[4:], "-->"); i >= 0 {
+			return start + 4 + i + 3, "", false
+		}
+		return unclosedTag, "", false
+	}
+	if len(rest) < 2 {
+		return notATag, "", false
+	}
+	i := 1
+	switch c := rest[1]; {
+	case c == '!' || c == '?':
+		if j := strings.IndexByte(rest, '>'); j >= 0 {
+			return start + j + 1, "", false
+		}
+		return unclosedTag, "", false
+	case c == '/':
+		closing = true
+		i++
+	}
+	nameStart := i
+	for i < len(rest) && isTagNameByte(rest[i]) {
+		i++
+	}
+	if i == nameStart || !isASCIILetter(rest[nameStart]) {
+		return notATag, "", false
+	}
+	name = strings.ToLower(rest[nameStart:i])
+
+	var quote byte
+	for ; i < len(rest); i++ {
+		c := rest[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '>':
+			return start + i + 1, name, closing
+		}
+	}
+	return unclosedTag, "", false
+}
+
+// skipRawText returns the end of the closing tag of a raw text element, or the
+// end of text if it is not closed.
+func skipRawText(text string, from int, name string) int {
+	closing := "</" + name
+	for i := from; i < len(text); i++ {
+		if text[i] != '<' || !strings.EqualFold(text[i:min(i+len(closing), len(text))], closing) {
+			continue
+		}
+		end, tag, isClosing := scanTag(text, i)
+		if end == unclosedTag {
+			break
+		}
+		if end >= 0 && tag == name && isClosing {
+			return end
+		}
+	}
+	return len(text)
+}
+
+// scanCharRef returns the end and the decoded value of the character reference
+// starting at start, or a negative end if there is none.
+func scanCharRef(text string, start int) (int, string) {
+	const maxLen = 32
+	end := strings.IndexByte(text[start:min(start+maxLen, len(text))], ';')
+	if end < 0 {
+		return -1, ""
+	}
+	ref := text[start : start+end+1]
+	decoded := html.UnescapeString(ref)
+	if decoded == ref {
+		return -1, ""
+	}
+	return start + end + 1, decoded
+}
+
+func isASCIILetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isTagNameByte(c byte) bool {
+	return isASCIILetter(c) || (c >= '0' && c <= '9') || c == '-' || c == ':'
+}
+
+func NewHTMLStripCharFilter() HTMLStripCharFilter {
+	return HTMLStripCharFilter{}
+}
@@ -0,0 +1,132 @@
+package visigoth
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	// ngramProfileSize is the number of most frequent n-grams kept per profile.
+	ngramProfileSize = 400
+	ngramMaxLength   = 3
+)
+
+// ngramProfile maps every n-gram to its rank in the frequency ordered profile.
+type ngramProfile map[string]int
+
+// LanguageDetector identifies the language of texts by comparing their
+// character n-gram profile against the profile of every known language, as
+// described by Cavnar & Trenkle in "N-Gram-Based Text Categorization" (1994).
+//
+// Profiles are built from the built-in samples and stopword lists of each
+// language, so detection runs fully offline.
+type LanguageDetector struct {
+	languages []Language
+	profiles  map[Language]ngramProfile
+}
+
+// Detect returns the language whose profile is closest to the one of text, or
+// an empty Language if text contains no letters.
+func (d *LanguageDetector) Detect(text string) Language {
+	profile := newNgramProfile(text)
+	if len(profile) == 0 {
+		return ""
+	}
+
+	var (
+		best     Language
+		bestDist = -1
+	)
+	for _, lang := range d.languages {
+		dist := outOfPlace(profile, d.profiles[lang])
+		if bestDist < 0 || dist < bestDist {
+			best, bestDist = lang, dist
+		}
+	}
+	return best
+}
+
+// Languages returns the languages the detector is able to identify.
+func (d *LanguageDetector) Languages() []Language {
+	return d.languages
+}
+
+// outOfPlace sums, for every n-gram of the document, how far its rank is from
+// the rank in the language profile. N-grams missing from the language profile
+// are given the maximum penalty.
+func outOfPlace(doc, lang ngramProfile) int {
+	var dist int
+	for gram, rank := range doc {
+		if langRank, ok := lang[gram]; ok {
+			dist += abs(rank - langRank)
+		} else {
+			dist += ngramProfileSize
+		}
+	}
+	return dist
+}
+
+func newNgramProfile(text string) ngramProfile {
+	counts := make(map[string]int)
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	for _, word := range words {
+		runes := []rune("_" + word + "_")
+		for n := 1; n <= ngramMaxLength; n++ {
+			for i := 0; i+n <= len(runes); i++ {
+				gram := string(runes[i : i+n])
+				if gram == "_" {
+					continue
+				}
+				counts[gram]++
+			}
+		}
+	}
+
+	grams := make([]string, 0, len(counts))
+	for gram := range counts {
+		grams = append(grams, gram)
+	}
+	sort.Slice(grams, func(i, j int) bool {
+		if counts[grams[i]] != counts[grams[j]] {
+			return counts[grams[i]] > counts[grams[j]]
+		}
+		return grams[i] < grams[j]
+	})
+	if len(grams) > ngramProfileSize {
+		grams = grams[:ngramProfileSize]
+	}
+
+	profile := make(ngramProfile, len(grams))
+	for rank, gram := range grams {
+		profile[gram] = rank
+	}
+	return profile
+}
+
+// NewLanguageDetector returns a detector for the given languages, or for
+// every language with a built-in profile if none is given. Languages with no
+// built-in profile are ignored.
+func NewLanguageDetector(langs ...Language) *LanguageDetector {
+	if len(langs) == 0 {
+		langs = Languages()
+	}
+	d := &LanguageDetector{profiles: make(map[Language]ngramProfile, len(langs))}
+	for _, lang := range langs {
+		sample, ok := languageSamples[lang]
+		if !ok {
+			continue
+		}
+		var buf strings.Builder
+		buf.WriteString(sample)
+		for word := range languageStopWords[lang] {
+			buf.WriteString(" ")
+			buf.WriteString(word)
+		}
+		d.languages = append(d.languages, lang)
+		d.profiles[lang] = newNgramProfile(buf.String())
+	}
+	return d
+}
@@ -0,0 +1,160 @@
+package visigoth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLanguageDetector_Detect(t *testing.T) {
+	detector := NewLanguageDetector()
+
+	tests := []struct {
+		text     string
+		expected Language
+	}{
+		{text: "Curso de programación en Java para principiantes", expected: Spanish},
+		{text: "Los niños estaban jugando en el parque con sus amigos", expected: Spanish},
+		{text: "An introduction to programming with the Java language", expected: English},
+		{text: "The weather was nice so we went for a walk in the park", expected: English},
+		{
+			text:     "Un cours de programmation pour les débutants qui veulent apprendre",
+			expected: French,
+		},
+		{text: "Курс программирования на языке Java для начинающих", expected: Russian},
+		{text: "Jag har inte läst någon bok om historia än", expected: Swedish},
+		{text: "Det er ikke lett å lære et nytt språk", expected: Norwegian},
+		{text: "Ez egy könnyen olvasható könyv a magyar történelemről", expected: Hungarian},
+		{text: "1234 !!", expected: ""},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, detector.Detect(test.text), "text '%s'", test.text)
+	}
+}
+
+func newTestMultilingualIndex(t *testing.T) *MemoryIndex {
+	spanish, err := NewLanguageAnalyzer(Spanish)
+	assert.NoError(t, err)
+	english, err := NewLanguageAnalyzer(English)
+	assert.NoError(t, err)
+
+	analyzer := NewMultilingualAnalyzer(
+		NewTokenizationPipeline(NewKeepAlphanumericTokenizer(), NewLowerCaseTokenizer()),
+		map[Language]*TokenizationPipeline{Spanish: spanish, English: english},
+	)
+	in := NewMemoryIndex("courses", analyzer)
+	in.Put(NewDocRequest("es-java", "Curso de programación en Java para principiantes"))
+	in.Put(NewDocRequest("en-java", "An introduction to programming with the Java language"))
+	in.Put(NewDocRequest("en-go", "Learn how to write programs in Go"))
+	in.Put(NewDocRequest("tagged", "Programas en Go").WithLanguage(Spanish))
+	return in
+}
+
+func TestMemoryIndex_Put_Multilingual(t *testing.T) {
+	in := newTestMultilingualIndex(t)
+
+	assert.Equal(t, Spanish, in.Document(0).Language)
+	assert.Equal(t, English, in.Document(1).Language)
+	assert.Equal(t, English, in.Document(2).Language)
+	assert.Equal(t, Spanish, in.Document(3).Language, "explicit language should not be overridden")
+
+	// Each document is analyzed with the chain of its language
+	assert.Contains(t, in.Indexed("program"), 0)
+	assert.Contains(t, in.Indexed("program"), 1)
+	assert.NotContains(t, in.Indexed("the"), 1, "english stopwords should be removed")
+}
+
+func TestMemoryIndex_SearchWith_Languages(t *testing.T) {
+	in := newTestMultilingualIndex(t)
+
+	ids := func(results []SearchResult) []string {
+		var res []string
+		for _, r := range results {
+			res = append(res, r.Document.ID())
+		}
+		return res
+	}
+
+	results := in.Search("java", HitsSearch)
+	assert.ElementsMatch(t, []string{"es-java", "en-java"}, ids(results))
+
+	results = in.SearchWith("java", HitsSearch, WithLanguages(English))
+	assert.Equal(t, []string{"en-java"}, ids(results))
+
+	results = in.SearchWith("programas", LinearSearch, WithLanguages(Spanish))
+	assert.ElementsMatch(t, []string{"es-java", "tagged"}, ids(results))
+
+	results = in.SearchWith("java", HitsSearch, WithLanguages(French))
+	assert.Empty(t, results)
+
+	// Documents tagged with a language with no analysis chain are analyzed
+	// with the fallback one, and so searched along with undetected ones
+	german := Language("german")
+	in.Put(NewDocRequest("de-java", "Java für Anfänger").WithLanguage(german))
+	results = in.Search("anfänger", HitsSearch)
+	assert.Equal(t, []string{"de-java"}, ids(results))
+	assert.Equal(t, german, results[0].Document.Language)
+	results = in.SearchWith("anfänger", HitsSearch, WithLanguages(german))
+	assert.Equal(t, []string{"de-java"}, ids(results))
+}
+
+func TestMemoryIndex_SearchWith_Languages_NoopAll(t *testing.T) {
+	in := newTestMultilingualIndex(t)
+
+	ids := func(results []SearchResult) []string {
+		var res []string
+		for _, r := range results {
+			res = append(res, r.Document.ID())
+		}
+		return res
+	}
+
+	all := make([]string, 0, in.Len())
+	for i := 0; i < in.Len(); i++ {
+		all = append(all, in.Document(i).ID())
+	}
+
+	// Every document is returned once, from the pass of its language
+	results := in.Search("", NoopAllSearch)
+	assert.ElementsMatch(t, all, ids(results))
+
+	english := in.SearchWith("", NoopAllSearch, WithLanguages(English))
+	assert.NotEmpty(t, english)
+	for _, r := range english {
+		assert.Equal(t, English, r.Document.Language)
+	}
+	results = in.SearchWith("", NoopAllSearch, WithLanguages(English, English))
+	assert.Equal(t, ids(english), ids(results))
+	spanish := in.SearchWith("", NoopAllSearch, WithLanguages(Spanish))
+	for _, r := range spanish {
+		assert.Equal(t, Spanish, r.Document.Language)
+	}
+	assert.Len(t, all, len(english)+len(spanish)+
+		len(in.SearchWith("", NoopAllSearch, WithLanguages(""))))
+	assert.Empty(t, in.SearchWith("", NoopAllSearch, WithLanguages(French)))
+
+	mono := NewMemoryIndex("mono", NewTokenizationPipeline(
+		NewWhitespaceTokenizer(), NewLowerCaseTokenizer()))
+	mono.Put(NewDocRequest("es", "hola").WithLanguage(Spanish))
+	mono.Put(NewDocRequest("en", "hello").WithLanguage(English))
+	assert.ElementsMatch(t, []string{"es", "en"}, ids(mono.Search("", NoopAllSearch)))
+	results = mono.SearchWith("", NoopAllSearch, WithLanguages(English))
+	assert.Equal(t, []string{"en"}, ids(results))
+}
+
+func Test_IndexRepo_SearchWith_Languages(t *testing.T) {
+	repo := newTestIndexRepo()
+	repo.Put("dedos", NewDocRequest("pulgar", "este fue a por huevos").WithLanguage(Spanish))
+	repo.Put("dedos", NewDocRequest("thumb", "this one went for eggs huevos").WithLanguage(English))
+
+	stream, err := repo.SearchWith("dedos", "huevos", HitsSearch, WithLanguages(Spanish))
+	assert.NoError(t, err)
+
+	var found []string
+	for stream.Next() {
+		found = append(found, stream.Data().Document.ID())
+		assert.Equal(t, Spanish, stream.Data().Document.Language)
+	}
+	assert.Equal(t, []string{"pulgar"}, found)
+}
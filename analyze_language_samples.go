@@ -0,0 +1,73 @@
+package visigoth
+
+// languageSamples are short general-purpose texts used, along with stopword
+// lists, to build the n-gram profile of every language.
+var languageSamples = map[Language]string{
+	English: `The quick brown fox jumps over the lazy dog while the children play
+in the garden. Learning a new programming language takes time, patience and
+a lot of practice. Most people think that search engines are simple, but
+building one requires knowledge about text analysis, indexing and ranking.
+This course teaches you how to write software that is easy to read and to
+maintain. We were walking through the city when it started raining, so we
+went into a small coffee shop and waited there for hours. Which of these
+books would you recommend to someone who has never read anything about
+history? Nothing is more important than the health of your family.`,
+
+	French: `Le renard brun rapide saute par-dessus le chien paresseux pendant que
+les enfants jouent dans le jardin. Apprendre un nouveau langage de
+programmation demande du temps, de la patience et beaucoup de pratique. La
+plupart des gens pensent que les moteurs de recherche sont simples, mais en
+construire un exige des connaissances sur l'analyse de texte, l'indexation
+et le classement. Ce cours vous apprend à écrire des logiciels faciles à lire
+et à maintenir. Nous nous promenions dans la ville quand il a commencé à
+pleuvoir, alors nous sommes entrés dans un petit café où nous avons attendu
+pendant des heures. Rien n'est plus important que la santé de votre famille.`,
+
+	Hungarian: `A gyors barna róka átugrik a lusta kutya felett, miközben a gyerekek
+a kertben játszanak. Egy új programozási nyelv megtanulása időt, türelmet
+és sok gyakorlást igényel. A legtöbb ember azt gondolja, hogy a keresőmotorok
+egyszerűek, de egy ilyen építéséhez ismerni kell a szövegelemzést, az
+indexelést és a rangsorolást. Ez a tanfolyam megtanítja, hogyan írj könnyen
+olvasható és karbantartható szoftvert. Éppen a városban sétáltunk, amikor
+elkezdett esni az eső, ezért bementünk egy kis kávézóba, és ott vártunk
+órákig. Semmi sem fontosabb, mint a családod egészsége.`,
+
+	Norwegian: `Den raske brune reven hopper over den late hunden mens barna leker i
+hagen. Å lære et nytt programmeringsspråk tar tid, krever tålmodighet og mye
+øving. De fleste tror at søkemotorer er enkle, men å bygge en krever kunnskap
+om tekstanalyse, indeksering og rangering. Dette kurset lærer deg hvordan du
+skriver programvare som er lett å lese og vedlikeholde. Vi gikk gjennom byen
+da det begynte å regne, så vi gikk inn på en liten kafé og ventet der i
+flere timer. Hvilken av disse bøkene vil du anbefale til noen som aldri har
+lest noe om historie? Ingenting er viktigere enn helsen til familien din.`,
+
+	Russian: `Быстрая коричневая лиса прыгает через ленивую собаку, пока дети играют
+в саду. Изучение нового языка программирования требует времени, терпения и
+большой практики. Большинство людей думает, что поисковые системы просты, но
+чтобы построить такую систему, нужны знания об анализе текста, индексации и
+ранжировании. Этот курс научит вас писать программы, которые легко читать и
+поддерживать. Мы гуляли по городу, когда пошёл дождь, поэтому мы зашли в
+маленькое кафе и ждали там несколько часов. Нет ничего важнее здоровья вашей
+семьи.`,
+
+	Spanish: `El rápido zorro marrón salta sobre el perro perezoso mientras los niños
+juegan en el jardín. Aprender un nuevo lenguaje de programación requiere
+tiempo, paciencia y mucha práctica. La mayoría de la gente piensa que los
+motores de búsqueda son sencillos, pero construir uno exige conocimientos de
+análisis de texto, indexación y ordenación. Este curso te enseña a escribir
+programas fáciles de leer y de mantener. Estábamos paseando por la ciudad
+cuando empezó a llover, así que entramos en una pequeña cafetería y esperamos
+allí durante horas. ¿Cuál de estos libros le recomendarías a alguien que
+nunca ha leído nada sobre historia? Nada es más importante que la salud de
+tu familia.`,
+
+	Swedish: `Den snabba bruna räven hoppar över den lata hunden medan barnen leker i
+trädgården. Att lära sig ett nytt programspråk tar tid och kräver tålamod och
+mycket övning. De flesta tror att sökmotorer är enkla, men att bygga en
+kräver kunskap om textanalys, indexering och rangordning. Den här kursen lär
+dig hur man skriver program som är lätta att läsa och underhålla. Vi gick
+genom staden när det började regna, så vi gick in på ett litet kafé och
+väntade där i flera timmar. Vilken av dessa böcker skulle du rekommendera
+till någon som aldrig har läst något om historia? Ingenting är viktigare än
+din familjs hälsa.`,
+}
@@ -20,6 +20,15 @@ func (l LowerCaseFilter) Filter(tokens []string) []string {
 	return r
 }
 
+func (l LowerCaseFilter) FilterStream(tokens []Token) []Token {
+	r := make([]Token, len(tokens))
+	for i, token := range tokens {
+		token.Term = strings.ToLower(token.Term)
+		r[i] = token
+	}
+	return r
+}
+
 func NewLowerCaseTokenizer() LowerCaseFilter {
 	return LowerCaseFilter{}
 }
@@ -0,0 +1,105 @@
+package visigoth
+
+import (
+	"regexp"
+	"unicode"
+	"unicode/utf8"
+)
+
+// markdownPatterns strip Markdown syntax, in order, keeping the first
+// submatch of every match, if any. Block syntax goes first so that list
+// markers are not mistaken for emphasis.
+var markdownPatterns = []stripPattern{
+	// Code fences, keeping the code
+	{pattern: regexp.MustCompile("(?m)^[ \t]*(?:```|~~~)[^\n]*$")},
+	// Link reference definitions
+	{pattern: regexp.MustCompile(`(?m)^[ \t]{0,3}\[[^\]\n]+\]:[ \t]*\S+[^\n]*$`)},
+	// Horizontal rules
+	{pattern: regexp.MustCompile(`(?m)^[ \t]{0,3}(?:[-*_][ \t]*){3,}$`)},
+	// ATX headings, leading and closing hashes
+	{pattern: regexp.MustCompile(`(?m)^[ \t]{0,3}#{1,6}[ \t]+|[ \t]+#+[ \t]*$`)},
+	// Setext heading underlines
+	{pattern: regexp.MustCompile(`(?m)^[ \t]{0,3}(?:=+|-+)[ \t]*$`)},
+	// Block quotes
+	{pattern: regexp.MustCompile(`(?m)^[ \t]{0,3}(?:>[ \t]?)+`)},
+	// List markers and task boxes
+	{pattern: regexp.MustCompile(`(?m)^[ \t]*(?:[-*+]|\d{1,9}[.)])[ \t]+(?:\[[ xX]\][ \t]+)?`)},
+	// Images and links, keeping the text, and autolinks, keeping the address
+	{pattern: regexp.MustCompile(
+		`!?\[([^\]\n]*)\]\([^)\n]*\)|!?\[([^\]\n]+)\]\[[^\]\n]*\]|<((?:https?|ftp|mailto):[^>\s]+)>`,
+	)},
+	// Backslash escapes, keeping the escaped character, and then emphasis,
+	// strikethrough and inline code, keeping the text. Both go together so
+	// that escaped characters are not taken for emphasis.
+	{pattern: regexp.MustCompile(
+		"\\\\([\\\\`*_{}\\[\\]()#+\\-.!|>~])|" +
+			`\*\*\*(\S[^\n]*?)\*\*\*|\*\*(\S[^\n]*?)\*\*|\*(\S[^*\n]*?)\*|` +
+			`___(\S[^\n]*?)___|__(\S[^\n]*?)__|\b_(\S[^_\n]*?)_\b|~~(\S[^\n]*?)~~|` +
+			"`+([^`\n]+)`+",
+	), accept: isEmphasis},
+}
+
+// isEmphasis tells whether a match of emphasis opens and closes with
+// delimiter runs which can, following the CommonMark rules for "_", which
+// are applied to "*" too so that expressions like 2*3*4 are kept. Any other
+// match is accepted.
+func isEmphasis(text string, match []int) bool {
+	delim := text[match[0]]
+	if delim != '*' && delim != '_' {
+		return true
+	}
+	openEnd := match[0]
+	for openEnd < match[1] && text[openEnd] == delim {
+		openEnd++
+	}
+	closeStart := match[1]
+	for closeStart > openEnd && text[closeStart-1] == delim {
+		closeStart--
+	}
+
+	left, right, before, _ := flanking(text, match[0], openEnd)
+	if !left || (right && !isPunctuation(before)) {
+		return false
+	}
+	left, right, _, after := flanking(text, closeStart, match[1])
+	return right && (!left || isPunctuation(after))
+}
+
+// flanking tells whether the delimiter run between start and end is left and
+// right-flanking, along with the runes around it, the start and end of the
+// text counting as whitespace.
+func flanking(text string, start, end int) (left, right bool, before, after rune) {
+	before, after = ' ', ' '
+	if start > 0 {
+		before, _ = utf8.DecodeLastRuneInString(text[:start])
+	}
+	if end < len(text) {
+		after, _ = utf8.DecodeRuneInString(text[end:])
+	}
+	left = !unicode.IsSpace(after) &&
+		(!isPunctuation(after) || unicode.IsSpace(before) || isPunctuation(before))
+	right = !unicode.IsSpace(before) &&
+		(!isPunctuation(before) || unicode.IsSpace(after) || isPunctuation(after))
+	return left, right, before, after
+}
+
+func isPunctuation(r rune) bool {
+	return unicode.IsPunct(r) || unicode.IsSymbol(r)
+}
+
+// MarkdownStripCharFilter removes Markdown syntax, keeping the text of
+// headings, lists, quotes, links, images alt texts, emphasis and code. HTML
+// embedded in Markdown is left as is, chain an HTMLStripCharFilter for it.
+type MarkdownStripCharFilter struct{}
+
+func (m MarkdownStripCharFilter) FilterChars(text string) (string, OffsetMap) {
+	filters := make([]CharFilter, len(markdownPatterns))
+	for i, pattern := range markdownPatterns {
+		filters[i] = pattern
+	}
+	return chainCharFilters(text, filters)
+}
+
+func NewMarkdownStripCharFilter() MarkdownStripCharFilter {
+	return MarkdownStripCharFilter{}
+}
@@ -0,0 +1,95 @@
+package visigoth
+
+import "sort"
+
+// languageTokenizer is implemented by tokenizers whose analysis depends on the
+// language of the text.
+type languageTokenizer interface {
+	tokenizer
+	Detect(text string) Language
+	TokenizeLanguage(text string, lang Language) []string
+	TokenStreamLanguage(text string, lang Language) []Token
+	Languages() []Language
+}
+
+// MultilingualAnalyzer routes text to the analysis chain of its language.
+// Languages are identified with a LanguageDetector, and texts in languages
+// with no analysis chain configured go through the fallback one.
+type MultilingualAnalyzer struct {
+	detector  *LanguageDetector
+	analyzers map[Language]*TokenizationPipeline
+	fallback  *TokenizationPipeline
+	languages []Language
+}
+
+func (m *MultilingualAnalyzer) Detect(text string) Language {
+	lang := m.detector.Detect(text)
+	if _, ok := m.analyzers[lang]; !ok {
+		return ""
+	}
+	return lang
+}
+
+func (m *MultilingualAnalyzer) Tokenize(text string) []string {
+	return m.TokenizeLanguage(text, m.Detect(text))
+}
+
+func (m *MultilingualAnalyzer) TokenizeLanguage(text string, lang Language) []string {
+	return m.Analyzer(lang).Tokenize(text)
+}
+
+func (m *MultilingualAnalyzer) TokenStream(text string) []Token {
+	return m.TokenStreamLanguage(text, m.Detect(text))
+}
+
+func (m *MultilingualAnalyzer) TokenStreamLanguage(text string, lang Language) []Token {
+	return m.Analyzer(lang).TokenStream(text)
+}
+
+// Analyzer returns the analysis chain of the language, or the fallback one.
+func (m *MultilingualAnalyzer) Analyzer(lang Language) *TokenizationPipeline {
+	if analyzer, ok := m.analyzers[lang]; ok {
+		return analyzer
+	}
+	return m.fallback
+}
+
+// Languages returns the languages with an analysis chain configured.
+func (m *MultilingualAnalyzer) Languages() []Language {
+	return m.languages
+}
+
+// NewLanguageAnalyzer returns the standard analysis chain of a language:
+// alphanumeric tokenization, lower casing, stopword removal and stemming.
+func NewLanguageAnalyzer(lang Language) (*TokenizationPipeline, error) {
+	stemmer, err := NewStemmer(lang, true)
+	if err != nil {
+		return nil, err
+	}
+	return NewTokenizationPipeline(
+		NewKeepAlphanumericTokenizer(),
+		NewLowerCaseTokenizer(),
+		NewStopWordsFilter(languageStopWords[lang]),
+		stemmer,
+	), nil
+}
+
+// NewMultilingualAnalyzer returns an analyzer routing texts to the given
+// analysis chains. Only configured languages with a built-in n-gram profile
+// are detected, others must be tagged explicitly in the DocRequest.
+func NewMultilingualAnalyzer(
+	fallback *TokenizationPipeline,
+	analyzers map[Language]*TokenizationPipeline,
+) *MultilingualAnalyzer {
+	langs := make([]Language, 0, len(analyzers))
+	for lang := range analyzers {
+		langs = append(langs, lang)
+	}
+	sort.Slice(langs, func(i, j int) bool { return langs[i] < langs[j] })
+	return &MultilingualAnalyzer{
+		detector:  NewLanguageDetector(langs...),
+		analyzers: analyzers,
+		fallback:  fallback,
+		languages: langs,
+	}
+}
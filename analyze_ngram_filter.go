@@ -0,0 +1,108 @@
+package visigoth
+
+import "unicode/utf8"
+
+// ngrams calls emit with the byte offsets of every n-gram of s, counted in
+// runes, ordered by start and length. Edge n-grams all start at the
+// beginning of s.
+func ngrams(s string, minGram, maxGram int, edge bool, emit func(start, end int)) {
+	bounds := make([]int, 0, len(s)+1)
+	for i := range s {
+		bounds = append(bounds, i)
+	}
+	bounds = append(bounds, len(s))
+	runes := len(bounds) - 1
+
+	for start := 0; start < runes; start++ {
+		if edge && start > 0 {
+			break
+		}
+		for n := minGram; n <= maxGram && start+n <= runes; n++ {
+			emit(bounds[start], bounds[start+n])
+		}
+	}
+}
+
+// gramSizes makes sure 1 <= minGram <= maxGram.
+func gramSizes(minGram, maxGram int) (int, int) {
+	minGram = max(minGram, 1)
+	return minGram, max(minGram, maxGram)
+}
+
+// NGramFilter replaces every token with its character n-grams, so that parts
+// of words such as "gram" in "programación" can be matched. All the n-grams of
+// a token share its position and offsets.
+type NGramFilter struct {
+	minGram, maxGram int
+	edge             bool
+	// preserveOriginal keeps the token itself before its n-grams, so that
+	// tokens shorter than minGram or longer than maxGram are still indexed.
+	preserveOriginal bool
+}
+
+func (f NGramFilter) Filter(tokens []string) []string {
+	res := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		res = append(res, Terms(f.grams(Token{Term: token}))...)
+	}
+	return res
+}
+
+func (f NGramFilter) FilterStream(tokens []Token) []Token {
+	var (
+		res   = make([]Token, 0, len(tokens))
+		carry int
+	)
+	for _, tok := range tokens {
+		grams := f.grams(tok)
+		if len(grams) == 0 {
+			carry += tok.PositionIncrement
+			continue
+		}
+		grams[0].PositionIncrement += carry
+		carry = 0
+		res = append(res, grams...)
+	}
+	return res
+}
+
+func (f NGramFilter) grams(tok Token) []Token {
+	var res []Token
+	if tok.Keyword || (f.preserveOriginal && !f.isGram(tok.Term)) {
+		res = append(res, tok)
+		if tok.Keyword {
+			return res
+		}
+	}
+	ngrams(tok.Term, f.minGram, f.maxGram, f.edge, func(start, end int) {
+		gram := tok
+		gram.Term = tok.Term[start:end]
+		if len(res) > 0 {
+			gram.PositionIncrement = 0
+		}
+		res = append(res, gram)
+	})
+	return res
+}
+
+// isGram reports whether term is one of its own n-grams.
+func (f NGramFilter) isGram(term string) bool {
+	n := utf8.RuneCountInString(term)
+	return n >= f.minGram && n <= f.maxGram
+}
+
+// NewNGramFilter returns a filter producing n-grams from minGram to maxGram
+// runes long. When preserveOriginal is set, tokens are kept as well. Sizes
+// are adjusted so that 1 <= minGram <= maxGram.
+func NewNGramFilter(minGram, maxGram int, preserveOriginal bool) NGramFilter {
+	minGram, maxGram = gramSizes(minGram, maxGram)
+	return NGramFilter{minGram: minGram, maxGram: maxGram, preserveOriginal: preserveOriginal}
+}
+
+// NewEdgeNGramFilter returns a filter producing the prefixes of tokens from
+// minGram to maxGram runes long, which is what search-as-you-type needs.
+func NewEdgeNGramFilter(minGram, maxGram int, preserveOriginal bool) NGramFilter {
+	f := NewNGramFilter(minGram, maxGram, preserveOriginal)
+	f.edge = true
+	return f
+}
@@ -0,0 +1,77 @@
+package visigoth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNGramTokenizer(t *testing.T) {
+	tokenizer := NewNGramTokenizer(2, 3)
+	assert.Equal(t,
+		[]string{"añ", "año", "ño", "go"},
+		tokenizer.Tokenize("año, go! a"),
+	)
+
+	tokens := tokenizer.TokenStream("x añ")
+	assert.Equal(t, []Token{
+		{Term: "añ", Position: 0, PositionIncrement: 1, Start: 2, End: 5, Type: TokenWord},
+	}, tokens)
+
+	edge := NewEdgeNGramTokenizer(1, 3)
+	assert.Equal(t, []string{"s", "sk", "sku", "1", "12"}, edge.Tokenize("sku 12"))
+}
+
+func TestNGramTokenizer_Sizes(t *testing.T) {
+	assert.Equal(t, []string{"a", "b"}, NewNGramTokenizer(0, -1).Tokenize("ab"))
+	assert.Equal(t, []string{"abc"}, NewNGramTokenizer(3, 1).Tokenize("abc"))
+}
+
+func TestNGramFilter(t *testing.T) {
+	filter := NewNGramFilter(3, 4, false)
+	assert.Equal(t, []string{"cod", "code", "ode"}, filter.Filter([]string{"code", "go"}))
+
+	tokens := filter.FilterStream([]Token{
+		{Term: "go", Position: 0, PositionIncrement: 1, Start: 0, End: 2},
+		{Term: "code", Position: 1, PositionIncrement: 1, Start: 3, End: 7},
+	})
+	assert.Equal(t, []Token{
+		{Term: "cod", Position: 1, PositionIncrement: 2, Start: 3, End: 7},
+		{Term: "code", Position: 1, PositionIncrement: 0, Start: 3, End: 7},
+		{Term: "ode", Position: 1, PositionIncrement: 0, Start: 3, End: 7},
+	}, tokens, "n-grams should share the position of their token")
+
+	preserve := NewNGramFilter(3, 3, true)
+	assert.Equal(t,
+		[]string{"go", "code", "cod", "ode", "sku"},
+		preserve.Filter([]string{"go", "code", "sku"}),
+	)
+
+	edge := NewEdgeNGramFilter(2, 10, false)
+	assert.Equal(t, []string{"pr", "pro", "prog"}, edge.Filter([]string{"prog"}))
+}
+
+func TestNGramFilter_Keyword(t *testing.T) {
+	analyzer := NewTokenizationPipeline(
+		NewKeepAlphanumericTokenizer(),
+		NewKeywordMarkerFilter("golang"),
+		NewEdgeNGramFilter(2, 3, false),
+	)
+	assert.Equal(t, []string{"golang", "ja", "jav"}, analyzer.Tokenize("golang java"))
+}
+
+func TestNGramFilter_Search(t *testing.T) {
+	builder := NewMemoryIndexBuilder(NewTokenizationPipeline(
+		NewKeepAlphanumericTokenizer(),
+		NewLowerCaseTokenizer(),
+		NewNGramFilter(3, 4, true),
+	))
+	in := builder("courses")
+	in.Put(NewDocRequest("programming", "Programación en Go"))
+	in.Put(NewDocRequest("cloud", "Cloud deployments"))
+
+	results := in.Search("gram", HitsSearch)
+	assert.Equal(t, 1, results.Len())
+	res, _ := results.Get(0)
+	assert.Equal(t, "programming", res.Doc().ID())
+}
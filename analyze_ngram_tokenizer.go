@@ -0,0 +1,46 @@
+package visigoth
+
+// NGramTokenizer splits text into words made of letters and digits and emits
+// the character n-grams of every word, each one at its own position and with
+// its own offsets. Words shorter than minGram produce no tokens.
+type NGramTokenizer struct {
+	minGram, maxGram int
+	edge             bool
+}
+
+func (t NGramTokenizer) Tokenize(text string) []string {
+	return Terms(t.TokenStream(text))
+}
+
+func (t NGramTokenizer) TokenStream(text string) []Token {
+	var res []Token
+	for _, word := range NewKeepAlphanumericTokenizer().TokenStream(text) {
+		ngrams(word.Term, t.minGram, t.maxGram, t.edge, func(start, end int) {
+			term := word.Term[start:end]
+			res = append(res, Token{
+				Term:              term,
+				Position:          len(res),
+				PositionIncrement: 1,
+				Start:             word.Start + start,
+				End:               word.Start + end,
+				Type:              tokenType(term),
+			})
+		})
+	}
+	return res
+}
+
+// NewNGramTokenizer returns a tokenizer producing n-grams from minGram to
+// maxGram runes long. Sizes are adjusted so that 1 <= minGram <= maxGram.
+func NewNGramTokenizer(minGram, maxGram int) NGramTokenizer {
+	minGram, maxGram = gramSizes(minGram, maxGram)
+	return NGramTokenizer{minGram: minGram, maxGram: maxGram}
+}
+
+// NewEdgeNGramTokenizer returns a tokenizer producing the prefixes of words
+// from minGram to maxGram runes long.
+func NewEdgeNGramTokenizer(minGram, maxGram int) NGramTokenizer {
+	t := NewNGramTokenizer(minGram, maxGram)
+	t.edge = true
+	return t
+}
@@ -0,0 +1,36 @@
+package visigoth
+
+import "golang.org/x/text/unicode/norm"
+
+// NormalizationFilter applies a Unicode normalization form to tokens, so that
+// canonically (NFC) or compatibility (NFKC) equivalent texts produce the same
+// terms: "é" typed as a single code point or as "e" plus a combining accent,
+// or the "ﬁ" ligature and "fi".
+type NormalizationFilter struct {
+	form norm.Form
+}
+
+func (n NormalizationFilter) Filter(tokens []string) []string {
+	r := make([]string, len(tokens))
+	for i, token := range tokens {
+		r[i] = n.form.String(token)
+	}
+	return r
+}
+
+func (n NormalizationFilter) FilterStream(tokens []Token) []Token {
+	r := make([]Token, len(tokens))
+	for i, token := range tokens {
+		token.Term = n.form.String(token.Term)
+		r[i] = token
+	}
+	return r
+}
+
+func NewNFCFilter() NormalizationFilter {
+	return NormalizationFilter{form: norm.NFC}
+}
+
+func NewNFKCFilter() NormalizationFilter {
+	return NormalizationFilter{form: norm.NFKC}
+}
@@ -0,0 +1,364 @@
+package visigoth
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Built-in analyzer names.
+const (
+	StandardAnalyzer   = "standard"
+	KeywordAnalyzer    = "keyword"
+	WhitespaceAnalyzer = "whitespace"
+)
+
+// TokenizerFactory builds a tokenizer from its configuration parameters.
+type TokenizerFactory func(params ComponentParams) (Tokenizer, error)
+
+// CharFilterFactory builds a char filter from its configuration parameters.
+type CharFilterFactory func(params ComponentParams) (CharFilter, error)
+
+// FilterFactory builds a filter from its configuration parameters.
+type FilterFactory func(params ComponentParams) (Filter, error)
+
+// AnalyzerRegistry keeps analyzers by name, along with the tokenizers and
+// filters analyzers can be declared with. It is safe for concurrent use.
+type AnalyzerRegistry struct {
+	mu          sync.RWMutex
+	charFilters map[string]CharFilterFactory
+	tokenizers  map[string]TokenizerFactory
+	filters     map[string]FilterFactory
+	analyzers   map[string]*TokenizationPipeline
+}
+
+func (r *AnalyzerRegistry) RegisterCharFilter(name string, factory CharFilterFactory) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.charFilters[name] = factory
+}
+
+func (r *AnalyzerRegistry) RegisterTokenizer(name string, factory TokenizerFactory) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tokenizers[name] = factory
+}
+
+func (r *AnalyzerRegistry) RegisterFilter(name string, factory FilterFactory) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.filters[name] = factory
+}
+
+// Register stores the analyzer under name, replacing any previous one.
+func (r *AnalyzerRegistry) Register(name string, analyzer *TokenizationPipeline) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.analyzers[name] = analyzer
+}
+
+// Analyzer returns the analyzer registered under name.
+func (r *AnalyzerRegistry) Analyzer(name string) (*TokenizationPipeline, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	analyzer, ok := r.analyzers[name]
+	if !ok {
+		return nil, NotFoundError{Kind: "analyzer", Name: name}
+	}
+	return analyzer, nil
+}
+
+// Analyzers returns the names of the registered analyzers, sorted.
+func (r *AnalyzerRegistry) Analyzers() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.analyzers))
+	for name := range r.analyzers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Build assembles the analyzer declared by config.
+func (r *AnalyzerRegistry) Build(config AnalyzerConfig) (*TokenizationPipeline, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	charFilters := make([]CharFilter, len(config.CharFilters))
+	for i, cc := range config.CharFilters {
+		newCharFilter, ok := r.charFilters[cc.Type]
+		if !ok {
+			return nil, fmt.Errorf("char filter with name '%s' does not exist", cc.Type)
+		}
+		var err error
+		if charFilters[i], err = newCharFilter(cc.Params); err != nil {
+			return nil, fmt.Errorf("char filter '%s': %w", cc.Type, err)
+		}
+	}
+
+	newTokenizer, ok := r.tokenizers[config.Tokenizer.Type]
+	if !ok {
+		return nil, fmt.Errorf("tokenizer with name '%s' does not exist", config.Tokenizer.Type)
+	}
+	tokenizer, err := newTokenizer(config.Tokenizer.Params)
+	if err != nil {
+		return nil, fmt.Errorf("tokenizer '%s': %w", config.Tokenizer.Type, err)
+	}
+	filters := make([]Filter, len(config.Filters))
+	for i, fc := range config.Filters {
+		newFilter, ok := r.filters[fc.Type]
+		if !ok {
+			return nil, fmt.Errorf("filter with name '%s' does not exist", fc.Type)
+		}
+		if filters[i], err = newFilter(fc.Params); err != nil {
+			return nil, fmt.Errorf("filter '%s': %w", fc.Type, err)
+		}
+	}
+	analyzer := NewTokenizationPipeline(tokenizer, filters...)
+	if len(charFilters) > 0 {
+		analyzer = analyzer.WithCharFilters(charFilters...)
+	}
+	return analyzer, nil
+}
+
+// Define builds the analyzer declared by config and registers it under name.
+func (r *AnalyzerRegistry) Define(name string, config AnalyzerConfig) error {
+	analyzer, err := r.Build(config)
+	if err != nil {
+		return fmt.Errorf("analyzer '%s': %w", name, err)
+	}
+	r.Register(name, analyzer)
+	return nil
+}
+
+// Load defines every analyzer in config. Nothing is registered if any of them
+// is invalid.
+func (r *AnalyzerRegistry) Load(config AnalyzersConfig) error {
+	names := make([]string, 0, len(config.Analyzers))
+	for name := range config.Analyzers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	analyzers := make([]*TokenizationPipeline, len(names))
+	for i, name := range names {
+		analyzer, err := r.Build(config.Analyzers[name])
+		if err != nil {
+			return fmt.Errorf("analyzer '%s': %w", name, err)
+		}
+		analyzers[i] = analyzer
+	}
+	for i, name := range names {
+		r.Register(name, analyzers[i])
+	}
+	return nil
+}
+
+func (r *AnalyzerRegistry) registerBuiltins() {
+	r.RegisterCharFilter("html_strip", func(ComponentParams) (CharFilter, error) {
+		return NewHTMLStripCharFilter(), nil
+	})
+	r.RegisterCharFilter("markdown_strip", func(ComponentParams) (CharFilter, error) {
+		return NewMarkdownStripCharFilter(), nil
+	})
+	r.RegisterCharFilter("pattern_replace", func(params ComponentParams) (CharFilter, error) {
+		pattern, err := params.String("pattern", "")
+		if err != nil {
+			return nil, err
+		}
+		replacement, err := params.String("replacement", "")
+		if err != nil {
+			return nil, err
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		return NewPatternReplaceCharFilter(re, replacement), nil
+	})
+
+	r.RegisterTokenizer("standard", func(ComponentParams) (Tokenizer, error) {
+		return NewUnicodeTokenizer(), nil
+	})
+	r.RegisterTokenizer("alphanumeric", func(ComponentParams) (Tokenizer, error) {
+		return NewKeepAlphanumericTokenizer(), nil
+	})
+	r.RegisterTokenizer("whitespace", func(ComponentParams) (Tokenizer, error) {
+		return NewWhitespaceTokenizer(), nil
+	})
+	r.RegisterTokenizer("keyword", func(ComponentParams) (Tokenizer, error) {
+		return NewKeywordTokenizer(), nil
+	})
+	r.RegisterTokenizer("ngram", func(params ComponentParams) (Tokenizer, error) {
+		minGram, maxGram, err := gramParams(params)
+		return NewNGramTokenizer(minGram, maxGram), err
+	})
+	r.RegisterTokenizer("edge_ngram", func(params ComponentParams) (Tokenizer, error) {
+		minGram, maxGram, err := gramParams(params)
+		return NewEdgeNGramTokenizer(minGram, maxGram), err
+	})
+
+	r.RegisterFilter("lowercase", func(ComponentParams) (Filter, error) {
+		return NewLowerCaseTokenizer(), nil
+	})
+	r.RegisterFilter("nfc", func(ComponentParams) (Filter, error) {
+		return NewNFCFilter(), nil
+	})
+	r.RegisterFilter("nfkc", func(ComponentParams) (Filter, error) {
+		return NewNFKCFilter(), nil
+	})
+	r.RegisterFilter("ascii_folding", func(params ComponentParams) (Filter, error) {
+		preserveOriginal, err := params.Bool("preserve_original", false)
+		return NewASCIIFoldingFilter(preserveOriginal), err
+	})
+	r.RegisterFilter("diacritic_folding", func(params ComponentParams) (Filter, error) {
+		preserve, err := params.String("preserve", "")
+		return NewDiacriticFoldingFilter([]rune(preserve)...), err
+	})
+	r.RegisterFilter("stopwords", newStopWordsFilterFromParams)
+	r.RegisterFilter("common_grams", func(params ComponentParams) (Filter, error) {
+		common, err := stopWordsFromParams(params)
+		return NewCommonGramsFilter(common), err
+	})
+	r.RegisterFilter("stemmer", func(params ComponentParams) (Filter, error) {
+		lang, err := params.String("language", "")
+		if err != nil {
+			return nil, err
+		}
+		removeStopWords, err := params.Bool("remove_stopwords", false)
+		if err != nil {
+			return nil, err
+		}
+		stemmer, err := NewStemmer(Language(lang), removeStopWords)
+		return stemmer, err
+	})
+	r.RegisterFilter("keyword_marker", func(params ComponentParams) (Filter, error) {
+		keywords, err := params.Strings("keywords")
+		return NewKeywordMarkerFilter(keywords...), err
+	})
+	r.RegisterFilter("synonyms", func(params ComponentParams) (Filter, error) {
+		rules, err := params.Strings("synonyms")
+		if err != nil {
+			return nil, err
+		}
+		expand, err := params.Bool("expand", true)
+		if err != nil {
+			return nil, err
+		}
+		synonyms, err := ParseSolrSynonyms(strings.NewReader(strings.Join(rules, "\n")), expand)
+		if err != nil {
+			return nil, err
+		}
+		return NewSynonymFilter(synonyms), nil
+	})
+	r.RegisterFilter("ngram", func(params ComponentParams) (Filter, error) {
+		minGram, maxGram, err := gramParams(params)
+		if err != nil {
+			return nil, err
+		}
+		preserveOriginal, err := params.Bool("preserve_original", false)
+		return NewNGramFilter(minGram, maxGram, preserveOriginal), err
+	})
+	r.RegisterFilter("edge_ngram", func(params ComponentParams) (Filter, error) {
+		minGram, maxGram, err := gramParams(params)
+		if err != nil {
+			return nil, err
+		}
+		preserveOriginal, err := params.Bool("preserve_original", false)
+		return NewEdgeNGramFilter(minGram, maxGram, preserveOriginal), err
+	})
+
+	r.Register(StandardAnalyzer, NewTokenizationPipeline(
+		NewUnicodeTokenizer(),
+		NewLowerCaseTokenizer(),
+	))
+	r.Register(KeywordAnalyzer, NewTokenizationPipeline(NewKeywordTokenizer()))
+	r.Register(WhitespaceAnalyzer, NewTokenizationPipeline(NewWhitespaceTokenizer()))
+	for _, lang := range Languages() {
+		analyzer, _ := NewLanguageAnalyzer(lang)
+		r.Register(string(lang), analyzer)
+	}
+}
+
+func gramParams(params ComponentParams) (int, int, error) {
+	minGram, err := params.Int("min_gram", 1)
+	if err != nil {
+		return 0, 0, err
+	}
+	maxGram, err := params.Int("max_gram", 2)
+	return minGram, maxGram, err
+}
+
+// stopWordsFromParams builds a stopword list from the built-in list of a
+// "language", the words of "stopwords" and of the Snowball formatted file at
+// "stopwords_path", minus the words of "exclude".
+func stopWordsFromParams(params ComponentParams) (StopWords, error) {
+	lang, err := params.String("language", "")
+	if err != nil {
+		return nil, err
+	}
+	words, err := params.Strings("stopwords")
+	if err != nil {
+		return nil, err
+	}
+	path, err := params.String("stopwords_path", "")
+	if err != nil {
+		return nil, err
+	}
+	exclude, err := params.Strings("exclude")
+	if err != nil {
+		return nil, err
+	}
+
+	lists := []StopWords{NewStopWords(words...)}
+	if lang != "" {
+		builtin, ok := LanguageStopWords(Language(lang))
+		if !ok {
+			return nil, fmt.Errorf("no stopwords available for language '%s'", lang)
+		}
+		lists = append(lists, builtin)
+	}
+	if path != "" {
+		loaded, err := LoadStopWords(path)
+		if err != nil {
+			return nil, err
+		}
+		lists = append(lists, loaded)
+	}
+	return lists[0].Merge(lists[1:]...).Subtract(NewStopWords(exclude...)), nil
+}
+
+// newStopWordsFilterFromParams builds a stopwords filter from a list given as
+// stopWordsFromParams does, optionally "folding" it.
+func newStopWordsFilterFromParams(params ComponentParams) (Filter, error) {
+	sw, err := stopWordsFromParams(params)
+	if err != nil {
+		return nil, err
+	}
+	folding, err := params.Bool("folding", false)
+	if err != nil {
+		return nil, err
+	}
+	var opts []StopWordsOption
+	if folding {
+		opts = append(opts, WithStopWordsFolding())
+	}
+	return NewStopWordsFilter(sw, opts...), nil
+}
+
+// NewAnalyzerRegistry returns a registry with the built-in components and
+// analyzers: "standard", "keyword", "whitespace" and one per language, such
+// as "spanish".
+func NewAnalyzerRegistry() *AnalyzerRegistry {
+	r := &AnalyzerRegistry{
+		charFilters: make(map[string]CharFilterFactory),
+		tokenizers:  make(map[string]TokenizerFactory),
+		filters:     make(map[string]FilterFactory),
+		analyzers:   make(map[string]*TokenizationPipeline),
+	}
+	r.registerBuiltins()
+	return r
+}
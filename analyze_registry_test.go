@@ -0,0 +1,128 @@
+package visigoth
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyzerRegistry_Builtins(t *testing.T) {
+	registry := NewAnalyzerRegistry()
+	assert.Contains(t, registry.Analyzers(), StandardAnalyzer)
+	assert.Contains(t, registry.Analyzers(), KeywordAnalyzer)
+	assert.Contains(t, registry.Analyzers(), WhitespaceAnalyzer)
+	assert.Contains(t, registry.Analyzers(), string(Spanish))
+
+	tests := []struct {
+		analyzer string
+		text     string
+		expected []string
+	}{
+		{StandardAnalyzer, "Hello, World!", []string{"hello", "world"}},
+		{KeywordAnalyzer, "SKU-1234 X", []string{"SKU-1234 X"}},
+		{WhitespaceAnalyzer, "Hello, World!", []string{"Hello,", "World!"}},
+		{string(Spanish), "Las programaciones", []string{"program"}},
+	}
+	for _, test := range tests {
+		analyzer, err := registry.Analyzer(test.analyzer)
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, analyzer.Tokenize(test.text), "analyzer '%s'", test.analyzer)
+	}
+
+	_, err := registry.Analyzer("klingon")
+	assert.Error(t, err)
+}
+
+func TestAnalyzerRegistry_LoadJSON(t *testing.T) {
+	config, err := ParseAnalyzersJSON([]byte(`{
+		"analyzers": {
+			"products": {
+				"tokenizer": "standard",
+				"filters": [
+					"lowercase",
+					{"type": "stopwords", "language": "spanish", "folding": true},
+					{"type": "edge_ngram", "min_gram": 3, "max_gram": 5}
+				]
+			}
+		}
+	}`))
+	assert.NoError(t, err)
+
+	registry := NewAnalyzerRegistry()
+	assert.NoError(t, registry.Load(config))
+	analyzer, err := registry.Analyzer("products")
+	assert.NoError(t, err)
+	assert.Equal(t,
+		[]string{"tec", "tecl", "tecla", "neg", "negr", "negro"},
+		analyzer.Tokenize("Teclado TAMBIEN negro"),
+	)
+
+	data, err := json.Marshal(config.Analyzers["products"].Filters)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `[
+		"lowercase",
+		{"type": "stopwords", "language": "spanish", "folding": true},
+		{"type": "edge_ngram", "min_gram": 3, "max_gram": 5}
+	]`, string(data))
+}
+
+func TestAnalyzerRegistry_LoadYAML(t *testing.T) {
+	config, err := ParseAnalyzersYAML([]byte(`
+analyzers:
+  tech:
+    tokenizer: alphanumeric
+    filters:
+      - lowercase
+      - type: synonyms
+        synonyms:
+          - "js, javascript"
+      - type: stemmer
+        language: english
+`))
+	assert.NoError(t, err)
+
+	registry := NewAnalyzerRegistry()
+	assert.NoError(t, registry.Load(config))
+	analyzer, err := registry.Analyzer("tech")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"learn", "js", "javascript"}, analyzer.Tokenize("Learning JS"))
+}
+
+func TestAnalyzerRegistry_Errors(t *testing.T) {
+	registry := NewAnalyzerRegistry()
+	tests := []string{
+		`{"analyzers": {"a": {"tokenizer": "unknown"}}}`,
+		`{"analyzers": {"a": {"tokenizer": "standard", "filters": ["unknown"]}}}`,
+		`{"analyzers": {"a": {"tokenizer": "ngram", "filters": [{"min_gram": 2}]}}}`,
+		`{"analyzers": {"a": {"tokenizer": {"type": "ngram", "min_gram": "two"}}}}`,
+		`{"analyzers": {"a": {"tokenizer": "standard", "filters": [{"type": "stemmer"}]}}}`,
+	}
+	for _, test := range tests {
+		config, err := ParseAnalyzersJSON([]byte(test))
+		if err == nil {
+			err = registry.Load(config)
+		}
+		assert.Error(t, err, test)
+	}
+	assert.NotContains(t, registry.Analyzers(), "a")
+}
+
+func TestMemoryIndex_NamedAnalyzer(t *testing.T) {
+	registry := NewAnalyzerRegistry()
+	builder, err := NewAnalyzerMemoryIndexBuilder(registry, string(Spanish))
+	assert.NoError(t, err)
+	in := builder("courses").(*MemoryIndex)
+	in.Put(NewDocRequest("go", "Programación en Go"))
+
+	data, err := json.Marshal(in)
+	assert.NoError(t, err)
+	restored := NewMemoryIndex("courses", nil)
+	assert.NoError(t, json.Unmarshal(data, restored))
+	assert.Equal(t, string(Spanish), restored.Analyzer)
+	assert.NoError(t, restored.SetAnalyzer(registry, restored.Analyzer))
+	assert.Equal(t, 1, restored.Search("programaciones", HitsSearch).Len())
+
+	_, err = NewAnalyzerMemoryIndexBuilder(registry, "klingon")
+	assert.Error(t, err)
+}
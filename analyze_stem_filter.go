@@ -1,6 +1,61 @@
 package visigoth
 
-import snowballSpanish "github.com/kljensen/snowball/spanish"
+import (
+	"fmt"
+
+	snowballEnglish "github.com/kljensen/snowball/english"
+	snowballFrench "github.com/kljensen/snowball/french"
+	snowballHungarian "github.com/kljensen/snowball/hungarian"
+	snowballNorwegian "github.com/kljensen/snowball/norwegian"
+	snowballRussian "github.com/kljensen/snowball/russian"
+	snowballSpanish "github.com/kljensen/snowball/spanish"
+	snowballSwedish "github.com/kljensen/snowball/swedish"
+)
+
+type Language string
+
+const (
+	English   Language = "english"
+	French    Language = "french"
+	Hungarian Language = "hungarian"
+	Norwegian Language = "norwegian"
+	Russian   Language = "russian"
+	Spanish   Language = "spanish"
+	Swedish   Language = "swedish"
+)
+
+type stemFunc func(word string, stemStopWords bool) string
+
+var snowballStemmers = map[Language]stemFunc{
+	English:   snowballEnglish.Stem,
+	French:    snowballFrench.Stem,
+	Hungarian: snowballHungarian.Stem,
+	Norwegian: snowballNorwegian.Stem,
+	Russian:   snowballRussian.Stem,
+	Spanish:   snowballSpanish.Stem,
+	Swedish:   snowballSwedish.Stem,
+}
+
+var languageStopWords = map[Language]StopWords{
+	English:   EnglishStopWords,
+	French:    FrenchStopWords,
+	Hungarian: HungarianStopWords,
+	Norwegian: NorwegianStopWords,
+	Russian:   RussianStopWords,
+	Spanish:   SpanishStopWords,
+	Swedish:   SwedishStopWords,
+}
+
+// Languages returns every language a stemmer is available for.
+func Languages() []Language {
+	return []Language{English, French, Hungarian, Norwegian, Russian, Spanish, Swedish}
+}
+
+// LanguageStopWords returns the built-in stopword list of the language.
+func LanguageStopWords(lang Language) (StopWords, bool) {
+	sw, ok := languageStopWords[lang]
+	return sw, ok
+}
 
 type SpanishStemmerFilter struct {
 	removeStopWords bool
@@ -14,6 +69,131 @@ func (s SpanishStemmerFilter) Filter(tokens []string) []string {
 	return r
 }
 
+func (s SpanishStemmerFilter) FilterStream(tokens []Token) []Token {
+	r := make([]Token, len(tokens))
+	for i, token := range tokens {
+		if !token.Keyword {
+			token.Term = snowballSpanish.Stem(token.Term, s.removeStopWords)
+		}
+		r[i] = token
+	}
+	return r
+}
+
 func NewSpanishStemmer(removeStopWords bool) SpanishStemmerFilter {
 	return SpanishStemmerFilter{removeStopWords: removeStopWords}
 }
+
+// StemmerFilter stems tokens with the Snowball stemmer of a language.
+type StemmerFilter struct {
+	language        Language
+	stem            stemFunc
+	removeStopWords bool
+}
+
+func (s StemmerFilter) Language() Language {
+	return s.language
+}
+
+func (s StemmerFilter) Filter(tokens []string) []string {
+	r := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		r = append(r, s.stem(token, s.removeStopWords))
+	}
+	return r
+}
+
+func (s StemmerFilter) FilterStream(tokens []Token) []Token {
+	r := make([]Token, len(tokens))
+	for i, token := range tokens {
+		if !token.Keyword {
+			token.Term = s.stem(token.Term, s.removeStopWords)
+		}
+		r[i] = token
+	}
+	return r
+}
+
+// NewStemmer returns the stemmer filter of the language, failing for
+// languages with no stemmer available.
+func NewStemmer(lang Language, removeStopWords bool) (StemmerFilter, error) {
+	stem, ok := snowballStemmers[lang]
+	if !ok {
+		return StemmerFilter{}, fmt.Errorf("no stemmer available for language '%s'", lang)
+	}
+	return StemmerFilter{language: lang, stem: stem, removeStopWords: removeStopWords}, nil
+}
+
+func newStemmer(lang Language, removeStopWords bool) StemmerFilter {
+	return StemmerFilter{
+		language:        lang,
+		stem:            snowballStemmers[lang],
+		removeStopWords: removeStopWords,
+	}
+}
+
+func NewEnglishStemmer(removeStopWords bool) StemmerFilter {
+	return newStemmer(English, removeStopWords)
+}
+
+func NewFrenchStemmer(removeStopWords bool) StemmerFilter {
+	return newStemmer(French, removeStopWords)
+}
+
+func NewHungarianStemmer(removeStopWords bool) StemmerFilter {
+	return newStemmer(Hungarian, removeStopWords)
+}
+
+func NewNorwegianStemmer(removeStopWords bool) StemmerFilter {
+	return newStemmer(Norwegian, removeStopWords)
+}
+
+func NewRussianStemmer(removeStopWords bool) StemmerFilter {
+	return newStemmer(Russian, removeStopWords)
+}
+
+func NewSwedishStemmer(removeStopWords bool) StemmerFilter {
+	return newStemmer(Swedish, removeStopWords)
+}
+
+// NativeSpanishStemmerFilter stems tokens with the native Snowball Spanish
+// Stemmer. Unlike SpanishStemmerFilter, it reuses a single buffer per call and
+// only allocates for tokens whose stem differs from the token itself. Tokens
+// are expected to be lower cased.
+type NativeSpanishStemmerFilter struct {
+	stemmer *Stemmer
+}
+
+func (s NativeSpanishStemmerFilter) stem(buf []byte, token string) ([]byte, string) {
+	buf = append(buf[:0], token...)
+	stem := s.stemmer.Stem(buf)
+	if len(stem) == len(token) && string(stem) == token {
+		return buf, token
+	}
+	return buf, string(stem)
+}
+
+func (s NativeSpanishStemmerFilter) Filter(tokens []string) []string {
+	var buf []byte
+	r := make([]string, len(tokens))
+	for i, token := range tokens {
+		buf, r[i] = s.stem(buf, token)
+	}
+	return r
+}
+
+func (s NativeSpanishStemmerFilter) FilterStream(tokens []Token) []Token {
+	var buf []byte
+	r := make([]Token, len(tokens))
+	for i, token := range tokens {
+		if !token.Keyword {
+			buf, token.Term = s.stem(buf, token.Term)
+		}
+		r[i] = token
+	}
+	return r
+}
+
+func NewNativeSpanishStemmer() NativeSpanishStemmerFilter {
+	return NativeSpanishStemmerFilter{stemmer: NewSnowball()}
+}
@@ -0,0 +1,101 @@
+package visigoth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStemmerFilter(t *testing.T) {
+	tests := []struct {
+		lang     Language
+		words    []string
+		expected []string
+	}{
+		{lang: English, words: []string{"running", "courses"}, expected: []string{"run", "cours"}},
+		{
+			lang:     French,
+			words:    []string{"programmation", "continuellement"},
+			expected: []string{"programm", "continuel"},
+		},
+		{lang: Russian, words: []string{"программирование"}, expected: []string{"программирован"}},
+		{
+			lang:     Swedish,
+			words:    []string{"flickorna", "hästarna"},
+			expected: []string{"flick", "häst"},
+		},
+		{lang: Norwegian, words: []string{"husene", "bilene"}, expected: []string{"hus", "bil"}},
+		{lang: Spanish, words: []string{"programación"}, expected: []string{"program"}},
+	}
+
+	for _, test := range tests {
+		stemmer, err := NewStemmer(test.lang, true)
+		assert.NoError(t, err)
+		assert.Equal(t, test.lang, stemmer.Language())
+		assert.Equal(t, test.expected, stemmer.Filter(test.words), "language '%s'", test.lang)
+	}
+
+	_, err := NewStemmer("klingon", true)
+	assert.Error(t, err, "unknown languages should not have a stemmer")
+}
+
+func TestStemmerFilter_StopWords(t *testing.T) {
+	for _, lang := range Languages() {
+		sw, ok := LanguageStopWords(lang)
+		assert.True(t, ok, "language '%s' should have stopwords", lang)
+		assert.NotEmpty(t, sw)
+	}
+
+	analyzer := NewTokenizationPipeline(
+		NewKeepAlphanumericTokenizer(),
+		NewLowerCaseTokenizer(),
+		NewStopWordsFilter(EnglishStopWords),
+		NewEnglishStemmer(true),
+	)
+	in := NewMemoryIndex("courses", analyzer)
+	in.Put(NewDocRequest("java-course", "Learn programming in Java"))
+	in.Put(NewDocRequest("go-course", "Go programs for the cloud"))
+
+	results := in.Search("the programs", HitsSearch)
+	assert.Equal(t, 2, results.Len(), "'programming' and 'programs' should share stem")
+}
+
+func TestNativeSpanishStemmerFilter(t *testing.T) {
+	words := []string{"programación", "haciéndola", "corriendo", "java", "trabajadoras"}
+
+	native := NewNativeSpanishStemmer()
+	assert.Equal(t, []string{"program", "hac", "corr", "jav", "trabaj"}, native.Filter(words))
+	assert.Equal(t, NewSpanishStemmer(true).Filter(words), native.Filter(words))
+
+	analyzer := NewTokenizationPipeline(
+		NewKeepAlphanumericTokenizer(),
+		NewLowerCaseTokenizer(),
+		NewKeywordMarkerFilter("java"),
+		native,
+	)
+	tokens := analyzer.TokenStream("Programación Java")
+	assert.Equal(t, []string{"program", "java"}, Terms(tokens))
+}
+
+func BenchmarkSpanishStemmers(b *testing.B) {
+	tokens := NewKeepAlphanumericTokenizer().Tokenize(
+		"curso de programación en java para principiantes haciéndola trabajadoras " +
+			"corriendo rápidamente hacia las montañas nevadas durante el invierno",
+	)
+
+	b.Run("snowball", func(b *testing.B) {
+		filter := NewSpanishStemmer(true)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			filter.Filter(tokens)
+		}
+	})
+
+	b.Run("native", func(b *testing.B) {
+		filter := NewNativeSpanishStemmer()
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			filter.Filter(tokens)
+		}
+	})
+}
@@ -0,0 +1,24 @@
+package visigoth
+
+// Stopword lists distributed with the Snowball project for every language a
+// stemmer filter is available for.
+var (
+	EnglishStopWords = StopWords{
+		"i": {}, "me": {}, "my": {}, "myself": {}, "we": {}, "our": {}, "ours": {}, "ourselves": {}, "you": {}, "your": {}, "yours": {}, "yourself": {}, "yourselves": {}, "he": {}, "him": {}, "his": {}, "himself": {}, "she": {}, "her": {}, "hers": {}, "herself": {}, "it": {}, "its": {}, "itself": {}, "they": {}, "them": {}, "their": {}, "theirs": {}, "themselves": {}, "what": {}, "which": {}, "who": {}, "whom": {}, "this": {}, "that": {}, "these": {}, "those": {}, "am": {}, "is": {}, "are": {}, "was": {}, "were": {}, "be": {}, "been": {}, "being": {}, "have": {}, "has": {}, "had": {}, "having": {}, "do": {}, "does": {}, "did": {}, "doing": {}, "would": {}, "should": {}, "could": {}, "ought": {}, "i'm": {}, "you're": {}, "he's": {}, "she's": {}, "it's": {}, "we're": {}, "they're": {}, "i've": {}, "you've": {}, "we've": {}, "they've": {}, "i'd": {}, "you'd": {}, "he'd": {}, "she'd": {}, "we'd": {}, "they'd": {}, "i'll": {}, "you'll": {}, "he'll": {}, "she'll": {}, "we'll": {}, "they'll": {}, "isn't": {}, "aren't": {}, "wasn't": {}, "weren't": {}, "hasn't": {}, "haven't": {}, "hadn't": {}, "doesn't": {}, "don't": {}, "didn't": {}, "won't": {}, "wouldn't": {}, "shan't": {}, "shouldn't": {}, "can't": {}, "cannot": {}, "couldn't": {}, "mustn't": {}, "let's": {}, "that's": {}, "who's": {}, "what's": {}, "here's": {}, "there's": {}, "when's": {}, "where's": {}, "why's": {}, "how's": {}, "a": {}, "an": {}, "the": {}, "and": {}, "but": {}, "if": {}, "or": {}, "because": {}, "as": {}, "until": {}, "while": {}, "of": {}, "at": {}, "by": {}, "for": {}, "with": {}, "about": {}, "against": {}, "between": {}, "into": {}, "through": {}, "during": {}, "before": {}, "after": {}, "above": {}, "below": {}, "to": {}, "from": {}, "up": {}, "down": {}, "in": {}, "out": {}, "on": {}, "off": {}, "over": {}, "under": {}, "again": {}, "further": {}, "then": {}, "once": {}, "here": {}, "there": {}, "when": {}, "where": {}, "why": {}, "how": {}, "all": {}, "any": {}, "both": {}, "each": {}, "few": {}, "more": {}, "most": {}, "other": {}, "some": {}, "such": {}, "no": {}, "nor": {}, "not": {}, "only": {}, "own": {}, "same": {}, "so": {}, "than": {}, "too": {}, "very": {},
+	}
+	FrenchStopWords = StopWords{
+		"au": {}, "aux": {}, "avec": {}, "ce": {}, "ces": {}, "dans": {}, "de": {}, "des": {}, "du": {}, "elle": {}, "en": {}, "et": {}, "eux": {}, "il": {}, "je": {}, "la": {}, "le": {}, "leur": {}, "lui": {}, "ma": {}, "mais": {}, "me": {}, "même": {}, "mes": {}, "moi": {}, "mon": {}, "ne": {}, "nos": {}, "notre": {}, "nous": {}, "on": {}, "ou": {}, "par": {}, "pas": {}, "pour": {}, "qu": {}, "que": {}, "qui": {}, "sa": {}, "se": {}, "ses": {}, "son": {}, "sur": {}, "ta": {}, "te": {}, "tes": {}, "toi": {}, "ton": {}, "tu": {}, "un": {}, "une": {}, "vos": {}, "votre": {}, "vous": {}, "c": {}, "d": {}, "j": {}, "l": {}, "à": {}, "m": {}, "n": {}, "s": {}, "t": {}, "y": {}, "été": {}, "étée": {}, "étées": {}, "étés": {}, "étant": {}, "étante": {}, "étants": {}, "étantes": {}, "suis": {}, "es": {}, "est": {}, "sommes": {}, "êtes": {}, "sont": {}, "serai": {}, "seras": {}, "sera": {}, "serons": {}, "serez": {}, "seront": {}, "serais": {}, "serait": {}, "serions": {}, "seriez": {}, "seraient": {}, "étais": {}, "était": {}, "étions": {}, "étiez": {}, "étaient": {}, "fus": {}, "fut": {}, "fûmes": {}, "fûtes": {}, "furent": {}, "sois": {}, "soit": {}, "soyons": {}, "soyez": {}, "soient": {}, "fusse": {}, "fusses": {}, "fût": {}, "fussions": {}, "fussiez": {}, "fussent": {}, "ayant": {}, "ayante": {}, "ayantes": {}, "ayants": {}, "eu": {}, "eue": {}, "eues": {}, "eus": {}, "ai": {}, "as": {}, "avons": {}, "avez": {}, "ont": {}, "aurai": {}, "auras": {}, "aura": {}, "aurons": {}, "aurez": {}, "auront": {}, "aurais": {}, "aurait": {}, "aurions": {}, "auriez": {}, "auraient": {}, "avais": {}, "avait": {}, "avions": {}, "aviez": {}, "avaient": {}, "eut": {}, "eûmes": {}, "eûtes": {}, "eurent": {}, "aie": {}, "aies": {}, "ait": {}, "ayons": {}, "ayez": {}, "aient": {}, "eusse": {}, "eusses": {}, "eût": {}, "eussions": {}, "eussiez": {}, "eussent": {},
+	}
+	RussianStopWords = StopWords{
+		"и": {}, "в": {}, "во": {}, "не": {}, "что": {}, "он": {}, "на": {}, "я": {}, "с": {}, "со": {}, "как": {}, "а": {}, "то": {}, "все": {}, "она": {}, "так": {}, "его": {}, "но": {}, "да": {}, "ты": {}, "к": {}, "у": {}, "же": {}, "вы": {}, "за": {}, "бы": {}, "по": {}, "только": {}, "ее": {}, "мне": {}, "было": {}, "вот": {}, "от": {}, "меня": {}, "еще": {}, "нет": {}, "о": {}, "из": {}, "ему": {}, "теперь": {}, "когда": {}, "даже": {}, "ну": {}, "вдруг": {}, "ли": {}, "если": {}, "уже": {}, "или": {}, "ни": {}, "быть": {}, "был": {}, "него": {}, "до": {}, "вас": {}, "нибудь": {}, "опять": {}, "уж": {}, "вам": {}, "ведь": {}, "там": {}, "потом": {}, "себя": {}, "ничего": {}, "ей": {}, "может": {}, "они": {}, "тут": {}, "где": {}, "есть": {}, "надо": {}, "ней": {}, "для": {}, "мы": {}, "тебя": {}, "их": {}, "чем": {}, "была": {}, "сам": {}, "чтоб": {}, "без": {}, "будто": {}, "чего": {}, "раз": {}, "тоже": {}, "себе": {}, "под": {}, "будет": {}, "ж": {}, "тогда": {}, "кто": {}, "этот": {}, "того": {}, "потому": {}, "этого": {}, "какой": {}, "совсем": {}, "ним": {}, "здесь": {}, "этом": {}, "один": {}, "почти": {}, "мой": {}, "тем": {}, "чтобы": {}, "нее": {}, "сейчас": {}, "были": {}, "куда": {}, "зачем": {}, "всех": {}, "никогда": {}, "можно": {}, "при": {}, "наконец": {}, "два": {}, "об": {}, "другой": {}, "хоть": {}, "после": {}, "над": {}, "больше": {}, "тот": {}, "через": {}, "эти": {}, "нас": {}, "про": {}, "всего": {}, "них": {}, "какая": {}, "много": {}, "разве": {}, "три": {}, "эту": {}, "моя": {}, "впрочем": {}, "хорошо": {}, "свою": {}, "этой": {}, "перед": {}, "иногда": {}, "лучше": {}, "чуть": {}, "том": {}, "нельзя": {}, "такой": {}, "им": {}, "более": {}, "всегда": {}, "конечно": {}, "всю": {}, "между": {},
+	}
+	SwedishStopWords = StopWords{
+		"och": {}, "det": {}, "att": {}, "i": {}, "en": {}, "jag": {}, "hon": {}, "som": {}, "han": {}, "på": {}, "den": {}, "med": {}, "var": {}, "sig": {}, "för": {}, "så": {}, "till": {}, "är": {}, "men": {}, "ett": {}, "om": {}, "hade": {}, "de": {}, "av": {}, "icke": {}, "mig": {}, "du": {}, "henne": {}, "då": {}, "sin": {}, "nu": {}, "har": {}, "inte": {}, "hans": {}, "honom": {}, "skulle": {}, "hennes": {}, "där": {}, "min": {}, "man": {}, "ej": {}, "vid": {}, "kunde": {}, "något": {}, "från": {}, "ut": {}, "när": {}, "efter": {}, "upp": {}, "vi": {}, "dem": {}, "vara": {}, "vad": {}, "över": {}, "än": {}, "dig": {}, "kan": {}, "sina": {}, "här": {}, "ha": {}, "mot": {}, "alla": {}, "under": {}, "någon": {}, "eller": {}, "allt": {}, "mycket": {}, "sedan": {}, "ju": {}, "denna": {}, "själv": {}, "detta": {}, "åt": {}, "utan": {}, "varit": {}, "hur": {}, "ingen": {}, "mitt": {}, "ni": {}, "bli": {}, "blev": {}, "oss": {}, "din": {}, "dessa": {}, "några": {}, "deras": {}, "blir": {}, "mina": {}, "samma": {}, "vilken": {}, "er": {}, "sådan": {}, "vår": {}, "blivit": {}, "dess": {}, "inom": {}, "mellan": {}, "sådant": {}, "varför": {}, "varje": {}, "vilka": {}, "ditt": {}, "vem": {}, "vilket": {}, "sitta": {}, "sådana": {}, "vart": {}, "dina": {}, "vars": {}, "vårt": {}, "våra": {}, "ert": {}, "era": {}, "vilkas": {},
+	}
+	NorwegianStopWords = StopWords{
+		"og": {}, "i": {}, "jeg": {}, "det": {}, "at": {}, "en": {}, "et": {}, "den": {}, "til": {}, "er": {}, "som": {}, "på": {}, "de": {}, "med": {}, "han": {}, "av": {}, "ikke": {}, "ikkje": {}, "der": {}, "så": {}, "var": {}, "meg": {}, "seg": {}, "men": {}, "ett": {}, "har": {}, "om": {}, "vi": {}, "min": {}, "mitt": {}, "ha": {}, "hadde": {}, "hun": {}, "nå": {}, "over": {}, "da": {}, "ved": {}, "fra": {}, "du": {}, "ut": {}, "sin": {}, "dem": {}, "oss": {}, "opp": {}, "man": {}, "kan": {}, "hans": {}, "hvor": {}, "eller": {}, "hva": {}, "skal": {}, "selv": {}, "sjøl": {}, "her": {}, "alle": {}, "vil": {}, "bli": {}, "ble": {}, "blei": {}, "blitt": {}, "kunne": {}, "inn": {}, "når": {}, "være": {}, "kom": {}, "noen": {}, "noe": {}, "ville": {}, "dere": {}, "deres": {}, "kun": {}, "ja": {}, "etter": {}, "ned": {}, "skulle": {}, "denne": {}, "for": {}, "deg": {}, "si": {}, "sine": {}, "sitt": {}, "mot": {}, "å": {}, "meget": {}, "hvorfor": {}, "dette": {}, "disse": {}, "uten": {}, "hvordan": {}, "ingen": {}, "din": {}, "ditt": {}, "blir": {}, "samme": {}, "hvilken": {}, "hvilke": {}, "sånn": {}, "inni": {}, "mellom": {}, "vår": {}, "hver": {}, "hvem": {}, "vors": {}, "hvis": {}, "både": {}, "bare": {}, "enn": {}, "fordi": {}, "før": {}, "mange": {}, "også": {}, "slik": {}, "vært": {}, "båe": {}, "begge": {}, "siden": {}, "dykk": {}, "dykkar": {}, "dei": {}, "deira": {}, "deires": {}, "deim": {}, "di": {}, "då": {}, "eg": {}, "ein": {}, "eit": {}, "eitt": {}, "elles": {}, "honom": {}, "hjå": {}, "ho": {}, "hoe": {}, "henne": {}, "hennar": {}, "hennes": {}, "hoss": {}, "hossen": {}, "ingi": {}, "inkje": {}, "korleis": {}, "korso": {}, "kva": {}, "kvar": {}, "kvarhelst": {}, "kven": {}, "kvi": {}, "kvifor": {}, "me": {}, "medan": {}, "mi": {}, "mine": {}, "mykje": {}, "no": {}, "nokon": {}, "noka": {}, "nokor": {}, "noko": {}, "nokre": {}, "sia": {}, "sidan": {}, "so": {}, "somt": {}, "somme": {}, "um": {}, "upp": {}, "vere": {}, "vore": {}, "verte": {}, "vort": {}, "varte": {}, "vart": {},
+	}
+	HungarianStopWords = StopWords{
+		"a": {}, "ahogy": {}, "ahol": {}, "aki": {}, "akik": {}, "akkor": {}, "alatt": {}, "által": {}, "általában": {}, "amely": {}, "amelyek": {}, "amelyekben": {}, "amelyeket": {}, "amelyet": {}, "amelynek": {}, "ami": {}, "amit": {}, "amolyan": {}, "amíg": {}, "amikor": {}, "át": {}, "abban": {}, "ahhoz": {}, "annak": {}, "arra": {}, "arról": {}, "az": {}, "azok": {}, "azon": {}, "azt": {}, "azzal": {}, "azért": {}, "aztán": {}, "azután": {}, "azonban": {}, "bár": {}, "be": {}, "belül": {}, "benne": {}, "cikk": {}, "cikkek": {}, "cikkeket": {}, "csak": {}, "de": {}, "e": {}, "eddig": {}, "egész": {}, "egy": {}, "egyes": {}, "egyetlen": {}, "egyéb": {}, "egyik": {}, "egyre": {}, "ekkor": {}, "el": {}, "elég": {}, "ellen": {}, "elő": {}, "először": {}, "előtt": {}, "első": {}, "én": {}, "éppen": {}, "ebben": {}, "ehhez": {}, "emilyen": {}, "ennek": {}, "erre": {}, "ez": {}, "ezt": {}, "ezek": {}, "ezen": {}, "ezzel": {}, "ezért": {}, "és": {}, "fel": {}, "felé": {}, "hanem": {}, "hiszen": {}, "hogy": {}, "hogyan": {}, "igen": {}, "így": {}, "illetve": {}, "ill.": {}, "ill": {}, "ilyen": {}, "ilyenkor": {}, "ison": {}, "ismét": {}, "itt": {}, "jó": {}, "jól": {}, "jobban": {}, "kell": {}, "kellett": {}, "keresztül": {}, "keressünk": {}, "ki": {}, "kívül": {}, "között": {}, "közül": {}, "legalább": {}, "lehet": {}, "lehetett": {}, "legyen": {}, "lenne": {}, "lenni": {}, "lesz": {}, "lett": {}, "maga": {}, "magát": {}, "majd": {}, "már": {}, "más": {}, "másik": {}, "meg": {}, "még": {}, "mellett": {}, "mert": {}, "mely": {}, "melyek": {}, "mi": {}, "mit": {}, "míg": {}, "miért": {}, "milyen": {}, "mikor": {}, "minden": {}, "mindent": {}, "mindenki": {}, "mindig": {}, "mint": {}, "mintha": {}, "mivel": {}, "most": {}, "nagy": {}, "nagyobb": {}, "nagyon": {}, "ne": {}, "néha": {}, "nekem": {}, "neki": {}, "nem": {}, "néhány": {}, "nélkül": {}, "nincs": {}, "olyan": {}, "ott": {}, "össze": {}, "ő": {}, "ők": {}, "őket": {}, "pedig": {}, "persze": {}, "rá": {}, "s": {}, "saját": {}, "sem": {}, "semmi": {}, "sok": {}, "sokat": {}, "sokkal": {}, "számára": {}, "szemben": {}, "szerint": {}, "szinte": {}, "talán": {}, "tehát": {}, "teljes": {}, "tovább": {}, "továbbá": {}, "több": {}, "úgy": {}, "ugyanis": {}, "új": {}, "újabb": {}, "újra": {}, "után": {}, "utána": {}, "utolsó": {}, "vagy": {}, "vagyis": {}, "valaki": {}, "valami": {}, "valamint": {}, "való": {}, "vagyok": {}, "van": {}, "vannak": {}, "volt": {}, "voltam": {}, "voltak": {}, "voltunk": {}, "vissza": {}, "vele": {}, "viszont": {}, "volna": {},
+	}
+)
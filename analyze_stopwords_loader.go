@@ -0,0 +1,83 @@
+package visigoth
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+)
+
+// ParseStopWords reads a stopword list in the Snowball format: words separated
+// by white space, usually one per line, where '|' starts a comment running to
+// the end of the line.
+func ParseStopWords(r io.Reader) (StopWords, error) {
+	sw := make(StopWords)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '|'); i >= 0 {
+			line = line[:i]
+		}
+		for _, word := range strings.Fields(line) {
+			sw[word] = struct{}{}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return sw, nil
+}
+
+// LoadStopWords reads a stopword list in the Snowball format from a file.
+func LoadStopWords(path string) (StopWords, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseStopWords(f)
+}
+
+// NewStopWords returns a stopword list with the given words.
+func NewStopWords(words ...string) StopWords {
+	sw := make(StopWords, len(words))
+	for _, word := range words {
+		sw[word] = struct{}{}
+	}
+	return sw
+}
+
+// Merge returns a new list with the words of sw and of all the others.
+func (sw StopWords) Merge(others ...StopWords) StopWords {
+	res := make(StopWords, len(sw))
+	for word := range sw {
+		res[word] = struct{}{}
+	}
+	for _, other := range others {
+		for word := range other {
+			res[word] = struct{}{}
+		}
+	}
+	return res
+}
+
+// Subtract returns a new list with the words of sw missing from all the others.
+func (sw StopWords) Subtract(others ...StopWords) StopWords {
+	res := make(StopWords, len(sw))
+words:
+	for word := range sw {
+		for _, other := range others {
+			if _, ok := other[word]; ok {
+				continue words
+			}
+		}
+		res[word] = struct{}{}
+	}
+	return res
+}
+
+// Contains reports whether word is in the list.
+func (sw StopWords) Contains(word string) bool {
+	_, ok := sw[word]
+	return ok
+}
@@ -0,0 +1,144 @@
+package visigoth
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const snowballStopWords = ` | A Snowball stopword list
+de             |  from, of
+la             |  the, her
+que  el        |  who, that / the
+
+  | a line with no words
+`
+
+func TestParseStopWords(t *testing.T) {
+	sw, err := ParseStopWords(strings.NewReader(snowballStopWords))
+	assert.NoError(t, err)
+	assert.Equal(t, NewStopWords("de", "la", "que", "el"), sw)
+}
+
+func TestLoadStopWords(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spanish.txt")
+	assert.NoError(t, os.WriteFile(path, []byte(snowballStopWords), 0o600))
+	sw, err := LoadStopWords(path)
+	assert.NoError(t, err)
+	assert.Len(t, sw, 4)
+
+	_, err = LoadStopWords(filepath.Join(t.TempDir(), "missing.txt"))
+	assert.Error(t, err)
+}
+
+func TestStopWords_MergeSubtract(t *testing.T) {
+	a := NewStopWords("de", "la", "que")
+	b := NewStopWords("que", "el")
+
+	assert.Equal(t, NewStopWords("de", "la", "que", "el"), a.Merge(b))
+	assert.Equal(t, NewStopWords("de", "la"), a.Subtract(b))
+	assert.Equal(t, NewStopWords("de"), a.Subtract(b, NewStopWords("la")))
+	assert.Len(t, a, 3, "lists should not be modified")
+	assert.True(t, a.Contains("de"))
+	assert.False(t, a.Contains("el"))
+}
+
+func TestSpanishStopWords_SingleTerms(t *testing.T) {
+	for word := range SpanishStopWords {
+		assert.Len(t, strings.Fields(word), 1, "stopword '%s' can never match a token", word)
+	}
+}
+
+func TestCommonGramsFilter(t *testing.T) {
+	filter := NewCommonGramsFilter(NewStopWords("the"))
+	assert.Equal(t,
+		[]string{"the", "the_who", "who", "live"},
+		filter.Filter([]string{"the", "who", "live"}),
+	)
+
+	tokens := filter.FilterStream([]Token{
+		{Term: "meet", Position: 0, PositionIncrement: 1, Start: 0, End: 4},
+		{Term: "the", Position: 1, PositionIncrement: 1, Start: 5, End: 8},
+		{Term: "who", Position: 2, PositionIncrement: 1, Start: 9, End: 12},
+	})
+	assert.Equal(t, []Token{
+		{Term: "meet", Position: 0, PositionIncrement: 1, Start: 0, End: 4},
+		{
+			Term:           "meet_the",
+			Position:       0,
+			PositionLength: 2,
+			Start:          0,
+			End:            8,
+			Type:           TokenGram,
+		},
+		{Term: "the", Position: 1, PositionIncrement: 1, Start: 5, End: 8},
+		{
+			Term:           "the_who",
+			Position:       1,
+			PositionLength: 2,
+			Start:          5,
+			End:            12,
+			Type:           TokenGram,
+		},
+		{Term: "who", Position: 2, PositionIncrement: 1, Start: 9, End: 12},
+	}, tokens)
+}
+
+func TestCommonGramsFilter_Search(t *testing.T) {
+	common := NewStopWords("the", "to", "be", "or", "not")
+	analyzer := NewTokenizationPipeline(
+		NewUnicodeTokenizer(),
+		NewLowerCaseTokenizer(),
+		NewCommonGramsFilter(common),
+		NewStopWordsFilter(common),
+	)
+	in := NewMemoryIndex("quotes", analyzer)
+	in.Put(NewDocRequest("hamlet", "To be, or not to be"))
+	in.Put(NewDocRequest("band", "The Who live"))
+	in.Put(NewDocRequest("question", "Who will be there"))
+
+	results := in.Search("to be or not", HitsSearch)
+	assert.Equal(t, 1, results.Len(), "phrases of stopwords should be searchable")
+	res, _ := results.Get(0)
+	assert.Equal(t, "hamlet", res.Doc().ID())
+
+	results = in.Search("the who", HitsSearch)
+	assert.Equal(t, 1, results.Len())
+	res, _ = results.Get(0)
+	assert.Equal(t, "band", res.Doc().ID())
+
+	assert.Equal(t, 2, in.Search("who", HitsSearch).Len())
+}
+
+func TestAnalyzerRegistry_StopWordsParams(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "custom.txt")
+	assert.NoError(t, os.WriteFile(path, []byte("curso | course\n"), 0o600))
+
+	registry := NewAnalyzerRegistry()
+	assert.NoError(t, registry.Define("courses", AnalyzerConfig{
+		Tokenizer: ComponentConfig{Type: "standard"},
+		Filters: []ComponentConfig{
+			{Type: "lowercase"},
+			{Type: "stopwords", Params: ComponentParams{
+				"language":       "spanish",
+				"stopwords":      []any{"online"},
+				"stopwords_path": path,
+				"exclude":        []any{"no"},
+			}},
+		},
+	}))
+	analyzer, err := registry.Analyzer("courses")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"no", "programar"}, analyzer.Tokenize("Curso online de no programar"))
+
+	assert.Error(t, registry.Define("missing", AnalyzerConfig{
+		Tokenizer: ComponentConfig{Type: "standard"},
+		Filters: []ComponentConfig{{
+			Type:   "stopwords",
+			Params: ComponentParams{"stopwords_path": filepath.Join(t.TempDir(), "missing.txt")},
+		}},
+	}))
+}
@@ -0,0 +1,324 @@
+package visigoth
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// SynonymMap holds synonym rules, from a sequence of one or more terms to the
+// sequences of terms that replace it.
+type SynonymMap struct {
+	rules map[string][][]string
+	// maxLen is the number of terms of the longest rule input.
+	maxLen int
+	expand bool
+}
+
+func (m *SynonymMap) add(input []string, output []string) {
+	key := strings.Join(input, " ")
+	for _, existing := range m.rules[key] {
+		if strings.Join(existing, " ") == strings.Join(output, " ") {
+			return
+		}
+	}
+	m.rules[key] = append(m.rules[key], output)
+	if len(input) > m.maxLen {
+		m.maxLen = len(input)
+	}
+}
+
+// Add replaces input with every one of outputs, as the Solr rule
+// "input => output1, output2" does. Multi-word inputs and outputs are written
+// with their terms separated by spaces. The input itself is kept only if it
+// is also listed as an output.
+func (m *SynonymMap) Add(input string, outputs ...string) {
+	in := strings.Fields(input)
+	if len(in) == 0 {
+		return
+	}
+	for _, output := range outputs {
+		if out := strings.Fields(output); len(out) > 0 {
+			m.add(in, out)
+		}
+	}
+}
+
+// AddEquivalent declares terms as equivalent. When the map expands, every
+// term is replaced by all of them, otherwise every term is replaced by the
+// first one.
+func (m *SynonymMap) AddEquivalent(terms ...string) {
+	if len(terms) == 0 {
+		return
+	}
+	for _, term := range terms {
+		if m.expand {
+			m.Add(term, terms...)
+		} else {
+			m.Add(term, terms[0])
+		}
+	}
+}
+
+// Len returns the number of distinct rule inputs.
+func (m *SynonymMap) Len() int {
+	return len(m.rules)
+}
+
+// NewSynonymMap returns an empty SynonymMap. Expand sets how equivalent terms
+// are handled by AddEquivalent.
+func NewSynonymMap(expand bool) *SynonymMap {
+	return &SynonymMap{rules: make(map[string][][]string), expand: expand}
+}
+
+// ParseSolrSynonyms reads synonyms in the Solr format: one rule per line,
+// either a comma separated list of equivalent terms, or explicit mappings
+// such as "i-pod, i pod => ipod". Blank lines and lines starting with '#' are
+// ignored.
+func ParseSolrSynonyms(r io.Reader, expand bool) (*SynonymMap, error) {
+	m := NewSynonymMap(expand)
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		rule := strings.TrimSpace(scanner.Text())
+		if rule == "" || strings.HasPrefix(rule, "#") {
+			continue
+		}
+		sides := strings.Split(rule, "=>")
+		switch len(sides) {
+		case 1:
+			m.AddEquivalent(splitSynonyms(sides[0])...)
+		case 2:
+			inputs, outputs := splitSynonyms(sides[0]), splitSynonyms(sides[1])
+			if len(inputs) == 0 || len(outputs) == 0 {
+				return nil, fmt.Errorf("invalid synonym rule at line %d: '%s'", line, rule)
+			}
+			for _, input := range inputs {
+				m.Add(input, outputs...)
+			}
+		default:
+			return nil, fmt.Errorf("invalid synonym rule at line %d: '%s'", line, rule)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func splitSynonyms(list string) []string {
+	var res []string
+	for _, term := range strings.Split(list, ",") {
+		if term = strings.Join(strings.Fields(term), " "); term != "" {
+			res = append(res, term)
+		}
+	}
+	return res
+}
+
+// ParseWordNetSynonyms reads synonyms in the WordNet prolog format, as found
+// in the wn_s.pl file, where every line holds a word of a synset:
+//
+//	s(106566077,1,'software',n,1,7).
+//
+// All the words of a synset are declared as equivalent.
+func ParseWordNetSynonyms(r io.Reader, expand bool) (*SynonymMap, error) {
+	var (
+		m        = NewSynonymMap(expand)
+		synset   string
+		words    []string
+		scanner  = bufio.NewScanner(r)
+		flushSet = func() {
+			if len(words) > 1 {
+				m.AddEquivalent(words...)
+			}
+			words = words[:0]
+		}
+	)
+	for line := 1; scanner.Scan(); line++ {
+		entry := strings.TrimSpace(scanner.Text())
+		if entry == "" {
+			continue
+		}
+		id, word, ok := parseWordNetEntry(entry)
+		if !ok {
+			return nil, fmt.Errorf("invalid wordnet entry at line %d: '%s'", line, entry)
+		}
+		if id != synset {
+			flushSet()
+			synset = id
+		}
+		words = append(words, word)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	flushSet()
+	return m, nil
+}
+
+// parseWordNetEntry extracts the synset id and the word, in which quotes are
+// escaped by doubling them, from a line such as s(100001740,1,'entity',n,1,11).
+func parseWordNetEntry(entry string) (id, word string, ok bool) {
+	if !strings.HasPrefix(entry, "s(") || !strings.HasSuffix(entry, ").") {
+		return "", "", false
+	}
+	entry = entry[len("s(") : len(entry)-len(").")]
+	comma := strings.IndexByte(entry, ',')
+	quote := strings.IndexByte(entry, '\'')
+	if comma <= 0 || quote < 0 {
+		return "", "", false
+	}
+	var buf strings.Builder
+	for i := quote + 1; i < len(entry); i++ {
+		if entry[i] != '\'' {
+			buf.WriteByte(entry[i])
+			continue
+		}
+		if i+1 < len(entry) && entry[i+1] == '\'' {
+			buf.WriteByte('\'')
+			i++
+			continue
+		}
+		return entry[:comma], buf.String(), buf.Len() > 0
+	}
+	return "", "", false
+}
+
+// SynonymFilter injects synonyms into the token stream. Rules must be written
+// with the terms produced by the filters running before it, usually lowercase.
+//
+// The same filter may run at index time, so that documents get indexed under
+// every synonym, or at query time, so that queries look for any of them:
+// synonyms stacked at the same position are searched as alternatives rather
+// than as terms that must all be found.
+//
+// Multi-word synonyms keep positions consistent for phrase matching: the
+// alternatives of a match start at the same position, and those shorter than
+// the longest one span the remaining positions through PositionLength. When an
+// alternative is longer than the matched terms, positions of the following
+// tokens are shifted accordingly.
+type SynonymFilter struct {
+	synonyms *SynonymMap
+}
+
+func (s SynonymFilter) Filter(tokens []string) []string {
+	stream := make([]Token, len(tokens))
+	for i, term := range tokens {
+		stream[i] = Token{Term: term, Position: i, PositionIncrement: 1, Type: tokenType(term)}
+	}
+	return Terms(s.FilterStream(stream))
+}
+
+func (s SynonymFilter) FilterStream(tokens []Token) []Token {
+	if len(tokens) == 0 || s.synonyms.Len() == 0 {
+		return tokens
+	}
+	var (
+		res   = make([]Token, 0, len(tokens))
+		shift int
+		prev  = tokens[0].Position - tokens[0].PositionIncrement
+	)
+	emit := func(tok Token) {
+		tok.PositionIncrement = tok.Position - prev
+		prev = tok.Position
+		res = append(res, tok)
+	}
+
+	for i := 0; i < len(tokens); {
+		n, alternatives := s.match(tokens[i:])
+		if n == 0 {
+			tok := tokens[i]
+			tok.Position += shift
+			emit(tok)
+			i++
+			continue
+		}
+
+		matched := tokens[i : i+n]
+		span := 0
+		for _, alt := range alternatives {
+			span = max(span, len(alt))
+		}
+		start := matched[0].Position + shift
+		for k := 0; k < span; k++ {
+			for _, alt := range alternatives {
+				if k >= len(alt) {
+					continue
+				}
+				var tok Token
+				if len(alt) == n && isOriginal(matched, alt) {
+					tok = matched[k]
+				} else {
+					tok = Token{
+						Term:  alt[k],
+						Start: matched[0].Start,
+						End:   matched[n-1].End,
+						Type:  TokenSynonym,
+					}
+					if len(alt) == n {
+						tok.Start, tok.End = matched[k].Start, matched[k].End
+					}
+				}
+				tok.Position = start + k
+				if k == len(alt)-1 && len(alt) < span {
+					tok.PositionLength = span - len(alt) + 1
+				}
+				emit(tok)
+			}
+		}
+		shift += span - n
+		i += n
+	}
+	return res
+}
+
+// match finds the longest rule input at the beginning of tokens, returning the
+// number of tokens it covers and its alternatives, the original terms first.
+// Tokens separated by gaps, such as removed stopwords, never match together.
+func (s SynonymFilter) match(tokens []Token) (int, [][]string) {
+	for n := min(s.synonyms.maxLen, len(tokens)); n > 0; n-- {
+		terms := make([]string, n)
+		contiguous := true
+		for k, tok := range tokens[:n] {
+			if k > 0 && tok.PositionIncrement != 1 {
+				contiguous = false
+				break
+			}
+			terms[k] = tok.Term
+		}
+		if !contiguous {
+			continue
+		}
+		outputs, ok := s.synonyms.rules[strings.Join(terms, " ")]
+		if !ok {
+			continue
+		}
+		alternatives := make([][]string, 0, len(outputs))
+		for _, out := range outputs {
+			if isOriginal(tokens[:n], out) {
+				alternatives = append([][]string{out}, alternatives...)
+			} else {
+				alternatives = append(alternatives, out)
+			}
+		}
+		return n, alternatives
+	}
+	return 0, nil
+}
+
+func isOriginal(tokens []Token, terms []string) bool {
+	if len(tokens) != len(terms) {
+		return false
+	}
+	for i, tok := range tokens {
+		if tok.Term != terms[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func NewSynonymFilter(synonyms *SynonymMap) SynonymFilter {
+	return SynonymFilter{synonyms: synonyms}
+}
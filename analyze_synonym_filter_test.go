@@ -0,0 +1,176 @@
+package visigoth
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const solrSynonyms = `
+# programming languages
+js, javascript
+golang => go
+
+machine learning, ml
+`
+
+func TestParseSolrSynonyms(t *testing.T) {
+	synonyms, err := ParseSolrSynonyms(strings.NewReader(solrSynonyms), true)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, synonyms.Len())
+
+	filter := NewSynonymFilter(synonyms)
+	assert.Equal(t,
+		[]string{"learn", "js", "javascript", "and", "go"},
+		filter.Filter([]string{"learn", "js", "and", "golang"}),
+	)
+
+	_, err = ParseSolrSynonyms(strings.NewReader("a => b => c"), true)
+	assert.Error(t, err)
+	_, err = ParseSolrSynonyms(strings.NewReader("a =>"), true)
+	assert.Error(t, err)
+}
+
+func TestParseSolrSynonyms_NoExpand(t *testing.T) {
+	synonyms, err := ParseSolrSynonyms(strings.NewReader(solrSynonyms), false)
+	assert.NoError(t, err)
+
+	filter := NewSynonymFilter(synonyms)
+	assert.Equal(t,
+		[]string{"js", "js", "machine", "learning"},
+		filter.Filter([]string{"javascript", "js", "ml"}),
+	)
+}
+
+func TestParseWordNetSynonyms(t *testing.T) {
+	wordnet := `s(106566077,1,'software',n,1,7).
+s(106566077,2,'software program',n,1,0).
+s(106566077,3,'computer software',n,1,0).
+s(100001740,1,'entity',n,1,11).
+s(104050410,1,'o''brien',n,1,0).
+`
+	synonyms, err := ParseWordNetSynonyms(strings.NewReader(wordnet), true)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, synonyms.Len(), "single word synsets should not produce rules")
+
+	filter := NewSynonymFilter(synonyms)
+	assert.Equal(t,
+		[]string{"software", "software", "computer", "program", "software"},
+		filter.Filter([]string{"software"}),
+	)
+
+	_, err = ParseWordNetSynonyms(strings.NewReader("s(1,1,entity,n,1,1)."), true)
+	assert.Error(t, err)
+}
+
+func TestSynonymFilter_MultiWordPositions(t *testing.T) {
+	synonyms := NewSynonymMap(true)
+	synonyms.AddEquivalent("machine learning", "ml")
+	analyzer := NewTokenizationPipeline(
+		NewUnicodeTokenizer(),
+		NewLowerCaseTokenizer(),
+		NewSynonymFilter(synonyms),
+	)
+
+	tokens := analyzer.TokenStream("Machine learning course")
+	assert.Equal(t, []Token{
+		{Term: "machine", Position: 0, PositionIncrement: 1, Start: 0, End: 7, Type: TokenWord},
+		{
+			Term:              "ml",
+			Position:          0,
+			PositionIncrement: 0,
+			PositionLength:    2,
+			Start:             0,
+			End:               16,
+			Type:              TokenSynonym,
+		},
+		{Term: "learning", Position: 1, PositionIncrement: 1, Start: 8, End: 16, Type: TokenWord},
+		{Term: "course", Position: 2, PositionIncrement: 1, Start: 17, End: 23, Type: TokenWord},
+	}, tokens)
+
+	tokens = analyzer.TokenStream("ML course")
+	assert.Equal(t, []Token{
+		{
+			Term:              "ml",
+			Position:          0,
+			PositionIncrement: 1,
+			PositionLength:    2,
+			Start:             0,
+			End:               2,
+			Type:              TokenWord,
+		},
+		{Term: "machine", Position: 0, PositionIncrement: 0, Start: 0, End: 2, Type: TokenSynonym},
+		{Term: "learning", Position: 1, PositionIncrement: 1, Start: 0, End: 2, Type: TokenSynonym},
+		{Term: "course", Position: 2, PositionIncrement: 1, Start: 3, End: 9, Type: TokenWord},
+	}, tokens, "following tokens should be shifted past the longest synonym")
+}
+
+func TestSynonymFilter_Gaps(t *testing.T) {
+	synonyms := NewSynonymMap(true)
+	synonyms.AddEquivalent("machine learning", "ml")
+	analyzer := NewTokenizationPipeline(
+		NewUnicodeTokenizer(),
+		NewLowerCaseTokenizer(),
+		NewStopWordsFilter(StopWords{"the": {}}),
+		NewSynonymFilter(synonyms),
+	)
+	assert.Equal(t,
+		[]string{"machine", "learning"},
+		analyzer.Tokenize("machine the learning"),
+		"tokens separated by removed stopwords should not match",
+	)
+}
+
+func TestSynonymFilter_Search(t *testing.T) {
+	synonyms := NewSynonymMap(true)
+	synonyms.AddEquivalent("js", "javascript")
+	synonyms.AddEquivalent("machine learning", "ml")
+	analyzer := NewTokenizationPipeline(
+		NewUnicodeTokenizer(),
+		NewLowerCaseTokenizer(),
+		NewSynonymFilter(synonyms),
+	)
+	in := NewMemoryIndex("courses", analyzer)
+	in.Put(NewDocRequest("js", "Javascript for beginners"))
+	in.Put(NewDocRequest("ml", "Introduction to ML"))
+
+	results := in.Search("js", HitsSearch)
+	assert.Equal(t, 1, results.Len())
+	res, _ := results.Get(0)
+	assert.Equal(t, "js", res.Doc().ID())
+
+	results = in.Search("machine learning", HitsSearch)
+	assert.Equal(t, 1, results.Len())
+	res, _ = results.Get(0)
+	assert.Equal(t, "ml", res.Doc().ID())
+}
+
+func TestSynonymFilter_QueryTimeExpansion(t *testing.T) {
+	synonyms := NewSynonymMap(true)
+	synonyms.AddEquivalent("coche", "auto", "carro")
+	indexAnalyzer := NewTokenizationPipeline(NewUnicodeTokenizer(), NewLowerCaseTokenizer())
+	in := NewMemoryIndex("anuncios", indexAnalyzer, WithSearchTokenizer(NewTokenizationPipeline(
+		NewUnicodeTokenizer(),
+		NewLowerCaseTokenizer(),
+		NewSynonymFilter(synonyms),
+	)))
+	in.Put(NewDocRequest("es", "Coche rojo"))
+	in.Put(NewDocRequest("ar", "Auto rojo"))
+	in.Put(NewDocRequest("mx", "Carro azul"))
+
+	// Expanded queries match documents with any of the synonyms
+	for _, engine := range []Engine{HitsSearch, LinearSearch} {
+		var ids []string
+		for _, res := range in.Search("coche", engine) {
+			ids = append(ids, res.Doc().ID())
+		}
+		assert.ElementsMatch(t, []string{"es", "ar", "mx"}, ids)
+
+		ids = nil
+		for _, res := range in.Search("auto rojo", engine) {
+			ids = append(ids, res.Doc().ID())
+		}
+		assert.ElementsMatch(t, []string{"es", "ar"}, ids)
+	}
+}
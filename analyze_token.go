@@ -0,0 +1,208 @@
+package visigoth
+
+import "strings"
+
+type TokenType string
+
+const (
+	TokenWord   TokenType = "word"
+	TokenNumber TokenType = "number"
+	// TokenSynonym tokens are injected by the SynonymFilter.
+	TokenSynonym TokenType = "synonym"
+	// TokenGram tokens join two adjacent terms, see CommonGramsFilter.
+	TokenGram TokenType = "gram"
+)
+
+// Token is a term along with the attributes the analysis pipeline keeps
+// track of.
+type Token struct {
+	Term string `json:"term"`
+	// Position is the ordinal of the token in the text. Tokens removed by a
+	// filter leave gaps, tokens injected at the same place share it.
+	Position int `json:"position"`
+	// PositionIncrement is the distance to the position of the previous token.
+	PositionIncrement int `json:"position_increment"`
+	// PositionLength is the number of positions the token spans, when more
+	// than one, as "ml" does when injected over "machine learning".
+	PositionLength int `json:"position_length,omitempty"`
+	// Start and End are the byte offsets [Start, End) of the token in the
+	// original text.
+	Start int       `json:"start"`
+	End   int       `json:"end"`
+	Type  TokenType `json:"type"`
+	// Keyword tokens are protected from being modified by stemmers.
+	Keyword bool `json:"keyword,omitempty"`
+}
+
+// StreamTokenizer is implemented by tokenizers able to keep token attributes.
+type StreamTokenizer interface {
+	TokenStream(text string) []Token
+}
+
+// StreamFilter is implemented by filters able to keep token attributes.
+type StreamFilter interface {
+	FilterStream(tokens []Token) []Token
+}
+
+// Terms returns the bare terms of the tokens.
+func Terms(tokens []Token) []string {
+	res := make([]string, len(tokens))
+	for i, tok := range tokens {
+		res[i] = tok.Term
+	}
+	return res
+}
+
+// tokenType guesses the type of plain terms.
+func tokenType(term string) TokenType {
+	if term == "" {
+		return TokenWord
+	}
+	for _, r := range term {
+		if r < '0' || r > '9' {
+			return TokenWord
+		}
+	}
+	return TokenNumber
+}
+
+// removeTokens drops tokens for which remove returns true, carrying their
+// position increment over the next kept token.
+func removeTokens(tokens []Token, remove func(tok Token) bool) []Token {
+	var (
+		res   = make([]Token, 0, len(tokens))
+		carry int
+	)
+	for _, tok := range tokens {
+		if remove(tok) {
+			carry += tok.PositionIncrement
+			continue
+		}
+		tok.PositionIncrement += carry
+		carry = 0
+		res = append(res, tok)
+	}
+	return res
+}
+
+type tokenizerAdapter struct {
+	Tokenizer
+}
+
+// TokenStream locates every term produced by the wrapped tokenizer in the
+// text, in order, to recover its offsets. Terms not found verbatim in the
+// text are given the offsets of the previous token.
+func (a tokenizerAdapter) TokenStream(text string) []Token {
+	terms := a.Tokenize(text)
+	res := make([]Token, len(terms))
+	cursor, start := 0, 0
+	for i, term := range terms {
+		if offset := strings.Index(text[cursor:], term); offset >= 0 {
+			start = cursor + offset
+			cursor = start + len(term)
+		}
+		res[i] = Token{
+			Term:              term,
+			Position:          i,
+			PositionIncrement: 1,
+			Start:             start,
+			End:               cursor,
+			Type:              tokenType(term),
+		}
+	}
+	return res
+}
+
+// AdaptTokenizer turns any Tokenizer into a StreamTokenizer. Tokenizers that
+// already are are returned as is.
+func AdaptTokenizer(t Tokenizer) StreamTokenizer {
+	if st, ok := t.(StreamTokenizer); ok {
+		return st
+	}
+	return tokenizerAdapter{Tokenizer: t}
+}
+
+type filterAdapter struct {
+	Filter
+}
+
+// FilterStream runs the wrapped filter over all the terms at once. When it
+// keeps a 1:1 correspondence between input and output terms, only terms are
+// replaced. Otherwise every token is filtered on its own: tokens filtered out
+// are removed, and extra terms are injected at the position of the token they
+// originate from.
+func (a filterAdapter) FilterStream(tokens []Token) []Token {
+	terms := a.Filter.Filter(Terms(tokens))
+	if len(terms) == len(tokens) {
+		res := make([]Token, len(tokens))
+		for i, tok := range tokens {
+			if terms[i] != tok.Term && !tok.Keyword {
+				tok.Term = terms[i]
+			}
+			res[i] = tok
+		}
+		return res
+	}
+
+	var (
+		res   = make([]Token, 0, len(tokens))
+		carry int
+	)
+	for _, tok := range tokens {
+		terms := a.Filter.Filter([]string{tok.Term})
+		if len(terms) == 0 {
+			carry += tok.PositionIncrement
+			continue
+		}
+		for i, term := range terms {
+			next := tok
+			next.Term = term
+			if i == 0 {
+				next.PositionIncrement += carry
+				carry = 0
+			} else {
+				next.PositionIncrement = 0
+			}
+			res = append(res, next)
+		}
+	}
+	return res
+}
+
+// AdaptFilter turns any Filter into a StreamFilter. Filters that already are
+// are returned as is.
+func AdaptFilter(f Filter) StreamFilter {
+	if sf, ok := f.(StreamFilter); ok {
+		return sf
+	}
+	return filterAdapter{Filter: f}
+}
+
+// KeywordMarkerFilter flags the given words as keywords so that later filters,
+// stemmers mainly, leave them untouched.
+type KeywordMarkerFilter struct {
+	keywords map[string]struct{}
+}
+
+func (k KeywordMarkerFilter) Filter(tokens []string) []string {
+	return tokens
+}
+
+func (k KeywordMarkerFilter) FilterStream(tokens []Token) []Token {
+	res := make([]Token, len(tokens))
+	for i, tok := range tokens {
+		if _, ok := k.keywords[tok.Term]; ok {
+			tok.Keyword = true
+		}
+		res[i] = tok
+	}
+	return res
+}
+
+func NewKeywordMarkerFilter(keywords ...string) KeywordMarkerFilter {
+	k := KeywordMarkerFilter{keywords: make(map[string]struct{}, len(keywords))}
+	for _, keyword := range keywords {
+		k.keywords[keyword] = struct{}{}
+	}
+	return k
+}
@@ -0,0 +1,76 @@
+package visigoth
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenizationPipeline_TokenStream(t *testing.T) {
+	pipeline := NewTokenizationPipeline(
+		NewKeepAlphanumericTokenizer(),
+		NewLowerCaseTokenizer(),
+		NewStopWordsFilter(SpanishStopWords),
+		NewSpanishStemmer(true),
+	)
+	text := "Curso de Programación en Java 2024"
+
+	tokens := pipeline.TokenStream(text)
+	assert.Equal(t, []Token{
+		{Term: "curs", Position: 0, PositionIncrement: 1, Start: 0, End: 5, Type: TokenWord},
+		{Term: "program", Position: 2, PositionIncrement: 2, Start: 9, End: 22, Type: TokenWord},
+		{Term: "jav", Position: 4, PositionIncrement: 2, Start: 26, End: 30, Type: TokenWord},
+		{Term: "2024", Position: 5, PositionIncrement: 1, Start: 31, End: 35, Type: TokenNumber},
+	}, tokens)
+	assert.Equal(t, "Programación", text[tokens[1].Start:tokens[1].End])
+	assert.Equal(t, Terms(tokens), pipeline.Tokenize(text))
+}
+
+func TestKeywordMarkerFilter(t *testing.T) {
+	pipeline := NewTokenizationPipeline(
+		NewKeepAlphanumericTokenizer(),
+		NewLowerCaseTokenizer(),
+		NewKeywordMarkerFilter("java"),
+		NewSpanishStemmer(true),
+	)
+
+	tokens := pipeline.TokenStream("Java programación")
+	assert.Equal(t, []string{"java", "program"}, Terms(tokens))
+	assert.True(t, tokens[0].Keyword)
+	assert.False(t, tokens[1].Keyword)
+}
+
+// fieldsTokenizer is a plain Tokenizer, unaware of token attributes.
+type fieldsTokenizer struct{}
+
+func (fieldsTokenizer) Tokenize(text string) []string {
+	return strings.Fields(text)
+}
+
+// duplicateFilter is a plain Filter which does not keep a 1:1 relation
+// between input and output terms.
+type duplicateFilter struct{}
+
+func (duplicateFilter) Filter(tokens []string) []string {
+	var res []string
+	for _, tok := range tokens {
+		if tok == "skip" {
+			continue
+		}
+		res = append(res, tok, tok+"!")
+	}
+	return res
+}
+
+func TestAdapters(t *testing.T) {
+	pipeline := NewTokenizationPipeline(fieldsTokenizer{}, duplicateFilter{})
+
+	tokens := pipeline.TokenStream("uno  skip dos")
+	assert.Equal(t, []Token{
+		{Term: "uno", Position: 0, PositionIncrement: 1, Start: 0, End: 3, Type: TokenWord},
+		{Term: "uno!", Position: 0, PositionIncrement: 0, Start: 0, End: 3, Type: TokenWord},
+		{Term: "dos", Position: 2, PositionIncrement: 2, Start: 10, End: 13, Type: TokenWord},
+		{Term: "dos!", Position: 2, PositionIncrement: 0, Start: 10, End: 13, Type: TokenWord},
+	}, tokens)
+}
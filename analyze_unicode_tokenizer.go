@@ -0,0 +1,457 @@
+package visigoth
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	TokenEmail       TokenType = "email"
+	TokenURL         TokenType = "url"
+	TokenHashtag     TokenType = "hashtag"
+	TokenIdeographic TokenType = "ideographic"
+)
+
+// wordBreak is the Word_Break property of a rune, as defined by UAX#29
+// https://www.unicode.org/reports/tr29/#Word_Boundaries
+type wordBreak uint8
+
+const (
+	wbOther wordBreak = iota
+	wbCR
+	wbLF
+	wbNewline
+	wbExtend
+	wbZWJ
+	wbRegionalIndicator
+	wbFormat
+	wbKatakana
+	wbHebrewLetter
+	wbALetter
+	wbSingleQuote
+	wbDoubleQuote
+	wbMidNumLet
+	wbMidLetter
+	wbMidNum
+	wbNumeric
+	wbExtendNumLet
+	wbWSegSpace
+	// wbIdeographic is not a Word_Break value but a subset of Other: Han and
+	// Hiragana characters, which form one word each.
+	wbIdeographic
+)
+
+// wordBreakOf approximates the Word_Break property of r from the Unicode
+// categories and scripts known to the unicode package.
+func wordBreakOf(r rune) wordBreak {
+	switch r {
+	case '\r':
+		return wbCR
+	case '\n':
+		return wbLF
+	case '\v', '\f', 0x85, 0x2028, 0x2029:
+		return wbNewline
+	case 0x200D:
+		return wbZWJ
+	case '\'':
+		return wbSingleQuote
+	case '"':
+		return wbDoubleQuote
+	case '.', 0x2018, 0x2019, 0x2024, 0xFE52, 0xFF07, 0xFF0E:
+		return wbMidNumLet
+	case ':', 0xB7, 0x387, 0x55F, 0x5F4, 0x2027, 0xFE13, 0xFE55, 0xFF1A:
+		return wbMidLetter
+	case ',', ';', 0x37E, 0x589, 0x60C, 0x60D, 0x66C, 0x7F8, 0x2044, 0xFE10, 0xFE14, 0xFE50,
+		0xFE54, 0xFF0C, 0xFF1B:
+		return wbMidNum
+	case 0x202F:
+		return wbExtendNumLet
+	case 0x30FC, 0x30FD, 0x30FE, 0x30FF, 0x3031, 0x3032, 0x3033, 0x3034, 0x3035, 0x309B, 0x309C:
+		return wbKatakana
+	}
+	switch {
+	case r >= 0x1F1E6 && r <= 0x1F1FF:
+		return wbRegionalIndicator
+	case r == ' ' || r == 0x1680 || (r >= 0x2000 && r <= 0x200A && r != 0x2007) ||
+		r == 0x205F || r == 0x3000:
+		return wbWSegSpace
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc) || r == 0x200C ||
+		(r >= 0x1F3FB && r <= 0x1F3FF):
+		return wbExtend
+	case unicode.Is(unicode.Cf, r) && r != 0x200B && r != 0x2060 && r != 0xFEFF:
+		return wbFormat
+	case unicode.Is(unicode.Katakana, r):
+		return wbKatakana
+	case unicode.In(r, unicode.Han, unicode.Hiragana):
+		return wbIdeographic
+	case unicode.Is(unicode.Hebrew, r) && unicode.IsLetter(r):
+		return wbHebrewLetter
+	case unicode.In(r, unicode.Thai, unicode.Lao, unicode.Myanmar, unicode.Khmer):
+		// Complex context scripts need dictionary based segmentation
+		return wbOther
+	case unicode.IsLetter(r) || unicode.Is(unicode.Nl, r):
+		return wbALetter
+	case unicode.Is(unicode.Nd, r):
+		return wbNumeric
+	case unicode.Is(unicode.Pc, r):
+		return wbExtendNumLet
+	}
+	return wbOther
+}
+
+func isExtendedPictographic(r rune) bool {
+	return (r >= 0x1F000 && r <= 0x1FAFF) || (r >= 0x2600 && r <= 0x27BF) || r == 0xA9 || r == 0xAE
+}
+
+func (wb wordBreak) ignorable() bool {
+	return wb == wbExtend || wb == wbFormat || wb == wbZWJ
+}
+
+func (wb wordBreak) ahLetter() bool {
+	return wb == wbALetter || wb == wbHebrewLetter
+}
+
+func (wb wordBreak) midNumLetQ() bool {
+	return wb == wbMidNumLet || wb == wbSingleQuote
+}
+
+func (wb wordBreak) newline() bool {
+	return wb == wbCR || wb == wbLF || wb == wbNewline
+}
+
+type segmentRune struct {
+	r      rune
+	offset int
+	wb     wordBreak
+}
+
+// segment is a byte range [start, end) of text delimited by word boundaries.
+type segment struct {
+	start int
+	end   int
+	// kind is the Word_Break property that best describes the segment.
+	kind wordBreak
+}
+
+// wordSegments splits text at every word boundary as defined by UAX#29.
+func wordSegments(text string) []segment {
+	runes := make([]segmentRune, 0, len(text))
+	for i, r := range text {
+		runes = append(runes, segmentRune{r: r, offset: i, wb: wordBreakOf(r)})
+	}
+	if len(runes) == 0 {
+		return nil
+	}
+
+	// prev returns the index of the closest rune before i not ignored by WB4.
+	prev := func(i int) int {
+		for i--; i >= 0 && runes[i].wb.ignorable(); i-- {
+		}
+		return i
+	}
+	// next returns the index of the closest rune after i not ignored by WB4.
+	next := func(i int) int {
+		for i++; i < len(runes) && runes[i].wb.ignorable(); i++ {
+		}
+		return i
+	}
+	wbAt := func(i int) wordBreak {
+		if i < 0 || i >= len(runes) {
+			return wbOther
+		}
+		return runes[i].wb
+	}
+
+	isBreak := func(i int) bool {
+		left, right := runes[i-1].wb, runes[i].wb
+		switch {
+		case left == wbCR && right == wbLF: // WB3
+			return false
+		case left.newline() || right.newline(): // WB3a, WB3b
+			return true
+		case left == wbZWJ && isExtendedPictographic(runes[i].r): // WB3c
+			return false
+		case left == wbWSegSpace && right == wbWSegSpace: // WB3d
+			return false
+		case right.ignorable(): // WB4
+			return false
+		}
+
+		p := prev(i)
+		left = wbAt(p)
+		if p < 0 {
+			return true
+		}
+		left2, right2 := wbAt(prev(p)), wbAt(next(i))
+		switch {
+		case left.ahLetter() && right.ahLetter(): // WB5
+			return false
+		case left.ahLetter() && (right == wbMidLetter || right.midNumLetQ()) && right2.ahLetter(): // WB6
+			return false
+		case left2.ahLetter() && (left == wbMidLetter || left.midNumLetQ()) && right.ahLetter(): // WB7
+			return false
+		case left == wbHebrewLetter && right == wbSingleQuote: // WB7a
+			return false
+		case left == wbHebrewLetter && right == wbDoubleQuote && right2 == wbHebrewLetter: // WB7b
+			return false
+		case left2 == wbHebrewLetter && left == wbDoubleQuote && right == wbHebrewLetter: // WB7c
+			return false
+		case left == wbNumeric && right == wbNumeric: // WB8
+			return false
+		case left.ahLetter() && right == wbNumeric: // WB9
+			return false
+		case left == wbNumeric && right.ahLetter(): // WB10
+			return false
+		case left2 == wbNumeric && (left == wbMidNum || left.midNumLetQ()) && right == wbNumeric: // WB11
+			return false
+		case left == wbNumeric && (right == wbMidNum || right.midNumLetQ()) && right2 == wbNumeric: // WB12
+			return false
+		case left == wbKatakana && right == wbKatakana: // WB13
+			return false
+		case (left.ahLetter() || left == wbNumeric || left == wbKatakana || left == wbExtendNumLet) &&
+			right == wbExtendNumLet: // WB13a
+			return false
+		case left == wbExtendNumLet &&
+			(right.ahLetter() || right == wbNumeric || right == wbKatakana): // WB13b
+			return false
+		case left == wbRegionalIndicator && right == wbRegionalIndicator: // WB15, WB16
+			n := 0
+			for j := p; j >= 0 && wbAt(j) == wbRegionalIndicator; j = prev(j) {
+				n++
+			}
+			return n%2 == 0
+		}
+		return true // WB999
+	}
+
+	var (
+		res   []segment
+		start int
+		kind  = wbOther
+	)
+	for i := range runes {
+		if i > 0 && isBreak(i) {
+			res = append(res, segment{start: runes[start].offset, end: runes[i].offset, kind: kind})
+			start, kind = i, wbOther
+		}
+		kind = segmentKind(kind, runes[i].wb)
+	}
+	res = append(res, segment{start: runes[start].offset, end: len(text), kind: kind})
+	return res
+}
+
+// segmentKind folds the Word_Break property of every rune of a segment into
+// the one describing the segment: letters win over numbers, and numbers over
+// everything else.
+func segmentKind(kind wordBreak, wb wordBreak) wordBreak {
+	switch {
+	case wb.ahLetter() || wb == wbKatakana:
+		return wbALetter
+	case kind == wbALetter:
+		return kind
+	case wb == wbIdeographic || wb == wbNumeric:
+		return wb
+	case kind == wbIdeographic || kind == wbNumeric:
+		return kind
+	}
+	return wbOther
+}
+
+var (
+	urlPattern = regexp.MustCompile(
+		`^(?:[a-zA-Z][a-zA-Z0-9+.\-]*://|www\.)[^\s<>"'` + "`" + `]+`,
+	)
+	emailPattern = regexp.MustCompile(
+		`^[\p{L}\p{N}._%+\-]+@[\p{L}\p{N}\-]+(?:\.[\p{L}\p{N}\-]+)*\.\p{L}{2,}`,
+	)
+	hashtagPattern = regexp.MustCompile(`^#[\p{L}\p{N}_]*\p{L}[\p{L}\p{N}_]*`)
+)
+
+// UnicodeTokenizer splits text into words following the word boundary rules
+// of UAX#29, so that "O'Brien", "3.14" or "1,000" are kept as single tokens
+// and every Han or Hiragana character is a token of its own.
+//
+// On top of that, it recognizes emails, URLs, hashtags and programming
+// language names such as "C++" or "C#", keeping them whole.
+type UnicodeTokenizer struct{}
+
+func (u UnicodeTokenizer) Tokenize(text string) []string {
+	return Terms(u.TokenStream(text))
+}
+
+func (u UnicodeTokenizer) TokenStream(text string) []Token {
+	var (
+		res     []Token
+		cursor  int
+		special = newSpecialTokens(text)
+	)
+	emit := func(start, end int, typ TokenType) {
+		res = append(res, Token{
+			Term:              text[start:end],
+			Position:          len(res),
+			PositionIncrement: 1,
+			Start:             start,
+			End:               end,
+			Type:              typ,
+		})
+		cursor = end
+	}
+
+	for _, seg := range wordSegments(text) {
+		if seg.start < cursor {
+			continue
+		}
+		if end, typ, ok := special.match(seg); ok {
+			emit(seg.start, end, typ)
+			continue
+		}
+		switch seg.kind {
+		case wbALetter:
+			emit(seg.start, seg.end+languageNameSuffix(text[seg.end:]), TokenWord)
+		case wbNumeric:
+			emit(seg.start, seg.end, TokenNumber)
+		case wbIdeographic:
+			emit(seg.start, seg.end, TokenIdeographic)
+		}
+	}
+	return res
+}
+
+// specialTokens finds emails, URLs and hashtags starting at segments. It
+// looks no further than the whitespace following a segment, and remembers
+// what it found there for the next segments, so that long texts with no
+// whitespace, such as URLs or base64 strings, are tokenized in linear time.
+type specialTokens struct {
+	text string
+	// chunkEnd is where the whitespace following the last segment starts.
+	chunkEnd int
+	// scheme and at are the next "://" and "@" of the chunk.
+	scheme, at separator
+}
+
+func newSpecialTokens(text string) *specialTokens {
+	return &specialTokens{text: text, scheme: separator{pos: -1}, at: separator{pos: -1}}
+}
+
+// separator is a "://" or "@" found at pos, preceded since runStart by the
+// characters a URL scheme or an email local part is made of. pos is -1 before
+// searching, and the end of the chunk when the chunk has none.
+type separator struct {
+	pos      int
+	runStart int
+	// failed is set once no URL or email was found ending the run at pos,
+	// as none will be starting later in the run.
+	failed bool
+}
+
+// next updates sep to the first occurrence of sub at or after start, unless
+// it already is.
+func (sep *separator) next(text string, start, chunkEnd int, sub string, inRun func(rune) bool) {
+	if start <= sep.pos {
+		return
+	}
+	*sep = separator{pos: chunkEnd, runStart: chunkEnd}
+	if i := strings.Index(text[start:chunkEnd], sub); i >= 0 {
+		sep.pos = start + i
+		sep.runStart = sep.pos
+		for sep.runStart > 0 {
+			r, size := utf8.DecodeLastRuneInString(text[:sep.runStart])
+			if !inRun(r) {
+				break
+			}
+			sep.runStart -= size
+		}
+	}
+}
+
+// match checks whether an email, URL or hashtag starts at the segment,
+// returning where it ends.
+func (st *specialTokens) match(seg segment) (int, TokenType, bool) {
+	text := st.text
+	if seg.start >= st.chunkEnd {
+		st.chunkEnd = len(text)
+		if space := strings.IndexFunc(text[seg.start:], unicode.IsSpace); space >= 0 {
+			st.chunkEnd = seg.start + space
+		}
+	}
+	rest := text[seg.start:st.chunkEnd]
+
+	switch {
+	case seg.kind == wbOther && strings.HasPrefix(rest, "#"):
+		if seg.start > 0 {
+			// Hashtags must not be glued to a preceding word
+			r, _ := utf8.DecodeLastRuneInString(text[:seg.start])
+			if unicode.IsLetter(r) || unicode.IsNumber(r) {
+				return 0, "", false
+			}
+		}
+		if m := hashtagPattern.FindString(rest); m != "" {
+			return seg.start + len(m), TokenHashtag, true
+		}
+		return 0, "", false
+	case seg.kind != wbALetter && seg.kind != wbNumeric:
+		return 0, "", false
+	}
+
+	st.scheme.next(text, seg.start, st.chunkEnd, "://", isSchemeRune)
+	www := len(rest) >= 4 && strings.EqualFold(rest[:4], "www.")
+	if www || st.scheme.pos < st.chunkEnd {
+		// The scheme is all scheme characters from the segment on, so only
+		// what follows "://" may fail to match, as it would later in the run
+		scheme := seg.start >= st.scheme.runStart && !st.scheme.failed && isASCIILetter(rest[0])
+		if !www && !scheme {
+			return 0, "", false
+		}
+		if m := urlPattern.FindString(rest); m != "" {
+			m = strings.TrimRight(m, ".,;:!?)]}'")
+			return seg.start + len(m), TokenURL, true
+		}
+		if !www {
+			st.scheme.failed = true
+		}
+		return 0, "", false
+	}
+
+	st.at.next(text, seg.start, st.chunkEnd, "@", isLocalPartRune)
+	if st.at.pos < st.chunkEnd && seg.start >= st.at.runStart && !st.at.failed {
+		if m := emailPattern.FindString(rest); m != "" {
+			return seg.start + len(m), TokenEmail, true
+		}
+		st.at.failed = true
+	}
+	return 0, "", false
+}
+
+// isSchemeRune reports whether r belongs in a URL scheme.
+func isSchemeRune(r rune) bool {
+	return r < utf8.RuneSelf && (isASCIILetter(byte(r)) || strings.ContainsRune("0123456789+.-", r))
+}
+
+// isLocalPartRune reports whether r belongs in the local part of an email.
+func isLocalPartRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsNumber(r) || strings.ContainsRune("._%+-", r)
+}
+
+// languageNameSuffix returns the length of a "++" or "#" suffix, as in "C++"
+// or "F#", provided it is not followed by a letter or number.
+func languageNameSuffix(rest string) int {
+	var n int
+	switch {
+	case strings.HasPrefix(rest, "++"):
+		n = 2
+	case strings.HasPrefix(rest, "#"):
+		n = 1
+	default:
+		return 0
+	}
+	if r, _ := utf8.DecodeRuneInString(rest[n:]); unicode.IsLetter(r) || unicode.IsNumber(r) {
+		return 0
+	}
+	return n
+}
+
+func NewUnicodeTokenizer() UnicodeTokenizer {
+	return UnicodeTokenizer{}
+}
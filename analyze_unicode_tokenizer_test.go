@@ -0,0 +1,108 @@
+package visigoth
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnicodeTokenizer_Tokenize(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected []string
+	}{
+		{
+			text:     "Curso de programación en Java",
+			expected: []string{"Curso", "de", "programación", "en", "Java"},
+		},
+		{text: "O'Brien can't stop", expected: []string{"O'Brien", "can't", "stop"}},
+		{
+			text:     "pi vale 3.14 y e 2,718",
+			expected: []string{"pi", "vale", "3.14", "y", "e", "2,718"},
+		},
+		{text: "1,000.50 dollars", expected: []string{"1,000.50", "dollars"}},
+		{text: "send an e-mail", expected: []string{"send", "an", "e", "mail"}},
+		{text: "snake_case and U.S.A.", expected: []string{"snake_case", "and", "U.S.A"}},
+		{
+			text:     "I love C++ and C#, not c+",
+			expected: []string{"I", "love", "C++", "and", "C#", "not", "c"},
+		},
+		{
+			text:     "write to john.doe@example.com today",
+			expected: []string{"write", "to", "john.doe@example.com", "today"},
+		},
+		{
+			text:     "see https://example.com/a?b=1. Or www.example.org!",
+			expected: []string{"see", "https://example.com/a?b=1", "Or", "www.example.org"},
+		},
+		{
+			text:     "trending #golang #2024go, not issue#12",
+			expected: []string{"trending", "#golang", "#2024go", "not", "issue", "12"},
+		},
+		{
+			text:     "no user a.b@c, but x.y@mail.com or x—y@mail.com",
+			expected: []string{"no", "user", "a.b", "c", "but", "x.y@mail.com", "or", "x", "y@mail.com"},
+		},
+		{text: "git+ssh.a://<x", expected: []string{"git", "ssh.a", "x"}},
+		{text: "東京は日本", expected: []string{"東", "京", "は", "日", "本"}},
+		{text: "カタカナ word", expected: []string{"カタカナ", "word"}},
+		{text: "שלום עולם", expected: []string{"שלום", "עולם"}},
+		{text: "¡Hola!  ¿Qué tal?\r\n", expected: []string{"Hola", "Qué", "tal"}},
+		{text: "", expected: []string{}},
+	}
+
+	tokenizer := NewUnicodeTokenizer()
+	for _, test := range tests {
+		assert.Equal(t, test.expected, tokenizer.Tokenize(test.text), "text '%s'", test.text)
+	}
+}
+
+func TestUnicodeTokenizer_LongText(t *testing.T) {
+	// Text with no whitespace is scanned for special tokens once, rather than
+	// once per segment
+	text := strings.Repeat("aB3+/", 100_000) + "@mail.com://"
+	tokens := NewUnicodeTokenizer().Tokenize(text)
+	assert.Len(t, tokens, 100_001)
+	assert.Equal(t, "mail.com", tokens[len(tokens)-1])
+}
+
+func TestUnicodeTokenizer_TokenStream(t *testing.T) {
+	text := "Programación en C++ 3.14 #go http://go.dev 東"
+	tokens := NewUnicodeTokenizer().TokenStream(text)
+
+	assert.Equal(t, []TokenType{
+		TokenWord, TokenWord, TokenWord, TokenNumber, TokenHashtag, TokenURL, TokenIdeographic,
+	}, func() []TokenType {
+		var res []TokenType
+		for _, tok := range tokens {
+			res = append(res, tok.Type)
+		}
+		return res
+	}())
+	for i, tok := range tokens {
+		assert.Equal(t, i, tok.Position)
+		assert.Equal(
+			t,
+			tok.Term,
+			text[tok.Start:tok.End],
+			"offsets should point to the original text",
+		)
+	}
+}
+
+func TestUnicodeTokenizer_Pipeline(t *testing.T) {
+	analyzer := NewTokenizationPipeline(NewUnicodeTokenizer(), NewLowerCaseTokenizer())
+	in := NewMemoryIndex("testing", analyzer)
+	in.Put(NewDocRequest("cpp", "Curso de C++ moderno"))
+	in.Put(NewDocRequest("c", "Curso de C"))
+	in.Put(NewDocRequest("contact", "Escribe a info@example.com"))
+
+	results := in.Search("c++", HitsSearch)
+	assert.Equal(t, 1, results.Len())
+	assert.Equal(t, "cpp", results[0].Document.ID())
+
+	results = in.Search("info@example.com", HitsSearch)
+	assert.Equal(t, 1, results.Len())
+	assert.Equal(t, "contact", results[0].Document.ID())
+}
@@ -0,0 +1,55 @@
+package visigoth
+
+import (
+	"strings"
+	"unicode"
+)
+
+// WhitespaceTokenizer splits text on white space only, leaving punctuation
+// attached to terms.
+type WhitespaceTokenizer struct {
+	clean CleanTokenizer
+}
+
+func (w WhitespaceTokenizer) Tokenize(text string) []string {
+	return strings.Fields(text)
+}
+
+func (w WhitespaceTokenizer) TokenStream(text string) []Token {
+	return w.clean.TokenStream(text)
+}
+
+func NewWhitespaceTokenizer() WhitespaceTokenizer {
+	return WhitespaceTokenizer{clean: NewCleanTokenizer(func(r rune) bool {
+		return !unicode.IsSpace(r)
+	})}
+}
+
+// KeywordTokenizer emits the whole text as a single token, for identifiers,
+// tags or codes that must be matched exactly.
+type KeywordTokenizer struct{}
+
+func (k KeywordTokenizer) Tokenize(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return []string{text}
+}
+
+func (k KeywordTokenizer) TokenStream(text string) []Token {
+	if text == "" {
+		return nil
+	}
+	return []Token{{
+		Term:              text,
+		Position:          0,
+		PositionIncrement: 1,
+		Start:             0,
+		End:               len(text),
+		Type:              tokenType(text),
+	}}
+}
+
+func NewKeywordTokenizer() KeywordTokenizer {
+	return KeywordTokenizer{}
+}
@@ -0,0 +1,356 @@
+// Package client talks to visigoth servers over HTTP/JSON.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sonirico/visigoth"
+	"github.com/sonirico/visigoth/server"
+)
+
+const (
+	// DefaultURL is the address cmd/server listens on by default.
+	DefaultURL = "http://localhost:7374"
+	// DefaultMaxIdleConns is the number of connections to the server kept
+	// open for reuse.
+	DefaultMaxIdleConns = 16
+	// DefaultRetryBackoff is the wait before the first retry of a request,
+	// doubled on every further one.
+	DefaultRetryBackoff = 100 * time.Millisecond
+)
+
+// Client calls the API served by server.HTTPHandler. Errors returned by the
+// server are returned as *server.APIError.
+//
+// Clients are safe for concurrent use, and reuse connections to the server.
+type Client struct {
+	baseURL      *url.URL
+	http         *http.Client
+	transport    *http.Transport
+	timeout      time.Duration
+	retries      int
+	retryBackoff time.Duration
+	apiKey       string
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client requests are sent with, which then
+// handles the pool of connections. By default, requests are sent with a
+// client keeping up to DefaultMaxIdleConns connections open.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.http = httpClient
+	}
+}
+
+// WithMaxConns limits the number of connections to the server to maxConns,
+// keeping up to maxIdle of them open for reuse. Zero means no limit. It has
+// no effect along with WithHTTPClient.
+func WithMaxConns(maxConns, maxIdle int) Option {
+	return func(c *Client) {
+		c.transport.MaxConnsPerHost = maxConns
+		c.transport.MaxIdleConnsPerHost = maxIdle
+	}
+}
+
+// WithTimeout limits the time taken by every call, retries included. There is
+// no limit by default, other than that of the context of calls.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithRetries retries up to n times the requests which are safe to repeat,
+// those not sending documents, when the server cannot be reached or is
+// unavailable, waiting backoff before the first retry and twice as long
+// before every further one. Requests are not retried by default.
+func WithRetries(n int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.retries = n
+		c.retryBackoff = backoff
+	}
+}
+
+// WithAPIKey authenticates requests with the API key, for servers requiring
+// one.
+func WithAPIKey(key string) Option {
+	return func(c *Client) {
+		c.apiKey = key
+	}
+}
+
+// SearchParams tweak a search.
+type SearchParams struct {
+	// Engine is the name of the engine, the server's default if empty.
+	Engine string
+	From   int
+	// Size is the maximum number of results, all of them if negative.
+	Size      int
+	Languages []visigoth.Language
+}
+
+func (c *Client) Health(ctx context.Context) error {
+	return c.do(ctx, http.MethodGet, "/health", nil, nil, nil)
+}
+
+func (c *Client) Indices(ctx context.Context) ([]string, error) {
+	var res server.IndicesResponse
+	err := c.do(ctx, http.MethodGet, "/indices", nil, nil, &res)
+	return res.Indices, err
+}
+
+func (c *Client) CreateIndex(ctx context.Context, name string) error {
+	return c.do(ctx, http.MethodPut, indexPath(name), nil, nil, nil)
+}
+
+func (c *Client) DropIndex(ctx context.Context, name string) error {
+	return c.do(ctx, http.MethodDelete, indexPath(name), nil, nil, nil)
+}
+
+func (c *Client) RenameIndex(ctx context.Context, old, new string) error {
+	body, err := jsonBody(server.RenameRequest{Name: new})
+	if err != nil {
+		return err
+	}
+	return c.do(ctx, http.MethodPost, indexPath(old, "_rename"), nil, body, nil)
+}
+
+func (c *Client) PutDocument(ctx context.Context, index string, doc server.Document) error {
+	body, err := jsonBody(doc)
+	if err != nil {
+		return err
+	}
+	return c.do(ctx, http.MethodPost, indexPath(index, "docs"), nil, body, nil)
+}
+
+// Bulk indexes the documents read from r, one server.Document in JSON per
+// line. Documents failing to decode are reported in the response.
+func (c *Client) Bulk(ctx context.Context, index string, r io.Reader) (server.BulkResponse, error) {
+	var res server.BulkResponse
+	err := c.do(ctx, http.MethodPost, indexPath(index, "_bulk"), nil, r, &res)
+	return res, err
+}
+
+// SearchPage returns a page of the results of searching the index or alias.
+func (c *Client) SearchPage(
+	ctx context.Context,
+	index string,
+	terms string,
+	params SearchParams,
+) (server.SearchResponse, error) {
+	query := url.Values{"q": {terms}}
+	if params.Engine != "" {
+		query.Set("engine", params.Engine)
+	}
+	if params.From > 0 {
+		query.Set("from", strconv.Itoa(params.From))
+	}
+	if params.Size >= 0 {
+		query.Set("size", strconv.Itoa(params.Size))
+	}
+	for _, lang := range params.Languages {
+		query.Add("lang", string(lang))
+	}
+	var res server.SearchResponse
+	err := c.do(ctx, http.MethodGet, indexPath(index, "_search"), query, nil, &res)
+	return res, err
+}
+
+func (c *Client) Suggest(
+	ctx context.Context,
+	index string,
+	terms string,
+) (visigoth.Suggestion, error) {
+	var res visigoth.Suggestion
+	query := url.Values{"q": {terms}}
+	err := c.do(ctx, http.MethodGet, indexPath(index, "_suggest"), query, nil, &res)
+	return res, err
+}
+
+// Analyze details how the index, or the analyzer with that name, analyzes
+// the text.
+func (c *Client) Analyze(ctx context.Context, name, text string) (visigoth.Analysis, error) {
+	var res visigoth.Analysis
+	err := c.do(ctx, http.MethodPost, indexPath(name, "_analyze"), nil,
+		strings.NewReader(text), &res)
+	return res, err
+}
+
+func (c *Client) Aliases(ctx context.Context) ([]server.Alias, error) {
+	var res server.AliasesResponse
+	err := c.do(ctx, http.MethodGet, "/aliases", nil, nil, &res)
+	return res.Aliases, err
+}
+
+func (c *Client) AddAlias(ctx context.Context, alias, index string) error {
+	return c.do(ctx, http.MethodPut, aliasPath(alias, index), nil, nil, nil)
+}
+
+// RemoveAlias removes the index from the alias, or the whole alias if index
+// is empty.
+func (c *Client) RemoveAlias(ctx context.Context, alias, index string) error {
+	return c.do(ctx, http.MethodDelete, aliasPath(alias, index), nil, nil, nil)
+}
+
+func (c *Client) do(
+	ctx context.Context,
+	method string,
+	path string,
+	query url.Values,
+	body io.Reader,
+	out any,
+) error {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+	u := c.baseURL.JoinPath(path)
+	u.RawQuery = query.Encode()
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+	res, err := c.send(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= http.StatusBadRequest {
+		return decodeError(res)
+	}
+	if out == nil {
+		_, err = io.Copy(io.Discard, res.Body)
+		return err
+	}
+	return json.NewDecoder(res.Body).Decode(out)
+}
+
+// send sends the request, retrying it as configured with WithRetries.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	retryable := req.Method == http.MethodGet || req.Method == http.MethodPut ||
+		req.Method == http.MethodDelete
+	if req.Body != nil && req.GetBody == nil {
+		// The body cannot be read again
+		retryable = false
+	}
+	backoff := c.retryBackoff
+	for attempt := 0; ; attempt++ {
+		res, err := c.http.Do(req)
+		if !retryable || attempt == c.retries || !shouldRetry(res, err) {
+			return res, err
+		}
+		if res != nil {
+			_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+			res.Body.Close()
+		}
+
+		select {
+		case <-req.Context().Done():
+			if err == nil {
+				err = req.Context().Err()
+			}
+			return nil, err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if req.GetBody != nil {
+			if req.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// shouldRetry tells whether a request may succeed if sent again, which is
+// when the server could not be reached or was unavailable.
+func shouldRetry(res *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	switch res.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// decodeError returns the error of an ErrorResponse, or a generic one when the
+// response is not one, e.g. when returned by a proxy.
+func decodeError(res *http.Response) error {
+	data, err := io.ReadAll(io.LimitReader(res.Body, 64<<10))
+	if err != nil {
+		return err
+	}
+	var errRes server.ErrorResponse
+	if err := json.Unmarshal(data, &errRes); err == nil && errRes.Error != nil {
+		return errRes.Error
+	}
+	return &server.APIError{
+		Status:  res.StatusCode,
+		Code:    http.StatusText(res.StatusCode),
+		Message: strings.TrimSpace(string(data)),
+	}
+}
+
+func jsonBody(v any) (io.Reader, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(data), nil
+}
+
+func indexPath(index string, rest ...string) string {
+	return "/" + strings.Join(append([]string{"indices", url.PathEscape(index)}, rest...), "/")
+}
+
+func aliasPath(alias, index string) string {
+	if index == "" {
+		return "/aliases/" + url.PathEscape(alias)
+	}
+	return "/aliases/" + url.PathEscape(alias) + "/" + url.PathEscape(index)
+}
+
+// New returns a client of the server at baseURL, such as DefaultURL.
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("server URL should be http or https, got '%s'", baseURL)
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = DefaultMaxIdleConns
+	c := &Client{
+		baseURL:      u,
+		http:         &http.Client{Transport: transport},
+		transport:    transport,
+		retryBackoff: DefaultRetryBackoff,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
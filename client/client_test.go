@@ -0,0 +1,157 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sonirico/visigoth"
+	"github.com/sonirico/visigoth/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClient(t *testing.T) *Client {
+	repo := visigoth.NewIndexRepo(visigoth.NewMemoryIndexBuilder(
+		visigoth.NewTokenizationPipeline(
+			visigoth.NewKeepAlphanumericTokenizer(),
+			visigoth.NewLowerCaseTokenizer(),
+		),
+	))
+	srv := httptest.NewServer(server.NewHTTPHandler(repo))
+	t.Cleanup(srv.Close)
+	c, err := New(srv.URL, WithHTTPClient(srv.Client()))
+	require.NoError(t, err)
+	return c
+}
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient(t)
+
+	require.NoError(t, c.Health(ctx))
+	require.NoError(t, c.CreateIndex(ctx, "dedos"))
+	require.NoError(t, c.PutDocument(ctx, "dedos", server.Document{
+		ID:      "pulgar",
+		Content: "este fue a por huevos",
+	}))
+	bulk, err := c.Bulk(ctx, "dedos", strings.NewReader(
+		`{"id": "corazon", "content": "este fue a por huevos y los probo"}`+"\n"+`{}`,
+	))
+	require.NoError(t, err)
+	assert.Equal(t, 1, bulk.Indexed)
+	assert.Len(t, bulk.Errors, 1)
+
+	require.NoError(t, c.AddAlias(ctx, "dedos:latest", "dedos"))
+	aliases, err := c.Aliases(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []server.Alias{{Alias: "dedos:latest", Indices: []string{"dedos"}}}, aliases)
+
+	res, err := c.SearchPage(ctx, "dedos:latest", "huevos", SearchParams{Size: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 2, res.Total)
+	require.Len(t, res.Results, 1)
+	assert.Equal(t, "corazon", res.Results[0].Doc().ID())
+
+	require.NoError(t, c.RemoveAlias(ctx, "dedos:latest", ""))
+	require.NoError(t, c.RenameIndex(ctx, "dedos", "manos"))
+	indices, err := c.Indices(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"manos"}, indices)
+	require.NoError(t, c.DropIndex(ctx, "manos"))
+}
+
+func TestClient_Errors(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient(t)
+
+	_, err := c.SearchPage(ctx, "dedos", "huevos", SearchParams{Size: -1})
+	var apiErr *server.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.Status)
+	assert.Equal(t, server.CodeNotFound, apiErr.Code)
+	assert.Equal(t, "index with name 'dedos' does not exist", apiErr.Error())
+
+	_, err = New("localhost:7374")
+	assert.Error(t, err)
+}
+
+func TestClient_APIKey(t *testing.T) {
+	ctx := context.Background()
+	keys, err := server.NewKeyStore([]server.APIKey{{
+		Name:   "reader",
+		Key:    "r34d",
+		Grants: []server.Grant{{Role: server.RoleRead, Indices: []string{"*"}}},
+	}})
+	require.NoError(t, err)
+	repo := visigoth.NewIndexRepo(visigoth.NewMemoryIndexBuilder(
+		visigoth.NewKeepAlphanumericTokenizer(),
+	))
+	srv := httptest.NewServer(server.NewHTTPHandler(repo, server.WithKeyStore(keys)))
+	t.Cleanup(srv.Close)
+
+	c, err := New(srv.URL, WithHTTPClient(srv.Client()))
+	require.NoError(t, err)
+	_, err = c.Indices(ctx)
+	var apiErr *server.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, server.CodeUnauthorized, apiErr.Code)
+
+	c, err = New(srv.URL, WithHTTPClient(srv.Client()), WithAPIKey("r34d"))
+	require.NoError(t, err)
+	_, err = c.Indices(ctx)
+	require.NoError(t, err)
+	err = c.CreateIndex(ctx, "dedos")
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, server.CodeForbidden, apiErr.Code)
+}
+
+func TestClient_Retries(t *testing.T) {
+	ctx := context.Background()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"indices": ["dedos"]}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	c, err := New(srv.URL, WithRetries(1, time.Millisecond))
+	require.NoError(t, err)
+	_, err = c.Indices(ctx)
+	var apiErr *server.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusServiceUnavailable, apiErr.Status)
+
+	calls.Store(0)
+	c, err = New(srv.URL, WithRetries(2, time.Millisecond))
+	require.NoError(t, err)
+	indices, err := c.Indices(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dedos"}, indices)
+	assert.Equal(t, int32(3), calls.Load())
+
+	calls.Store(0)
+	err = c.PutDocument(ctx, "dedos", server.Document{ID: "pulgar"})
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), calls.Load(), "documents are not sent twice")
+}
+
+func TestClient_Timeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	t.Cleanup(srv.Close)
+
+	c, err := New(srv.URL, WithTimeout(10*time.Millisecond), WithRetries(3, time.Millisecond))
+	require.NoError(t, err)
+	err = c.Health(context.Background())
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,190 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+
+	"github.com/sonirico/vago/streams"
+	"github.com/sonirico/visigoth"
+	"github.com/sonirico/visigoth/server"
+)
+
+var _ visigoth.Repo = (*Repo)(nil)
+
+// Repo is a visigoth.Repo kept by a server, so that code written against
+// visigoth.Repo runs unchanged whether the repo is in-process or not. Calls
+// are sent with the client, and so are subject to its timeout and retries.
+//
+// Searches are limited to the built-in engines and return every result at
+// once. Query analyzers cannot be sent to the server, so searches with
+// visigoth.WithQueryAnalyzer fail.
+//
+// Methods which cannot return an error report failed calls to the error
+// handler, other than the server answering that an index or alias does not
+// exist or already exists, and then return false or nothing.
+type Repo struct {
+	client  *Client
+	onError func(err error)
+}
+
+// RepoOption configures a Repo.
+type RepoOption func(*Repo)
+
+// WithErrorHandler sets the function failed calls not returning an error
+// are reported to. They are ignored by default.
+func WithErrorHandler(fn func(err error)) RepoOption {
+	return func(r *Repo) {
+		r.onError = fn
+	}
+}
+
+func (r *Repo) List() []string {
+	indices, err := r.client.Indices(context.Background())
+	if err != nil {
+		r.onError(err)
+		return nil
+	}
+	return indices
+}
+
+func (r *Repo) ListAliases() visigoth.AliasesResult {
+	aliases, err := r.client.Aliases(context.Background())
+	if err != nil {
+		r.onError(err)
+		return visigoth.AliasesResult{}
+	}
+	res := visigoth.AliasesResult{Aliases: make([]visigoth.AliasesResultRow, len(aliases))}
+	for i, alias := range aliases {
+		res.Aliases[i] = visigoth.AliasesResultRow{Alias: alias.Alias, Indices: alias.Indices}
+	}
+	return res
+}
+
+func (r *Repo) Has(name string) bool {
+	return slices.Contains(r.List(), name)
+}
+
+func (r *Repo) HasAlias(name string) bool {
+	for _, alias := range r.ListAliases().Aliases {
+		if alias.Alias == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Repo) Alias(alias string, in string) bool {
+	return r.ok(r.client.AddAlias(context.Background(), alias, in))
+}
+
+func (r *Repo) UnAlias(alias, index string) bool {
+	return r.ok(r.client.RemoveAlias(context.Background(), alias, index))
+}
+
+func (r *Repo) Create(in string) bool {
+	return r.ok(r.client.CreateIndex(context.Background(), in))
+}
+
+func (r *Repo) Put(in string, req visigoth.DocRequest) {
+	doc := server.Document{
+		ID:        req.ID(),
+		Content:   req.Raw(),
+		Statement: req.Statement(),
+		Language:  string(req.Language),
+	}
+	if req.Mime() == visigoth.MimeJSON {
+		doc.Mime = "json"
+	}
+	if doc.Statement == doc.Content {
+		doc.Statement = ""
+	}
+	if err := r.client.PutDocument(context.Background(), in, doc); err != nil {
+		r.onError(err)
+	}
+}
+
+func (r *Repo) Search(
+	index string,
+	terms string,
+	engine visigoth.Engine,
+) (streams.ReadStream[visigoth.SearchResult], error) {
+	return r.SearchWith(index, terms, engine)
+}
+
+func (r *Repo) SearchWith(
+	index string,
+	terms string,
+	engine visigoth.Engine,
+	opts ...visigoth.SearchOption,
+) (streams.ReadStream[visigoth.SearchResult], error) {
+	name, ok := visigoth.EngineName(engine)
+	if !ok {
+		return nil, errors.New("only built-in engines can search a remote repo")
+	}
+	o := visigoth.NewSearchOptions(opts...)
+	if o.Analyzer != nil {
+		return nil, errors.New("query analyzers cannot be sent to a remote repo")
+	}
+	res, err := r.client.SearchPage(context.Background(), index, terms, SearchParams{
+		Engine:    name,
+		Size:      -1,
+		Languages: o.Languages,
+	})
+	if err != nil {
+		return nil, toRepoError(err, "index", index)
+	}
+	return streams.MemReader(res.Results, nil), nil
+}
+
+func (r *Repo) Suggest(index string, terms string) (visigoth.Suggestion, error) {
+	suggestion, err := r.client.Suggest(context.Background(), index, terms)
+	return suggestion, toRepoError(err, "index", index)
+}
+
+func (r *Repo) Analyze(name string, text string) (visigoth.Analysis, error) {
+	analysis, err := r.client.Analyze(context.Background(), name, text)
+	return analysis, toRepoError(err, "index or analyzer", name)
+}
+
+func (r *Repo) Rename(old string, new string) bool {
+	return r.ok(r.client.RenameIndex(context.Background(), old, new))
+}
+
+func (r *Repo) Drop(in string) bool {
+	return r.ok(r.client.DropIndex(context.Background(), in))
+}
+
+// ok tells whether the call succeeded, reporting unexpected errors.
+func (r *Repo) ok(err error) bool {
+	if err == nil {
+		return true
+	}
+	var apiErr *server.APIError
+	if !errors.As(err, &apiErr) ||
+		(apiErr.Status != http.StatusNotFound && apiErr.Status != http.StatusConflict) {
+		r.onError(err)
+	}
+	return false
+}
+
+// toRepoError returns errors of the server saying that the index, or
+// whatever kind of thing, does not exist as the visigoth.NotFoundError
+// returned by visigoth.IndexRepo.
+func toRepoError(err error, kind, name string) error {
+	var apiErr *server.APIError
+	if errors.As(err, &apiErr) && apiErr.Code == server.CodeNotFound {
+		return visigoth.NotFoundError{Kind: kind, Name: name}
+	}
+	return err
+}
+
+// NewRepo returns the repo kept by the server the client calls.
+func NewRepo(c *Client, opts ...RepoOption) *Repo {
+	r := &Repo{client: c, onError: func(error) {}}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
@@ -0,0 +1,94 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sonirico/vago/slices"
+	"github.com/sonirico/vago/streams"
+	"github.com/sonirico/visigoth"
+	"github.com/sonirico/visigoth/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepo(t *testing.T) {
+	c := newTestClient(t)
+	var errs []error
+	var repo visigoth.Repo = NewRepo(c, WithErrorHandler(func(err error) {
+		errs = append(errs, err)
+	}))
+
+	assert.True(t, repo.Create("dedos"))
+	assert.False(t, repo.Create("dedos"))
+	assert.True(t, repo.Has("dedos"))
+	repo.Put("dedos", visigoth.NewDocRequest("pulgar", "este fue a por huevos"))
+	repo.Put("dedos", visigoth.NewDocRequestWith("corazon",
+		`{"dedo": "corazon"}`, "este fue a por huevos y los probo"))
+	repo.Put("dedos", visigoth.NewDocRequest("indice", "este los puso a cocer").
+		WithLanguage("es"))
+
+	assert.True(t, repo.Alias("manos", "dedos"))
+	assert.False(t, repo.Alias("manos", "pies"))
+	assert.True(t, repo.HasAlias("manos"))
+	assert.Equal(t, visigoth.AliasesResult{Aliases: []visigoth.AliasesResultRow{
+		{Alias: "manos", Indices: []string{"dedos"}},
+	}}, repo.ListAliases())
+
+	stream, err := repo.Search("manos", "huevos", visigoth.HitsSearch)
+	require.NoError(t, err)
+	results, err := streams.Consume(stream)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, "corazon", results[0].Doc().ID())
+	assert.Equal(t, `{"dedo": "corazon"}`, results[0].Doc().Raw())
+
+	stream, err = repo.SearchWith("dedos", "este", visigoth.HitsSearch,
+		visigoth.WithLanguages("es"))
+	require.NoError(t, err)
+	results, err = streams.Consume(stream)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "indice", results[0].Doc().ID())
+
+	_, err = repo.Search("pies", "huevos", visigoth.HitsSearch)
+	assert.Equal(t, visigoth.NotFoundError{Kind: "index", Name: "pies"}, err)
+	custom := func([]string, visigoth.Indexer) slices.Slice[visigoth.SearchResult] { return nil }
+	_, err = repo.Search("dedos", "huevos", custom)
+	assert.Error(t, err)
+
+	suggestion, err := repo.Suggest("dedos", "huevso")
+	require.NoError(t, err)
+	assert.Equal(t, "huevos", suggestion.Query())
+	analysis, err := repo.Analyze("dedos", "Este fue")
+	require.NoError(t, err)
+	last := analysis.Stages[len(analysis.Stages)-1]
+	assert.Equal(t, []string{"este", "fue"}, visigoth.Terms(last.Tokens))
+
+	assert.True(t, repo.UnAlias("manos", "dedos"))
+	assert.True(t, repo.Rename("dedos", "pies"))
+	assert.Equal(t, []string{"pies"}, repo.List())
+	assert.True(t, repo.Drop("pies"))
+	assert.False(t, repo.Drop("pies"))
+	assert.Empty(t, errs)
+}
+
+func TestRepo_ErrorHandler(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(srv.Close)
+	c, err := New(srv.URL)
+	require.NoError(t, err)
+	var errs []error
+	repo := NewRepo(c, WithErrorHandler(func(err error) { errs = append(errs, err) }))
+
+	assert.False(t, repo.Create("dedos"))
+	assert.Nil(t, repo.List())
+	repo.Put("dedos", visigoth.NewDocRequest("pulgar", "este fue a por huevos"))
+	require.Len(t, errs, 3)
+	var apiErr *server.APIError
+	require.ErrorAs(t, errs[0], &apiErr)
+	assert.Equal(t, http.StatusInternalServerError, apiErr.Status)
+}
@@ -0,0 +1,612 @@
+// Command cmd_client manages and queries a visigoth server, either running a
+// single command or, with no command, interactively.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sonirico/visigoth"
+	"github.com/sonirico/visigoth/client"
+	"github.com/sonirico/visigoth/server"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+
+	historyFile  = ".visigoth_history"
+	maxCellRunes = 60
+
+	// defaultBulkBytes bounds the bulk requests documents are indexed with,
+	// well below the body limit of servers, server.DefaultMaxBodyBytes.
+	defaultBulkBytes = 8 << 20
+)
+
+const searchUsage = "search [-engine hits] [-from 0] [-size 10] [-lang en] <index> <terms ...>"
+
+var errUsage = errors.New("usage")
+
+type command struct {
+	usage   string
+	summary string
+	run     func(ctx context.Context, c *cli, args []string) error
+}
+
+var commands = map[string]command{
+	"indices": {"indices", "list indices", runIndices},
+	"create":  {"create <index>", "create an empty index", runCreate},
+	"drop":    {"drop <index>", "drop an index and its aliases", runDrop},
+	"rename":  {"rename <index> <name>", "rename an index", runRename},
+	"index": {
+		"index <index> [file ...]",
+		"index JSON lines documents from files, or from stdin",
+		runIndex,
+	},
+	"search": {
+		searchUsage,
+		"search an index or alias",
+		runSearch,
+	},
+	"aliases": {"aliases", "list aliases", runAliases},
+	"alias":   {"alias <alias> <index>", "add an index to an alias", runAlias},
+	"unalias": {
+		"unalias <alias> [index]",
+		"remove an index from an alias, or the whole alias",
+		runUnAlias,
+	},
+}
+
+type cli struct {
+	client  *client.Client
+	output  string
+	timeout time.Duration
+	stdin   io.Reader
+	out     io.Writer
+	errOut  io.Writer
+	// interactive is set in the REPL, where stdin holds commands.
+	interactive bool
+	// bulkBytes bounds bulk requests, defaultBulkBytes if zero.
+	bulkBytes int
+}
+
+func (c *cli) exec(ctx context.Context, args []string) error {
+	cmd, ok := commands[args[0]]
+	if !ok {
+		return fmt.Errorf("unknown command '%s', try 'help'", args[0])
+	}
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+	err := cmd.run(ctx, c, args[1:])
+	if errors.Is(err, errUsage) {
+		return fmt.Errorf("usage: %s", cmd.usage)
+	}
+	return err
+}
+
+func (c *cli) flagSet(name, usage string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(c.errOut)
+	fs.Usage = func() {
+		fmt.Fprintf(c.errOut, "usage: %s\n", usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+func (c *cli) printJSON(v any) error {
+	enc := json.NewEncoder(c.out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func (c *cli) printTable(header []string, rows [][]string) error {
+	w := tabwriter.NewWriter(c.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	return w.Flush()
+}
+
+func (c *cli) printAck(message string) error {
+	if c.output == outputJSON {
+		return c.printJSON(server.AcknowledgedResponse{Acknowledged: true})
+	}
+	_, err := fmt.Fprintln(c.out, message)
+	return err
+}
+
+func runIndices(ctx context.Context, c *cli, args []string) error {
+	if len(args) != 0 {
+		return errUsage
+	}
+	indices, err := c.client.Indices(ctx)
+	if err != nil {
+		return err
+	}
+	if c.output == outputJSON {
+		return c.printJSON(server.IndicesResponse{Indices: indices})
+	}
+	rows := make([][]string, len(indices))
+	for i, index := range indices {
+		rows[i] = []string{index}
+	}
+	return c.printTable([]string{"INDEX"}, rows)
+}
+
+func runCreate(ctx context.Context, c *cli, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	if err := c.client.CreateIndex(ctx, args[0]); err != nil {
+		return err
+	}
+	return c.printAck(fmt.Sprintf("created index '%s'", args[0]))
+}
+
+func runDrop(ctx context.Context, c *cli, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	if err := c.client.DropIndex(ctx, args[0]); err != nil {
+		return err
+	}
+	return c.printAck(fmt.Sprintf("dropped index '%s'", args[0]))
+}
+
+func runRename(ctx context.Context, c *cli, args []string) error {
+	if len(args) != 2 {
+		return errUsage
+	}
+	if err := c.client.RenameIndex(ctx, args[0], args[1]); err != nil {
+		return err
+	}
+	return c.printAck(fmt.Sprintf("renamed index '%s' to '%s'", args[0], args[1]))
+}
+
+func runIndex(ctx context.Context, c *cli, args []string) error {
+	if len(args) < 1 {
+		return errUsage
+	}
+	index, paths := args[0], args[1:]
+	if len(paths) == 0 {
+		if c.interactive {
+			return errors.New("no files to index, stdin holds commands")
+		}
+		paths = []string{"-"}
+	}
+
+	var total server.BulkResponse
+	for _, path := range paths {
+		res, err := c.indexFile(ctx, index, path)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		total.Indexed += res.Indexed
+		for _, e := range res.Errors {
+			total.Errors = append(total.Errors, e)
+			fmt.Fprintf(c.errOut, "%s:%d: %s\n", path, e.Line, e.Message)
+		}
+	}
+	if c.output == outputJSON {
+		return c.printJSON(total)
+	}
+	_, err := fmt.Fprintf(c.out, "indexed %d documents into '%s', %d failed\n",
+		total.Indexed, index, len(total.Errors))
+	return err
+}
+
+func (c *cli) indexFile(ctx context.Context, index, path string) (server.BulkResponse, error) {
+	if path == "-" {
+		return c.bulk(ctx, index, c.stdin)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return server.BulkResponse{}, err
+	}
+	defer f.Close()
+	return c.bulk(ctx, index, f)
+}
+
+// bulk indexes the JSON lines documents read from r in as many bulk requests
+// as needed to keep each within bulkBytes, reporting the errors by their line
+// in r.
+func (c *cli) bulk(ctx context.Context, index string, r io.Reader) (server.BulkResponse, error) {
+	limit := c.bulkBytes
+	if limit <= 0 {
+		limit = defaultBulkBytes
+	}
+	var (
+		total server.BulkResponse
+		batch bytes.Buffer
+		// sent and batched are the lines in requests sent and in batch.
+		sent, batched int
+	)
+	flush := func() error {
+		if batched == 0 {
+			return nil
+		}
+		res, err := c.client.Bulk(ctx, index, &batch)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", sent+1, err)
+		}
+		total.Indexed += res.Indexed
+		for _, e := range res.Errors {
+			e.Line += sent
+			total.Errors = append(total.Errors, e)
+		}
+		sent, batched = sent+batched, 0
+		batch.Reset()
+		return nil
+	}
+
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadBytes('\n')
+		if len(line) > 0 {
+			if batch.Len()+len(line) > limit {
+				if err := flush(); err != nil {
+					return total, err
+				}
+			}
+			batch.Write(line)
+			if line[len(line)-1] != '\n' {
+				batch.WriteByte('\n')
+			}
+			batched++
+		}
+		if errors.Is(err, io.EOF) {
+			return total, flush()
+		}
+		if err != nil {
+			return total, err
+		}
+	}
+}
+
+func runSearch(ctx context.Context, c *cli, args []string) error {
+	fs := c.flagSet("search", searchUsage)
+	var (
+		params client.SearchParams
+		langs  string
+	)
+	fs.StringVar(&params.Engine, "engine", server.DefaultEngine,
+		"search engine, one of "+strings.Join(visigoth.EngineNames(), ", "))
+	fs.IntVar(&params.From, "from", 0, "number of results to skip")
+	fs.IntVar(&params.Size, "size", 10, "maximum number of results, all if negative")
+	fs.StringVar(&langs, "lang", "", "comma separated languages to search in")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 2 {
+		return errUsage
+	}
+	if langs != "" {
+		for _, lang := range strings.Split(langs, ",") {
+			params.Languages = append(params.Languages, visigoth.Language(lang))
+		}
+	}
+
+	index, terms := fs.Arg(0), strings.Join(fs.Args()[1:], " ")
+	res, err := c.client.SearchPage(ctx, index, terms, params)
+	if err != nil {
+		return err
+	}
+	if c.output == outputJSON {
+		return c.printJSON(res)
+	}
+	rows := make([][]string, len(res.Results))
+	for i, result := range res.Results {
+		rows[i] = []string{
+			strconv.Itoa(res.From + i + 1),
+			result.Doc().ID(),
+			strconv.Itoa(result.Hits),
+			truncate(result.Doc().Raw()),
+		}
+	}
+	if err := c.printTable([]string{"#", "ID", "HITS", "CONTENT"}, rows); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(c.out, "%d of %d results\n", len(res.Results), res.Total)
+	return err
+}
+
+func runAliases(ctx context.Context, c *cli, args []string) error {
+	if len(args) != 0 {
+		return errUsage
+	}
+	aliases, err := c.client.Aliases(ctx)
+	if err != nil {
+		return err
+	}
+	if c.output == outputJSON {
+		return c.printJSON(server.AliasesResponse{Aliases: aliases})
+	}
+	rows := make([][]string, len(aliases))
+	for i, alias := range aliases {
+		rows[i] = []string{alias.Alias, strings.Join(alias.Indices, ", ")}
+	}
+	return c.printTable([]string{"ALIAS", "INDICES"}, rows)
+}
+
+func runAlias(ctx context.Context, c *cli, args []string) error {
+	if len(args) != 2 {
+		return errUsage
+	}
+	if err := c.client.AddAlias(ctx, args[0], args[1]); err != nil {
+		return err
+	}
+	return c.printAck(fmt.Sprintf("aliased index '%s' as '%s'", args[1], args[0]))
+}
+
+func runUnAlias(ctx context.Context, c *cli, args []string) error {
+	if len(args) != 1 && len(args) != 2 {
+		return errUsage
+	}
+	alias, index := args[0], ""
+	if len(args) == 2 {
+		index = args[1]
+	}
+	if err := c.client.RemoveAlias(ctx, alias, index); err != nil {
+		return err
+	}
+	if index == "" {
+		return c.printAck(fmt.Sprintf("removed alias '%s'", alias))
+	}
+	return c.printAck(fmt.Sprintf("removed index '%s' from alias '%s'", index, alias))
+}
+
+// truncate shortens s to fit a table cell, in a single line.
+func truncate(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if utf8.RuneCountInString(s) <= maxCellRunes {
+		return s
+	}
+	return string([]rune(s)[:maxCellRunes-1]) + "…"
+}
+
+// splitArgs splits a REPL line into arguments, separated by spaces unless
+// within single or double quotes.
+func splitArgs(line string) ([]string, error) {
+	var (
+		args    []string
+		current strings.Builder
+		quote   rune
+		inArg   bool
+	)
+	for _, r := range line {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			current.WriteRune(r)
+		case r == '"' || r == '\'':
+			quote, inArg = r, true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote %c", quote)
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
+
+// history holds the lines run in the REPL, appending them to a file if any.
+type history struct {
+	lines []string
+	file  *os.File
+}
+
+func openHistory(path string) (*history, error) {
+	h := &history{}
+	if path == "" {
+		return h, nil
+	}
+	if data, err := os.ReadFile(path); err == nil {
+		h.lines = strings.FieldsFunc(string(data), func(r rune) bool { return r == '\n' })
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	h.file = f
+	return h, nil
+}
+
+func (h *history) add(line string) {
+	h.lines = append(h.lines, line)
+	if h.file != nil {
+		_, _ = fmt.Fprintln(h.file, line)
+	}
+}
+
+// expand resolves "!!" to the last line and "!n" to the nth line.
+func (h *history) expand(line string) (string, error) {
+	if !strings.HasPrefix(line, "!") {
+		return line, nil
+	}
+	if line == "!!" {
+		if len(h.lines) == 0 {
+			return "", errors.New("history is empty")
+		}
+		return h.lines[len(h.lines)-1], nil
+	}
+	n, err := strconv.Atoi(line[1:])
+	if err != nil || n < 1 || n > len(h.lines) {
+		return "", fmt.Errorf("no line %s in history", line[1:])
+	}
+	return h.lines[n-1], nil
+}
+
+func (h *history) close() error {
+	if h.file == nil {
+		return nil
+	}
+	return h.file.Close()
+}
+
+func (c *cli) repl(ctx context.Context, hist *history) error {
+	fmt.Fprintln(c.out, "visigoth client, type 'help' for commands and 'exit' to quit")
+	scanner := bufio.NewScanner(c.stdin)
+	for {
+		fmt.Fprint(c.out, "visigoth> ")
+		if !scanner.Scan() {
+			fmt.Fprintln(c.out)
+			return scanner.Err()
+		}
+		line, err := hist.expand(strings.TrimSpace(scanner.Text()))
+		if err != nil {
+			fmt.Fprintln(c.errOut, "error:", err)
+			continue
+		}
+		if line == "" {
+			continue
+		}
+		args, err := splitArgs(line)
+		if err != nil {
+			fmt.Fprintln(c.errOut, "error:", err)
+			continue
+		}
+		if args[0] != "history" {
+			hist.add(line)
+		}
+
+		switch args[0] {
+		case "exit", "quit":
+			return nil
+		case "help":
+			c.help()
+		case "history":
+			for i, l := range hist.lines {
+				fmt.Fprintf(c.out, "%5d  %s\n", i+1, l)
+			}
+		default:
+			// Interrupts cancel the running command rather than the session.
+			cmdCtx, stop := signal.NotifyContext(ctx, os.Interrupt)
+			err := c.exec(cmdCtx, args)
+			stop()
+			if err != nil {
+				fmt.Fprintln(c.errOut, "error:", err)
+			}
+		}
+	}
+}
+
+func (c *cli) help() {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	w := tabwriter.NewWriter(c.out, 0, 4, 2, ' ', 0)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %s\t%s\n", commands[name].usage, commands[name].summary)
+	}
+	if c.interactive {
+		fmt.Fprintf(w, "  history\tlist previous commands, rerun them with !n or !!\n")
+		fmt.Fprintf(w, "  exit\tquit\n")
+	}
+	_ = w.Flush()
+}
+
+func defaultHistoryPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, historyFile)
+}
+
+func main() {
+	serverURL := flag.String("server", client.DefaultURL, "URL of the visigoth server")
+	output := flag.String("output", outputTable, "output format, table or json")
+	timeout := flag.Duration("timeout", 30*time.Second, "timeout of every command, none if 0")
+	historyPath := flag.String("history", defaultHistoryPath(),
+		"file keeping the history of the interactive mode, none if empty")
+	apiKey := flag.String("api-key", os.Getenv("VISIGOTH_API_KEY"),
+		"API key to authenticate with, $VISIGOTH_API_KEY by default")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(),
+			"usage: %s [flags] [command [arguments]]\n\ncommands:\n", filepath.Base(os.Args[0]))
+		(&cli{out: flag.CommandLine.Output()}).help()
+		fmt.Fprintf(
+			flag.CommandLine.Output(),
+			"\nwith no command, commands are read interactively\n\nflags:\n",
+		)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *output != outputTable && *output != outputJSON {
+		fmt.Fprintf(os.Stderr, "unknown output format '%s'\n", *output)
+		os.Exit(2)
+	}
+	cl, err := client.New(*serverURL, client.WithAPIKey(*apiKey))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(2)
+	}
+	c := &cli{
+		client:  cl,
+		output:  *output,
+		timeout: *timeout,
+		stdin:   os.Stdin,
+		out:     os.Stdout,
+		errOut:  os.Stderr,
+	}
+
+	if flag.NArg() > 0 {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		err := c.exec(ctx, flag.Args())
+		stop()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "error:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	hist, err := openHistory(*historyPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+	defer hist.close()
+	c.interactive = true
+	if err := c.repl(context.Background(), hist); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/sonirico/visigoth"
+	"github.com/sonirico/visigoth/client"
+	"github.com/sonirico/visigoth/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testCLI runs commands against a test server, keeping what they print.
+type testCLI struct {
+	*cli
+	out, errOut *bytes.Buffer
+	// bulks counts the bulk requests received by the server.
+	bulks *atomic.Int32
+}
+
+func newTestCLI(t *testing.T, output string) *testCLI {
+	repo := visigoth.NewIndexRepo(visigoth.NewMemoryIndexBuilder(
+		visigoth.NewTokenizationPipeline(
+			visigoth.NewKeepAlphanumericTokenizer(),
+			visigoth.NewLowerCaseTokenizer(),
+		),
+	))
+	handler := server.NewHTTPHandler(repo)
+	bulks := &atomic.Int32{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/_bulk") {
+			bulks.Add(1)
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	cl, err := client.New(srv.URL, client.WithHTTPClient(srv.Client()))
+	require.NoError(t, err)
+	tc := &testCLI{out: &bytes.Buffer{}, errOut: &bytes.Buffer{}, bulks: bulks}
+	tc.cli = &cli{
+		client: cl,
+		output: output,
+		stdin:  strings.NewReader(""),
+		out:    tc.out,
+		errOut: tc.errOut,
+	}
+	return tc
+}
+
+// run runs the command, returning what it printed.
+func (c *testCLI) run(t *testing.T, args ...string) string {
+	t.Helper()
+	c.out.Reset()
+	require.NoError(t, c.exec(context.Background(), args))
+	return c.out.String()
+}
+
+func writeTestFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestCLI_Table(t *testing.T) {
+	c := newTestCLI(t, outputTable)
+
+	assert.Equal(t, "created index 'dedos'\n", c.run(t, "create", "dedos"))
+	path := writeTestFile(t, "dedos.jsonl",
+		`{"id": "pulgar", "content": "este fue a por huevos"}`+"\n"+
+			`{"id": "indice", "content": "este los puso a cocer"}`+"\n")
+	assert.Equal(t, "indexed 2 documents into 'dedos', 0 failed\n",
+		c.run(t, "index", "dedos", path))
+	c.stdin = strings.NewReader(`{"id": "corazon", "content": "este fue a por huevos y los probo"}` +
+		"\n\n{}\n")
+	assert.Equal(t, "indexed 1 documents into 'dedos', 1 failed\n", c.run(t, "index", "dedos"))
+	assert.Equal(t, "-:3: document with no 'id'\n", c.errOut.String())
+
+	assert.Equal(t, "INDEX\ndedos\n", c.run(t, "indices"))
+	assert.Equal(t,
+		"#  ID       HITS  CONTENT\n"+
+			"1  corazon  1     este fue a por huevos y los probo\n"+
+			"2  pulgar   1     este fue a por huevos\n"+
+			"2 of 2 results\n",
+		c.run(t, "search", "dedos", "huevos"))
+	assert.Equal(t,
+		"#  ID      HITS  CONTENT\n"+
+			"2  pulgar  3     este fue a por huevos\n"+
+			"1 of 2 results\n",
+		c.run(t, "search", "-from", "1", "-size", "1", "dedos", "a", "por", "huevos"))
+
+	assert.Equal(t, "aliased index 'dedos' as 'manos'\n", c.run(t, "alias", "manos", "dedos"))
+	assert.Equal(t, "ALIAS  INDICES\nmanos  dedos\n", c.run(t, "aliases"))
+	assert.Equal(t, "removed index 'dedos' from alias 'manos'\n",
+		c.run(t, "unalias", "manos", "dedos"))
+	assert.Equal(t, "removed alias 'manos'\n", c.run(t, "unalias", "manos"))
+	assert.Equal(t, "renamed index 'dedos' to 'manos'\n", c.run(t, "rename", "dedos", "manos"))
+	assert.Equal(t, "dropped index 'manos'\n", c.run(t, "drop", "manos"))
+
+	err := c.exec(context.Background(), []string{"search", "manos"})
+	assert.EqualError(t, err, "usage: "+searchUsage)
+	err = c.exec(context.Background(), []string{"search", "manos", "huevos"})
+	assert.ErrorContains(t, err, "does not exist")
+	err = c.exec(context.Background(), []string{"bogus"})
+	assert.EqualError(t, err, "unknown command 'bogus', try 'help'")
+}
+
+func TestCLI_JSON(t *testing.T) {
+	c := newTestCLI(t, outputJSON)
+
+	var ack server.AcknowledgedResponse
+	require.NoError(t, json.Unmarshal([]byte(c.run(t, "create", "dedos")), &ack))
+	assert.True(t, ack.Acknowledged)
+
+	c.stdin = strings.NewReader(`{"id": "pulgar", "content": "este fue a por huevos"}`)
+	var bulk server.BulkResponse
+	require.NoError(t, json.Unmarshal([]byte(c.run(t, "index", "dedos", "-")), &bulk))
+	assert.Equal(t, server.BulkResponse{Indexed: 1}, bulk)
+
+	var search struct {
+		Total   int `json:"total"`
+		From    int `json:"from"`
+		Results []struct {
+			Doc struct {
+				ID string `json:"id"`
+			} `json:"doc"`
+		} `json:"results"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(c.run(t, "search", "dedos", "huevos")), &search))
+	assert.Equal(t, 1, search.Total)
+	require.Len(t, search.Results, 1)
+	assert.Equal(t, "pulgar", search.Results[0].Doc.ID)
+
+	require.NoError(t, json.Unmarshal([]byte(c.run(t, "alias", "manos", "dedos")), &ack))
+	var aliases server.AliasesResponse
+	require.NoError(t, json.Unmarshal([]byte(c.run(t, "aliases")), &aliases))
+	assert.Equal(t, []server.Alias{{Alias: "manos", Indices: []string{"dedos"}}}, aliases.Aliases)
+	var indices server.IndicesResponse
+	require.NoError(t, json.Unmarshal([]byte(c.run(t, "indices")), &indices))
+	assert.Equal(t, []string{"dedos"}, indices.Indices)
+}
+
+func TestCLI_Index_SplitsBulks(t *testing.T) {
+	c := newTestCLI(t, outputTable)
+	c.bulkBytes = 256
+
+	var lines strings.Builder
+	for i := range 20 {
+		if i == 13 {
+			lines.WriteString("{}\n")
+			continue
+		}
+		fmt.Fprintf(&lines, `{"id": "doc-%d", "content": "este fue a por huevos"}`+"\n", i)
+	}
+	path := writeTestFile(t, "dedos.jsonl", lines.String())
+	assert.Equal(t, "indexed 19 documents into 'dedos', 1 failed\n",
+		c.run(t, "index", "dedos", path))
+	assert.Equal(t, path+":14: document with no 'id'\n", c.errOut.String())
+	assert.Greater(t, c.bulks.Load(), int32(1), "documents should be sent in several requests")
+
+	out := c.run(t, "search", "-size", "0", "dedos", "huevos")
+	assert.True(t, strings.HasSuffix(out, "0 of 19 results\n"), out)
+}
+
+func TestSplitArgs(t *testing.T) {
+	args, err := splitArgs(`search -size 5  dedos "a por  huevos" 'el 92'`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"search", "-size", "5", "dedos", "a por  huevos", "el 92"}, args)
+
+	args, err = splitArgs(`create ""`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"create", ""}, args)
+
+	_, err = splitArgs(`search dedos "huevos`)
+	assert.Error(t, err)
+}
+
+func TestHistory_Expand(t *testing.T) {
+	h := &history{}
+	_, err := h.expand("!!")
+	assert.Error(t, err)
+
+	h.add("indices")
+	h.add("search dedos huevos")
+
+	line, err := h.expand("!!")
+	require.NoError(t, err)
+	assert.Equal(t, "search dedos huevos", line)
+	line, err = h.expand("!1")
+	require.NoError(t, err)
+	assert.Equal(t, "indices", line)
+	_, err = h.expand("!3")
+	assert.Error(t, err)
+	line, err = h.expand("aliases")
+	require.NoError(t, err)
+	assert.Equal(t, "aliases", line)
+}
@@ -0,0 +1,216 @@
+// Command server serves an in-memory index repository over HTTP/JSON, and
+// optionally over gRPC, RESP and an Elasticsearch-compatible HTTP API.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/sonirico/visigoth"
+	"github.com/sonirico/visigoth/pb"
+	"github.com/sonirico/visigoth/server"
+	"google.golang.org/grpc"
+)
+
+var version = "dev"
+
+type config struct {
+	addr            string
+	grpcAddr        string
+	respAddr        string
+	esAddr          string
+	analyzer        string
+	searchAnalyzer  string
+	analyzersPath   string
+	keysPath        string
+	shutdownTimeout time.Duration
+	logLevel        slog.Level
+}
+
+func parseFlags() config {
+	var c config
+	flag.StringVar(&c.addr, "addr", ":7374", "address to listen on")
+	flag.StringVar(&c.grpcAddr, "grpc-addr", "", "address to serve gRPC on, if any")
+	flag.StringVar(&c.respAddr, "resp-addr", "",
+		"address to serve RESP, the Redis protocol, on, if any")
+	flag.StringVar(&c.esAddr, "es-addr", "",
+		"address to serve the Elasticsearch-compatible API on, if any")
+	flag.StringVar(&c.analyzer, "analyzer", visigoth.StandardAnalyzer,
+		"analyzer of new indices")
+	flag.StringVar(&c.searchAnalyzer, "search-analyzer", "",
+		"analyzer of queries, the index analyzer if empty")
+	flag.StringVar(&c.analyzersPath, "analyzers", "",
+		"JSON or YAML file with custom analyzer definitions")
+	flag.StringVar(&c.keysPath, "keys", "",
+		"JSON or YAML file with the API keys to require, if any")
+	flag.DurationVar(&c.shutdownTimeout, "shutdown-timeout", server.DefaultShutdownTimeout,
+		"time given to in-flight requests to finish on shutdown")
+	flag.TextVar(&c.logLevel, "log-level", slog.LevelInfo, "minimum level of logs")
+	showVersion := flag.Bool("version", false, "print the version and exit")
+	flag.Parse()
+
+	if *showVersion {
+		fmt.Println(version)
+		os.Exit(0)
+	}
+	return c
+}
+
+func newRepo(c config) (visigoth.Repo, error) {
+	registry := visigoth.NewAnalyzerRegistry()
+	if c.analyzersPath != "" {
+		analyzers, err := visigoth.LoadAnalyzersConfig(c.analyzersPath)
+		if err != nil {
+			return nil, fmt.Errorf("loading analyzers: %w", err)
+		}
+		if err := registry.Load(analyzers); err != nil {
+			return nil, fmt.Errorf("loading analyzers: %w", err)
+		}
+	}
+	builder, err := visigoth.NewAnalyzersMemoryIndexBuilder(registry, c.analyzer, c.searchAnalyzer)
+	if err != nil {
+		return nil, err
+	}
+	return visigoth.NewIndexRepo(builder, visigoth.WithAnalyzerRegistry(registry)), nil
+}
+
+func run(c config, logger *slog.Logger) error {
+	repo, err := newRepo(c)
+	if err != nil {
+		return err
+	}
+
+	var (
+		httpOpts = []server.HTTPHandlerOption{server.WithLogger(logger)}
+		grpcOpts []server.GRPCServerOption
+		respOpts []server.RESPServerOption
+	)
+	if c.keysPath != "" {
+		keys, err := server.LoadKeyStore(c.keysPath)
+		if err != nil {
+			return fmt.Errorf("loading keys: %w", err)
+		}
+		httpOpts = append(httpOpts, server.WithKeyStore(keys))
+		grpcOpts = append(grpcOpts, server.WithGRPCKeyStore(keys))
+		respOpts = append(respOpts, server.WithRESPKeyStore(keys))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Every server runs until ctx is done, or until another one fails.
+	var servers []func(ctx context.Context) error
+
+	ln, err := net.Listen("tcp", c.addr)
+	if err != nil {
+		return err
+	}
+	// listeners are closed if listening on a later address fails.
+	listeners := []net.Listener{ln}
+	closeListeners := func() {
+		for _, l := range listeners {
+			l.Close()
+		}
+	}
+	srv := &http.Server{
+		Handler:           server.NewHTTPHandler(repo, httpOpts...),
+		ReadHeaderTimeout: 10 * time.Second,
+		ErrorLog:          slog.NewLogLogger(logger.Handler(), slog.LevelError),
+	}
+	servers = append(servers, func(ctx context.Context) error {
+		return server.Serve(ctx, srv, ln, c.shutdownTimeout)
+	})
+	logger.Info("listening", "addr", c.addr, "version", version)
+
+	if c.grpcAddr != "" {
+		grpcLn, err := net.Listen("tcp", c.grpcAddr)
+		if err != nil {
+			closeListeners()
+			return err
+		}
+		listeners = append(listeners, grpcLn)
+		grpcSrv := grpc.NewServer()
+		pb.RegisterVisigothServer(grpcSrv, server.NewGRPCServer(repo, grpcOpts...))
+		servers = append(servers, func(ctx context.Context) error {
+			return server.ServeGRPC(ctx, grpcSrv, grpcLn, c.shutdownTimeout)
+		})
+		logger.Info("listening for gRPC", "addr", c.grpcAddr)
+	}
+
+	if c.respAddr != "" {
+		respLn, err := net.Listen("tcp", c.respAddr)
+		if err != nil {
+			closeListeners()
+			return err
+		}
+		listeners = append(listeners, respLn)
+		respSrv := server.NewRESPServer(repo, logger, respOpts...)
+		servers = append(servers, func(ctx context.Context) error {
+			return server.ServeRESP(ctx, respSrv, respLn, c.shutdownTimeout)
+		})
+		logger.Info("listening for RESP", "addr", c.respAddr)
+	}
+
+	if c.esAddr != "" {
+		esLn, err := net.Listen("tcp", c.esAddr)
+		if err != nil {
+			closeListeners()
+			return err
+		}
+		listeners = append(listeners, esLn)
+		esSrv := &http.Server{
+			Handler:           server.NewESHandler(repo, httpOpts...),
+			ReadHeaderTimeout: 10 * time.Second,
+			ErrorLog:          slog.NewLogLogger(logger.Handler(), slog.LevelError),
+		}
+		servers = append(servers, func(ctx context.Context) error {
+			return server.Serve(ctx, esSrv, esLn, c.shutdownTimeout)
+		})
+		logger.Info("listening for Elasticsearch clients", "addr", c.esAddr)
+	}
+
+	if err := runAll(ctx, servers); err != nil {
+		return err
+	}
+	logger.Info("shut down")
+	return nil
+}
+
+// runAll runs every server until all of them return, cancelling the context
+// of the others as soon as one fails. It returns the first error.
+func runAll(ctx context.Context, servers []func(ctx context.Context) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errs := make(chan error, len(servers))
+	for _, serve := range servers {
+		go func() {
+			errs <- serve(ctx)
+		}()
+	}
+	var firstErr error
+	for range servers {
+		if err := <-errs; err != nil && firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+	}
+	return firstErr
+}
+
+func main() {
+	c := parseFlags()
+	logger := slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: c.logLevel}))
+	if err := run(c, logger); err != nil {
+		logger.Error("server failed", "error", err)
+		os.Exit(1)
+	}
+}
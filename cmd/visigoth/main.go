@@ -0,0 +1,279 @@
+// Command visigoth builds, persists and queries indices locally, with no
+// server involved:
+//
+//	visigoth index -analyzer english -o docs.visigoth ./docs
+//	visigoth search -i docs.visigoth -size 5 graceful shutdown
+//	visigoth analyze -i docs.visigoth "Shutting down gracefully"
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"unicode/utf8"
+
+	"github.com/sonirico/visigoth"
+)
+
+const (
+	defaultIndexPath = "index.visigoth"
+	outputTable      = "table"
+	outputJSON       = "json"
+	maxCellRunes     = 60
+)
+
+const (
+	indexUsage = "index [-analyzer standard] [-search-analyzer name] [-analyzers file] " +
+		"[-o file] [-name name] <dir>"
+	searchUsage = "search [-i file] [-engine hits] [-from 0] [-size 10] [-lang en] " +
+		"[-output table] <terms ...>"
+	analyzeUsage = "analyze [-i file] <text ...>"
+)
+
+var errUsage = errors.New("usage")
+
+type command struct {
+	usage   string
+	summary string
+	run     func(args []string, out io.Writer) error
+}
+
+var commands = map[string]command{
+	"index": {
+		indexUsage,
+		"index the text, Markdown and JSON files in a directory",
+		runIndex,
+	},
+	"search": {
+		searchUsage,
+		"search an index",
+		runSearch,
+	},
+	"analyze": {
+		analyzeUsage,
+		"detail how an index analyzes text",
+		runAnalyze,
+	},
+}
+
+func newFlagSet(name, usage string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: visigoth %s\n", usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+func runIndex(args []string, out io.Writer) error {
+	fs := newFlagSet("index", indexUsage)
+	analyzer := fs.String("analyzer", visigoth.StandardAnalyzer, "analyzer of documents")
+	searchAnalyzer := fs.String("search-analyzer", "",
+		"analyzer of queries, the document analyzer if empty")
+	analyzersPath := fs.String("analyzers", "",
+		"JSON or YAML file with custom analyzer definitions")
+	output := fs.String("o", defaultIndexPath, "file to write the index to")
+	name := fs.String("name", "", "name of the index, the directory name if empty")
+	_ = fs.Parse(args)
+	if fs.NArg() != 1 {
+		return errUsage
+	}
+	dir := fs.Arg(0)
+	if *name == "" {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return err
+		}
+		*name = filepath.Base(abs)
+	}
+
+	registry := visigoth.NewAnalyzerRegistry()
+	var analyzers visigoth.AnalyzersConfig
+	if *analyzersPath != "" {
+		var err error
+		if analyzers, err = visigoth.LoadAnalyzersConfig(*analyzersPath); err != nil {
+			return fmt.Errorf("loading analyzers: %w", err)
+		}
+		// Documents are indexed with the stopwords kept in the snapshot
+		if analyzers.Analyzers, err = inlineStopWords(analyzers.Analyzers); err != nil {
+			return fmt.Errorf("loading analyzers: %w", err)
+		}
+		if err := registry.Load(analyzers); err != nil {
+			return fmt.Errorf("loading analyzers: %w", err)
+		}
+	}
+	builder, err := visigoth.NewAnalyzersMemoryIndexBuilder(registry, *analyzer, *searchAnalyzer)
+	if err != nil {
+		return err
+	}
+	index := builder(*name).(*visigoth.MemoryIndex)
+
+	failed := 0
+	docs := visigoth.NewDirLoader(dir, visigoth.WithLoadErrorHandler(
+		func(err *visigoth.LoadError) error {
+			failed++
+			fmt.Fprintln(os.Stderr, "error:", err)
+			return nil
+		},
+	))
+	defer docs.Close()
+	for docs.Next() {
+		index.Put(docs.Data())
+	}
+	if err := docs.Err(); err != nil {
+		return err
+	}
+	err = saveSnapshot(*output, &snapshot{
+		Version:   snapshotVersion,
+		Name:      *name,
+		Analyzers: analyzers.Analyzers,
+		Index:     index,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(out, "indexed %d documents (%d terms) into %s, %d failed\n",
+		index.Len(), len(index.InvertedIndex), *output, failed)
+	return err
+}
+
+func runSearch(args []string, out io.Writer) error {
+	fs := newFlagSet("search", searchUsage)
+	input := fs.String("i", defaultIndexPath, "index file")
+	engineName := fs.String("engine", "hits",
+		"search engine, one of "+strings.Join(visigoth.EngineNames(), ", "))
+	from := fs.Int("from", 0, "number of results to skip")
+	size := fs.Int("size", 10, "maximum number of results, all if negative")
+	langs := fs.String("lang", "", "comma separated languages to search in")
+	output := fs.String("output", outputTable, "output format, table or json")
+	_ = fs.Parse(args)
+	if fs.NArg() == 0 {
+		return errUsage
+	}
+	if *from < 0 {
+		return fmt.Errorf("negative -from %d", *from)
+	}
+	engine, err := visigoth.EngineByName(*engineName)
+	if err != nil {
+		return err
+	}
+	var opts []visigoth.SearchOption
+	if *langs != "" {
+		var languages []visigoth.Language
+		for _, lang := range strings.Split(*langs, ",") {
+			languages = append(languages, visigoth.Language(lang))
+		}
+		opts = append(opts, visigoth.WithLanguages(languages...))
+	}
+
+	index, err := loadSnapshot(*input)
+	if err != nil {
+		return err
+	}
+	results := visigoth.SearchResults(
+		index.SearchWith(strings.Join(fs.Args(), " "), engine, opts...),
+	)
+	sort.Sort(results)
+	total := len(results)
+	results = results[min(*from, total):]
+	if *size >= 0 && *size < len(results) {
+		results = results[:*size]
+	}
+
+	if *output == outputJSON {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			Total   int                     `json:"total"`
+			From    int                     `json:"from"`
+			Results []visigoth.SearchResult `json:"results"`
+		}{total, *from, results})
+	}
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "#\tID\tHITS\tCONTENT")
+	for i, result := range results {
+		fmt.Fprintf(w, "%d\t%s\t%d\t%s\n",
+			*from+i+1, result.Doc().ID(), result.Hits, truncate(result.Doc().Raw()))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(out, "%d of %d results\n", len(results), total)
+	return err
+}
+
+func runAnalyze(args []string, out io.Writer) error {
+	fs := newFlagSet("analyze", analyzeUsage)
+	input := fs.String("i", defaultIndexPath, "index file")
+	_ = fs.Parse(args)
+	if fs.NArg() == 0 {
+		return errUsage
+	}
+	index, err := loadSnapshot(*input)
+	if err != nil {
+		return err
+	}
+	analysis := index.Analyze(strings.Join(fs.Args(), " "))
+
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	if analysis.CharFiltered != "" {
+		fmt.Fprintf(w, "CharFilters\t%s\n", strconv.Quote(analysis.CharFiltered))
+	}
+	for _, stage := range analysis.Stages {
+		terms := make([]string, len(stage.Tokens))
+		for i, tok := range stage.Tokens {
+			terms[i] = strconv.Quote(tok.Term)
+		}
+		fmt.Fprintf(w, "%s\t%s\n", stage.Name, strings.Join(terms, " "))
+	}
+	return w.Flush()
+}
+
+// truncate shortens s to fit a table cell, in a single line.
+func truncate(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if utf8.RuneCountInString(s) <= maxCellRunes {
+		return s
+	}
+	return string([]rune(s)[:maxCellRunes-1]) + "…"
+}
+
+func usage() {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Fprintln(os.Stderr, "usage: visigoth <command> [flags] [arguments]\n\ncommands:")
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-8s %s\n", name, commands[name].summary)
+	}
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		usage()
+		os.Exit(2)
+	}
+	if err := cmd.run(os.Args[2:], os.Stdout); err != nil {
+		if errors.Is(err, errUsage) {
+			fmt.Fprintf(os.Stderr, "usage: visigoth %s\n", cmd.usage)
+			os.Exit(2)
+		}
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/sonirico/visigoth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunSearch_From(t *testing.T) {
+	builder, err := visigoth.NewAnalyzerMemoryIndexBuilder(
+		visigoth.NewAnalyzerRegistry(),
+		"standard",
+	)
+	require.NoError(t, err)
+	index := builder("dedos").(*visigoth.MemoryIndex)
+	index.Put(visigoth.NewDocRequest("pulgar", "este fue a por huevos"))
+	path := filepath.Join(t.TempDir(), "dedos.visigoth")
+	require.NoError(t, saveSnapshot(path, &snapshot{
+		Version: snapshotVersion,
+		Name:    "dedos",
+		Index:   index,
+	}))
+
+	var out bytes.Buffer
+	require.NoError(t, runSearch([]string{"-i", path, "-from", "5", "huevos"}, &out))
+	assert.Contains(t, out.String(), "#  ID  HITS  CONTENT")
+	assert.NotContains(t, out.String(), "pulgar")
+
+	out.Reset()
+	err = runSearch([]string{"-i", path, "-from", "-1", "huevos"}, &out)
+	assert.EqualError(t, err, "negative -from -1")
+	assert.Empty(t, out.String())
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/sonirico/visigoth"
+)
+
+const snapshotVersion = 1
+
+// stopWordsPathParam is the parameter of stopword filters naming a file to
+// read the stopwords from, which snapshots never keep.
+const stopWordsPathParam = "stopwords_path"
+
+// snapshot is how an index is persisted: the index along with the
+// definitions of the custom analyzers it may use, so that it can be restored
+// without them at hand. The stopwords of files are kept in place of the
+// files, see inlineStopWords.
+type snapshot struct {
+	Version   int                                `json:"version"`
+	Name      string                             `json:"name"`
+	Analyzers map[string]visigoth.AnalyzerConfig `json:"analyzers,omitempty"`
+	Index     *visigoth.MemoryIndex              `json:"index"`
+}
+
+// restore returns the index ready to be searched, with its analyzers.
+func (s *snapshot) restore() (*visigoth.MemoryIndex, error) {
+	if s.Version != snapshotVersion {
+		return nil, fmt.Errorf("unsupported index version %d", s.Version)
+	}
+	if s.Index == nil {
+		return nil, fmt.Errorf("no index in snapshot")
+	}
+	// Restoring a snapshot reads no files, wherever it comes from
+	for name, analyzer := range s.Analyzers {
+		for _, component := range analyzerComponents(analyzer) {
+			if _, ok := component.Params[stopWordsPathParam]; ok {
+				return nil, fmt.Errorf("analyzer '%s' reads stopwords from a file", name)
+			}
+		}
+	}
+	registry := visigoth.NewAnalyzerRegistry()
+	if err := registry.Load(visigoth.AnalyzersConfig{Analyzers: s.Analyzers}); err != nil {
+		return nil, err
+	}
+	if err := s.Index.SetAnalyzer(registry, s.Index.Analyzer); err != nil {
+		return nil, err
+	}
+	if s.Index.SearchAnalyzer != "" {
+		if err := s.Index.SetSearchAnalyzer(registry, s.Index.SearchAnalyzer); err != nil {
+			return nil, err
+		}
+	}
+	return s.Index, nil
+}
+
+// analyzerComponents returns the char filters, tokenizer and filters of the
+// analyzer.
+func analyzerComponents(analyzer visigoth.AnalyzerConfig) []*visigoth.ComponentConfig {
+	var components []*visigoth.ComponentConfig
+	for i := range analyzer.CharFilters {
+		components = append(components, &analyzer.CharFilters[i])
+	}
+	components = append(components, &analyzer.Tokenizer)
+	for i := range analyzer.Filters {
+		components = append(components, &analyzer.Filters[i])
+	}
+	return components
+}
+
+// inlineStopWords returns the analyzers with the stopwords read from files
+// listed along with the other stopwords of their filters, so that they can
+// be restored where the files are not found.
+func inlineStopWords(
+	analyzers map[string]visigoth.AnalyzerConfig,
+) (map[string]visigoth.AnalyzerConfig, error) {
+	res := make(map[string]visigoth.AnalyzerConfig, len(analyzers))
+	for name, analyzer := range analyzers {
+		analyzer.CharFilters = append([]visigoth.ComponentConfig(nil), analyzer.CharFilters...)
+		analyzer.Filters = append([]visigoth.ComponentConfig(nil), analyzer.Filters...)
+		for _, component := range analyzerComponents(analyzer) {
+			path, err := component.Params.String(stopWordsPathParam, "")
+			if err != nil || path == "" {
+				continue
+			}
+			loaded, err := visigoth.LoadStopWords(path)
+			if err != nil {
+				return nil, fmt.Errorf("analyzer '%s': %w", name, err)
+			}
+			words, err := component.Params.Strings("stopwords")
+			if err != nil {
+				return nil, fmt.Errorf("analyzer '%s': %w", name, err)
+			}
+			for word := range loaded {
+				words = append(words, word)
+			}
+			sort.Strings(words)
+
+			params := make(visigoth.ComponentParams, len(component.Params))
+			for k, v := range component.Params {
+				params[k] = v
+			}
+			delete(params, stopWordsPathParam)
+			params["stopwords"] = words
+			component.Params = params
+		}
+		res[name] = analyzer
+	}
+	return res, nil
+}
+
+// saveSnapshot writes the snapshot to path through a temporary file, so that
+// an existing index is never left half written.
+func saveSnapshot(path string, s *snapshot) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := json.NewEncoder(tmp).Encode(s); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func loadSnapshot(path string) (*visigoth.MemoryIndex, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var s snapshot
+	if err := json.NewDecoder(f).Decode(&s); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	index, err := s.restore()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return index, nil
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sonirico/visigoth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshot_RoundTrip(t *testing.T) {
+	config, err := visigoth.ParseAnalyzersJSON([]byte(`{"analyzers": {"dedos": {
+		"tokenizer": "standard",
+		"filters": ["lowercase", {"type": "stemmer", "language": "spanish"}]
+	}}}`))
+	require.NoError(t, err)
+	registry := visigoth.NewAnalyzerRegistry()
+	require.NoError(t, registry.Load(config))
+	builder, err := visigoth.NewAnalyzerMemoryIndexBuilder(registry, "dedos")
+	require.NoError(t, err)
+	index := builder("dedos").(*visigoth.MemoryIndex)
+	index.Put(visigoth.NewDocRequest("pulgar", "Este fue a por huevos"))
+
+	path := filepath.Join(t.TempDir(), "dedos.visigoth")
+	require.NoError(t, saveSnapshot(path, &snapshot{
+		Version:   snapshotVersion,
+		Name:      "dedos",
+		Analyzers: config.Analyzers,
+		Index:     index,
+	}))
+
+	restored, err := loadSnapshot(path)
+	require.NoError(t, err)
+	assert.Equal(t, "dedos", restored.Analyzer)
+	results := restored.Search("HUEVO", visigoth.HitsSearch)
+	require.Equal(t, 1, results.Len())
+	res, _ := results.Get(0)
+	assert.Equal(t, "pulgar", res.Doc().ID())
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "temporary files should be removed")
+}
+
+func TestSnapshot_StopWordsFile(t *testing.T) {
+	dir := t.TempDir()
+	docs := filepath.Join(dir, "docs")
+	require.NoError(t, os.Mkdir(docs, 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(docs, "pulgar.txt"),
+		[]byte("este fue a por huevos"), 0o600))
+	stopwords := filepath.Join(dir, "stopwords.txt")
+	require.NoError(t, os.WriteFile(stopwords, []byte("este | demonstrative\nfue\n"), 0o600))
+	analyzers := filepath.Join(dir, "analyzers.json")
+	config, err := json.Marshal(map[string]any{"analyzers": map[string]any{"dedos": map[string]any{
+		"tokenizer": "standard",
+		"filters": []any{"lowercase", map[string]any{
+			"type": "stopwords", "stopwords": []string{"a"}, "stopwords_path": stopwords,
+		}},
+	}}})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(analyzers, config, 0o600))
+
+	path := filepath.Join(dir, "dedos.visigoth")
+	var out bytes.Buffer
+	require.NoError(t, runIndex([]string{"-analyzers", analyzers, "-analyzer", "dedos",
+		"-o", path, docs}, &out))
+	// Snapshots are restored without the files of stopwords
+	require.NoError(t, os.Remove(stopwords))
+
+	restored, err := loadSnapshot(path)
+	require.NoError(t, err)
+	assert.Equal(t, 1, restored.Search("huevos", visigoth.HitsSearch).Len())
+	assert.Equal(t, 0, restored.Search("este", visigoth.HitsSearch).Len())
+	assert.Equal(t, 0, restored.Search("a", visigoth.HitsSearch).Len())
+
+	// and never read files
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var s snapshot
+	require.NoError(t, json.Unmarshal(data, &s))
+	assert.Equal(t, []any{"a", "este", "fue"}, s.Analyzers["dedos"].Filters[1].Params["stopwords"])
+	s.Analyzers["dedos"].Filters[1].Params[stopWordsPathParam] = "/etc/passwd"
+	_, err = s.restore()
+	assert.EqualError(t, err, "analyzer 'dedos' reads stopwords from a file")
+}
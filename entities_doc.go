@@ -3,6 +3,10 @@ package visigoth
 type Doc struct {
 	Name    string `json:"id"`
 	Content string `json:"raw"`
+	// Statement is the text analyzed to index the document, when other than
+	// its content, such as the values of the fields of a JSON document.
+	Statement string   `json:"statement,omitempty"`
+	Language  Language `json:"language,omitempty"`
 }
 
 func NewDoc(name, content string) Doc {
@@ -16,3 +20,11 @@ func (d Doc) ID() string {
 func (d Doc) Raw() string {
 	return d.Content
 }
+
+// Text returns the text analyzed to index the document.
+func (d Doc) Text() string {
+	if d.Statement != "" {
+		return d.Statement
+	}
+	return d.Content
+}
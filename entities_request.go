@@ -12,6 +12,9 @@ type DocRequest struct {
 	Content   string
 	statement string
 	MimeType  MimeType
+	// Language of the document. When empty, indices analyzing documents per
+	// language detect it.
+	Language Language
 }
 
 func (d DocRequest) ID() string        { return d.Name }
@@ -19,6 +22,12 @@ func (d DocRequest) Raw() string       { return d.Content }
 func (d DocRequest) Mime() MimeType    { return d.MimeType }
 func (d DocRequest) Statement() string { return d.statement }
 
+// WithLanguage returns a copy of the request tagged with the language.
+func (d DocRequest) WithLanguage(lang Language) DocRequest {
+	d.Language = lang
+	return d
+}
+
 func NewDocRequest(name, content string) DocRequest {
 	return DocRequest{
 		Name:      name,
@@ -1,18 +1,26 @@
 module github.com/sonirico/visigoth
 
-go 1.23
+go 1.23.0
 
 require (
 	github.com/kljensen/snowball v0.10.0
 	github.com/mailru/easyjson v0.9.0
 	github.com/sonirico/vago v0.6.1
+	golang.org/x/text v0.28.0
+	google.golang.org/grpc v1.73.0
+	google.golang.org/protobuf v1.36.10
 )
 
-require github.com/josharian/intern v1.0.0 // indirect
+require (
+	github.com/josharian/intern v1.0.0 // indirect
+	golang.org/x/net v0.38.0 // indirect
+	golang.org/x/sys v0.31.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250324211829-b45e905df463 // indirect
+)
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/stretchr/testify v1.10.0
-	gopkg.in/yaml.v3 v3.0.1 // indirect
+	gopkg.in/yaml.v3 v3.0.1
 )
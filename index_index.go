@@ -8,7 +8,10 @@ type tokenizer interface {
 
 type Index interface {
 	Put(payload DocRequest) Index
+	// HasDocument tells whether a document with the ID was put to the index.
+	HasDocument(id string) bool
 	Search(terms string, engine Engine) slices.Slice[SearchResult]
+	SearchWith(terms string, engine Engine, opts ...SearchOption) slices.Slice[SearchResult]
 }
 
 type Builder func(name string) Index
@@ -3,6 +3,8 @@ package visigoth
 import (
 	"bytes"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/sonirico/vago/slices"
 )
@@ -12,9 +14,20 @@ type MemoryIndex struct {
 	name string
 
 	tokenizer tokenizer
+	// searchTokenizer analyzes queries. When nil, tokenizer does.
+	searchTokenizer tokenizer
+
+	// Analyzer and SearchAnalyzer are the names the analyzers were taken from
+	// an AnalyzerRegistry with, if any, so that they can be restored along
+	// with the index.
+	Analyzer       string `json:"analyzer,omitempty"`
+	SearchAnalyzer string `json:"search_analyzer,omitempty"`
 
 	Docs          []Doc            `json:"indexed"`
 	InvertedIndex map[string][]int `json:"inverted"`
+
+	// ids are the IDs of Docs, built on the first call to HasDocument.
+	ids map[string]struct{}
 }
 
 func (mi *MemoryIndex) Len() int {
@@ -30,6 +43,29 @@ func (mi *MemoryIndex) Document(index int) Doc {
 	return mi.Docs[index]
 }
 
+func (mi *MemoryIndex) HasDocument(id string) bool {
+	if mi.ids == nil {
+		mi.ids = make(map[string]struct{}, len(mi.Docs))
+		for _, doc := range mi.Docs {
+			mi.ids[doc.ID()] = struct{}{}
+		}
+	}
+	_, ok := mi.ids[id]
+	return ok
+}
+
+func (mi *MemoryIndex) Terms() []string {
+	terms := make([]string, 0, len(mi.InvertedIndex))
+	for term := range mi.InvertedIndex {
+		terms = append(terms, term)
+	}
+	return terms
+}
+
+func (mi *MemoryIndex) DocFreq(term string) int {
+	return len(mi.InvertedIndex[term])
+}
+
 func (mi *MemoryIndex) String() string {
 	var buf bytes.Buffer
 	buf.WriteString("{\n")
@@ -42,10 +78,28 @@ func (mi *MemoryIndex) String() string {
 }
 
 func (mi *MemoryIndex) Put(payload DocRequest) Index {
-	tokens := mi.tokenizer.Tokenize(payload.Statement())
+	var (
+		tokens []string
+		lang   = payload.Language
+	)
+	if ml, ok := mi.tokenizer.(languageTokenizer); ok {
+		if lang == "" {
+			lang = ml.Detect(payload.Statement())
+		}
+		tokens = ml.TokenizeLanguage(payload.Statement(), lang)
+	} else {
+		tokens = mi.tokenizer.Tokenize(payload.Statement())
+	}
 	next := len(mi.Docs)
 	newDoc := NewDoc(payload.ID(), payload.Raw())
+	newDoc.Language = lang
+	if payload.Statement() != payload.Raw() {
+		newDoc.Statement = payload.Statement()
+	}
 	mi.Docs = append(mi.Docs, newDoc)
+	if mi.ids != nil {
+		mi.ids[newDoc.ID()] = struct{}{}
+	}
 tokenLoop:
 	for _, tok := range tokens {
 		indexedDocs := mi.InvertedIndex[tok]
@@ -60,20 +114,199 @@ tokenLoop:
 }
 
 func (mi *MemoryIndex) Search(payload string, engine Engine) slices.Slice[SearchResult] {
-	return engine(mi.tokenizer.Tokenize(payload), mi)
+	return mi.SearchWith(payload, engine)
+}
+
+// queryTokenizer returns the tokenizer queries are analyzed with, unless
+// overridden for a single search.
+func (mi *MemoryIndex) queryTokenizer() tokenizer {
+	if mi.searchTokenizer != nil {
+		return mi.searchTokenizer
+	}
+	return mi.tokenizer
+}
+
+// SearchWith searches the index applying the given options. Queries analyzed
+// per language are analyzed once per language, matching them only against
+// the documents in that language.
+func (mi *MemoryIndex) SearchWith(
+	payload string,
+	engine Engine,
+	opts ...SearchOption,
+) slices.Slice[SearchResult] {
+	o := NewSearchOptions(opts...)
+	qt := o.Analyzer
+	if qt == nil {
+		qt = mi.queryTokenizer()
+	}
+	ml, ok := qt.(languageTokenizer)
+	if !ok {
+		var indexer Indexer = mi
+		if len(o.Languages) > 0 {
+			indexer = newLanguageIndexer(mi, o.Languages...)
+		}
+		return engine(queryGraph(AdaptTokenizer(qt).TokenStream(payload), indexer))
+	}
+
+	langs := o.Languages
+	if len(langs) == 0 {
+		// Documents in no configured language, either undetected or tagged
+		// with another one, went through the fallback
+		langs = append([]Language{""}, ml.Languages()...)
+	}
+	var results SearchResults
+	seen := make(map[Language]struct{}, len(langs))
+	for _, lang := range langs {
+		// Each pass searches different documents, so searching a language
+		// twice would repeat its results
+		if _, ok := seen[lang]; ok {
+			continue
+		}
+		seen[lang] = struct{}{}
+		tokens := ml.TokenStreamLanguage(payload, lang)
+		var indexer Indexer = newLanguageIndexer(mi, lang)
+		if lang == "" && len(o.Languages) == 0 {
+			indexer = newOtherLanguageIndexer(mi, ml.Languages()...)
+		}
+		results = append(results, engine(queryGraph(tokens, indexer))...)
+	}
+	if len(seen) > 1 {
+		sort.Stable(results)
+	}
+	return slices.Slice[SearchResult](results)
+}
+
+// Analyze details how the index analyzes text.
+func (mi *MemoryIndex) Analyze(text string) Analysis {
+	analysis := analyze(mi.tokenizer, text)
+	analysis.Analyzer = mi.Analyzer
+	return analysis
 }
 
-func NewMemoryIndex(name string, tkr tokenizer) *MemoryIndex {
-	return &MemoryIndex{
+// Suggest proposes spelling corrections for the words of payload, keeping the
+// best candidates per word.
+func (mi *MemoryIndex) Suggest(payload string, maxEdits int) Suggestion {
+	s := capCandidates(mi.suggestAll(payload, maxEdits))
+	fillWords(s, mi)
+	return s
+}
+
+func (mi *MemoryIndex) suggestAll(payload string, maxEdits int) Suggestion {
+	return suggest(payload, AdaptTokenizer(mi.queryTokenizer()).TokenStream(payload), mi, maxEdits)
+}
+
+// word returns, lowercased, the word the term is analyzed from in the first
+// document containing it, or "" if none does.
+func (mi *MemoryIndex) word(term string) string {
+	postings := mi.InvertedIndex[term]
+	if len(postings) == 0 {
+		return ""
+	}
+	doc := mi.Docs[postings[0]]
+	text := doc.Text()
+	var tokens []Token
+	if ml, ok := mi.tokenizer.(languageTokenizer); ok {
+		tokens = ml.TokenStreamLanguage(text, doc.Language)
+	} else {
+		tokens = AdaptTokenizer(mi.tokenizer).TokenStream(text)
+	}
+	for _, tok := range tokens {
+		if tok.Term == term && tok.Start < tok.End {
+			return strings.ToLower(text[tok.Start:tok.End])
+		}
+	}
+	return ""
+}
+
+// MemoryIndexOption configures a MemoryIndex.
+type MemoryIndexOption func(*MemoryIndex)
+
+// WithSearchTokenizer analyzes queries with tkr instead of with the tokenizer
+// documents are indexed with, e.g. to expand synonyms at query time only.
+func WithSearchTokenizer(tkr tokenizer) MemoryIndexOption {
+	return func(mi *MemoryIndex) {
+		mi.searchTokenizer = tkr
+	}
+}
+
+func NewMemoryIndex(name string, tkr tokenizer, opts ...MemoryIndexOption) *MemoryIndex {
+	mi := &MemoryIndex{
 		name:          name,
 		tokenizer:     tkr,
 		Docs:          []Doc{},
 		InvertedIndex: make(map[string][]int),
 	}
+	for _, opt := range opts {
+		opt(mi)
+	}
+	return mi
+}
+
+// SetAnalyzer makes the index analyze with the analyzer registered under name,
+// e.g. to restore the analyzers of an unmarshalled index:
+//
+//	err := mi.SetAnalyzer(registry, mi.Analyzer)
+//	if err == nil && mi.SearchAnalyzer != "" {
+//		err = mi.SetSearchAnalyzer(registry, mi.SearchAnalyzer)
+//	}
+//
+// Documents already indexed are not analyzed again.
+func (mi *MemoryIndex) SetAnalyzer(registry *AnalyzerRegistry, name string) error {
+	analyzer, err := registry.Analyzer(name)
+	if err != nil {
+		return err
+	}
+	mi.tokenizer = analyzer
+	mi.Analyzer = name
+	return nil
 }
 
-func NewMemoryIndexBuilder(tokenizer tokenizer) Builder {
+// SetSearchAnalyzer makes the index analyze queries with the analyzer
+// registered under name.
+func (mi *MemoryIndex) SetSearchAnalyzer(registry *AnalyzerRegistry, name string) error {
+	analyzer, err := registry.Analyzer(name)
+	if err != nil {
+		return err
+	}
+	mi.searchTokenizer = analyzer
+	mi.SearchAnalyzer = name
+	return nil
+}
+
+func NewMemoryIndexBuilder(tokenizer tokenizer, opts ...MemoryIndexOption) Builder {
 	return func(name string) Index {
-		return NewMemoryIndex(name, tokenizer)
+		return NewMemoryIndex(name, tokenizer, opts...)
 	}
 }
+
+// NewAnalyzerMemoryIndexBuilder returns a Builder of indices using the analyzer
+// registered under name, which is recorded in every index built.
+func NewAnalyzerMemoryIndexBuilder(registry *AnalyzerRegistry, name string) (Builder, error) {
+	return NewAnalyzersMemoryIndexBuilder(registry, name, "")
+}
+
+// NewAnalyzersMemoryIndexBuilder returns a Builder of indices indexing
+// documents with the analyzer registered under indexAnalyzer and analyzing
+// queries with the one under searchAnalyzer, or with the index one if empty.
+func NewAnalyzersMemoryIndexBuilder(
+	registry *AnalyzerRegistry,
+	indexAnalyzer string,
+	searchAnalyzer string,
+) (Builder, error) {
+	if _, err := registry.Analyzer(indexAnalyzer); err != nil {
+		return nil, err
+	}
+	if searchAnalyzer != "" {
+		if _, err := registry.Analyzer(searchAnalyzer); err != nil {
+			return nil, err
+		}
+	}
+	return func(indexName string) Index {
+		mi := NewMemoryIndex(indexName, nil)
+		_ = mi.SetAnalyzer(registry, indexAnalyzer)
+		if searchAnalyzer != "" {
+			_ = mi.SetSearchAnalyzer(registry, searchAnalyzer)
+		}
+		return mi
+	}, nil
+}
@@ -108,3 +108,73 @@ func TestIndex_Search_Deterministic(t *testing.T) {
 	assert.Contains(t, firstResult, "go-course")
 	assert.Contains(t, firstResult, "js-course")
 }
+
+func TestIndex_SearchAnalyzer(t *testing.T) {
+	synonyms := NewSynonymMap(true)
+	synonyms.AddEquivalent("js", "javascript")
+	indexAnalyzer := NewTokenizationPipeline(
+		NewKeepAlphanumericTokenizer(),
+		NewLowerCaseTokenizer(),
+	)
+	searchAnalyzer := NewTokenizationPipeline(
+		NewKeepAlphanumericTokenizer(),
+		NewLowerCaseTokenizer(),
+		NewSynonymFilter(synonyms),
+	)
+	in := NewMemoryIndex("courses", indexAnalyzer, WithSearchTokenizer(searchAnalyzer))
+	in.Put(NewDocRequest("js", "Javascript for beginners"))
+
+	assert.Equal(t, 1, in.Search("js", HitsSearch).Len(), "synonyms should expand at query time")
+	assert.ElementsMatch(t, []string{"javascript", "for", "beginners"}, in.Terms(),
+		"synonyms should not be indexed")
+}
+
+func TestIndex_SearchWith_QueryAnalyzer(t *testing.T) {
+	analyzer := NewTokenizationPipeline(
+		NewKeepAlphanumericTokenizer(),
+		NewLowerCaseTokenizer(),
+		NewKeywordMarkerFilter("programming"),
+		NewEnglishStemmer(false),
+	)
+	in := NewMemoryIndex("courses", analyzer)
+	in.Put(NewDocRequest("programming", "programming"))
+	in.Put(NewDocRequest("programs", "programs"))
+
+	assert.Equal(t, 1, in.Search("programming", HitsSearch).Len())
+	exact := NewTokenizationPipeline(NewKeepAlphanumericTokenizer(), NewLowerCaseTokenizer())
+	results := in.SearchWith("programming", HitsSearch, WithQueryAnalyzer(exact))
+	assert.Equal(t, 1, results.Len())
+	res, _ := results.Get(0)
+	assert.Equal(t, "programming", res.Doc().ID())
+
+	results = in.SearchWith("program", HitsSearch, WithQueryAnalyzer(exact))
+	assert.Equal(t, 1, results.Len(), "unstemmed query should only match the stem itself")
+	res, _ = results.Get(0)
+	assert.Equal(t, "programs", res.Doc().ID())
+}
+
+func TestIndex_NamedSearchAnalyzer(t *testing.T) {
+	registry := NewAnalyzerRegistry()
+	builder, err := NewAnalyzersMemoryIndexBuilder(registry, string(Spanish), StandardAnalyzer)
+	assert.NoError(t, err)
+	in := builder("courses").(*MemoryIndex)
+	in.Put(NewDocRequest("go", "Programación en Go"))
+
+	assert.Equal(t, string(Spanish), in.Analyzer)
+	assert.Equal(t, StandardAnalyzer, in.SearchAnalyzer)
+	assert.Equal(t, 0, in.Search("programación", HitsSearch).Len(), "query should not be stemmed")
+	assert.Equal(t, 1, in.Search("program", HitsSearch).Len())
+
+	_, err = NewAnalyzersMemoryIndexBuilder(registry, string(Spanish), "klingon")
+	assert.Error(t, err)
+}
+
+func TestIndex_HasDocument(t *testing.T) {
+	in := NewMemoryIndex("testing", NewKeepAlphanumericTokenizer())
+	in.Put(NewDocRequest("pulgar", "este fue a por huevos"))
+	assert.True(t, in.HasDocument("pulgar"))
+	assert.False(t, in.HasDocument("indice"))
+
+	in.Put(NewDocRequest("indice", "este los puso a freir"))
+	assert.True(t, in.HasDocument("indice"), "documents put after the first call should be known")
+}
@@ -0,0 +1,85 @@
+package visigoth
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/sonirico/vago/streams"
+)
+
+// NewCSVLoader streams the documents of a CSV file whose first row names its
+// columns. IDs and content are taken from the columns set by WithIDField and
+// WithContentFields. A header lacking any of the columns ends the stream with
+// an error, while malformed rows are reported as a *LoadError.
+func NewCSVLoader(r io.Reader, opts ...LoaderOption) streams.ReadStream[DocRequest] {
+	o := newLoaderOptions(opts)
+	reader := csv.NewReader(r)
+	reader.Comma = o.comma
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	var columns map[string]int
+	return newLoaderStream(o, func() (DocRequest, error) {
+		if columns == nil {
+			var err error
+			if columns, err = csvColumns(o, reader); err != nil {
+				return DocRequest{}, err
+			}
+		}
+		record, err := reader.Read()
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				return DocRequest{}, &LoadError{
+					Source: o.source,
+					Line:   parseErr.StartLine,
+					Err:    parseErr.Err,
+				}
+			}
+			return DocRequest{}, err
+		}
+		line, _ := reader.FieldPos(0)
+		if len(record) != len(columns) {
+			return DocRequest{}, &LoadError{
+				Source: o.source,
+				Line:   line,
+				Err:    fmt.Errorf("%d fields, expected %d", len(record), len(columns)),
+			}
+		}
+		doc, err := fieldsDocRequest(o, func(name string) (string, bool) {
+			i, ok := columns[name]
+			if !ok {
+				return "", false
+			}
+			return record[i], true
+		})
+		if err != nil {
+			return DocRequest{}, &LoadError{Source: o.source, Line: line, Err: err}
+		}
+		return doc, nil
+	})
+}
+
+// csvColumns reads the header, checking it has every column needed. Its
+// errors are not a *LoadError, as no row can be loaded without a header.
+func csvColumns(o loaderOptions, reader *csv.Reader) (map[string]int, error) {
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		err = errors.New("no CSV header")
+	}
+	if err != nil {
+		return nil, o.sourceError(err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[name] = i
+	}
+	for _, name := range append([]string{o.idField}, o.contentFields...) {
+		if _, ok := columns[name]; !ok {
+			return nil, o.sourceError(fmt.Errorf("no '%s' column in CSV header", name))
+		}
+	}
+	return columns, nil
+}
@@ -0,0 +1,103 @@
+package visigoth
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sonirico/vago/streams"
+)
+
+// DefaultLoaderExtensions are the extensions of the files loaded from
+// directories by default.
+var DefaultLoaderExtensions = []string{".txt", ".text", ".md", ".markdown", ".json"}
+
+// NewDirLoader streams a document per file under dir with one of the
+// extensions set by WithExtensions, walking subdirectories but hidden ones.
+// IDs are paths relative to dir, with forward slashes.
+//
+// Markdown files go through ParseFrontMatter, and JSON files are loaded whole
+// as MimeJSON documents. Any other file is loaded as text. Files failing to
+// load are reported as a *LoadError.
+func NewDirLoader(dir string, opts ...LoaderOption) streams.ReadStream[DocRequest] {
+	o := newLoaderOptions(opts)
+	extensions := make(map[string]struct{}, len(o.extensions))
+	for _, ext := range o.extensions {
+		extensions[strings.ToLower(ext)] = struct{}{}
+	}
+
+	var (
+		paths  []string
+		walked bool
+	)
+	return newLoaderStream(o, func() (DocRequest, error) {
+		if !walked {
+			walked = true
+			var err error
+			if paths, err = walkDir(dir, extensions); err != nil {
+				return DocRequest{}, err
+			}
+		}
+		if len(paths) == 0 {
+			return DocRequest{}, io.EOF
+		}
+		path := paths[0]
+		paths = paths[1:]
+
+		doc, err := fileDocRequest(o, dir, path)
+		if err != nil {
+			return DocRequest{}, &LoadError{Source: path, Err: err}
+		}
+		return doc, nil
+	})
+}
+
+// walkDir returns the paths of the files under dir with any of the
+// extensions, in lexical order.
+func walkDir(dir string, extensions map[string]struct{}) ([]string, error) {
+	var paths []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if _, ok := extensions[strings.ToLower(filepath.Ext(path))]; ok && d.Type().IsRegular() {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	return paths, err
+}
+
+func fileDocRequest(o loaderOptions, dir, path string) (DocRequest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return DocRequest{}, err
+	}
+	id, err := filepath.Rel(dir, path)
+	if err != nil {
+		return DocRequest{}, err
+	}
+	id = filepath.ToSlash(id)
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".md", ".markdown":
+		return markdownDocRequest(o, id, string(data))
+	case ".json":
+		if !json.Valid(data) {
+			return DocRequest{}, errors.New("invalid JSON")
+		}
+		return NewDocRequestWithMime(id, string(data), MimeJSON), nil
+	default:
+		return NewDocRequest(id, string(data)), nil
+	}
+}
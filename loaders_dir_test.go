@@ -0,0 +1,72 @@
+package visigoth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sonirico/vago/streams"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFrontMatter(t *testing.T) {
+	frontMatter, body, err := ParseFrontMatter(
+		"---\ntitle: Dedos\ntags: [mano]\n---\nEste fue a por huevos\n",
+	)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"title": "Dedos", "tags": []any{"mano"}}, frontMatter)
+	assert.Equal(t, "Este fue a por huevos\n", body)
+
+	frontMatter, body, err = ParseFrontMatter("---\r\ntitle: Dedos\r\n---")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"title": "Dedos"}, frontMatter)
+	assert.Equal(t, "", body)
+
+	frontMatter, body, err = ParseFrontMatter("# Dedos\n---\n")
+	require.NoError(t, err)
+	assert.Nil(t, frontMatter)
+	assert.Equal(t, "# Dedos\n---\n", body)
+
+	_, _, err = ParseFrontMatter("---\ntitle: Dedos\n")
+	assert.Error(t, err)
+}
+
+func TestDirLoader(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"pulgar.txt":        "este fue a por huevos",
+		"mano/indice.md":    "---\nid: indice\ntitle: Indice\nlang: spanish\n---\neste los puso a freir",
+		"mano/corazon.json": `{"dedo": "corazon"}`,
+		"mano/anular.json":  `{"dedo": `,
+		"mano/menique.md":   "---\ntitle: [\n---\n",
+		"foto.png":          "",
+		".git/HEAD.txt":     "ref: refs/heads/main",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+
+	var errs []*LoadError
+	docs, err := streams.Consume(NewDirLoader(dir,
+		WithLanguageField("lang"), collectLoadErrors(&errs)))
+	require.NoError(t, err)
+	require.Len(t, docs, 3)
+	assert.Equal(t, "mano/corazon.json", docs[0].ID())
+	assert.Equal(t, MimeJSON, docs[0].Mime())
+	assert.Equal(t, "indice", docs[1].ID())
+	assert.Equal(t, "este los puso a freir", docs[1].Raw())
+	assert.Equal(t, "Indice\n\neste los puso a freir", docs[1].Statement())
+	assert.Equal(t, Language("spanish"), docs[1].Language)
+	assert.Equal(t, "pulgar.txt", docs[2].ID())
+
+	require.Len(t, errs, 2)
+	assert.Equal(t, filepath.Join(dir, "mano/anular.json"), errs[0].Source)
+	assert.Equal(t, filepath.Join(dir, "mano/menique.md"), errs[1].Source)
+
+	docs, err = streams.Consume(NewDirLoader(filepath.Join(dir, "nope")))
+	assert.Error(t, err)
+	assert.Empty(t, docs)
+}
@@ -0,0 +1,101 @@
+package visigoth
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/sonirico/vago/streams"
+)
+
+// NewJSONLinesLoader streams the documents of r, one JSON object per line:
+//
+//	{"id": "pulgar", "content": "este fue a por huevos", "lang": "es"}
+//
+// IDs and content are taken from the fields set by WithIDField and
+// WithContentFields, or the whole object is the content with
+// WithJSONDocuments. Blank lines are skipped. Lines failing to decode, or
+// lacking any of the fields, are reported as a *LoadError.
+func NewJSONLinesLoader(r io.Reader, opts ...LoaderOption) streams.ReadStream[DocRequest] {
+	o := newLoaderOptions(opts)
+	reader := bufio.NewReader(r)
+	line := 0
+	return newLoaderStream(o, func() (DocRequest, error) {
+		for {
+			data, err := reader.ReadBytes('\n')
+			if len(data) == 0 && err != nil {
+				return DocRequest{}, err
+			}
+			line++
+			data = bytes.TrimSpace(data)
+			if len(data) == 0 {
+				continue
+			}
+			doc, err := jsonDocRequest(o, data)
+			if err != nil {
+				return DocRequest{}, &LoadError{Source: o.source, Line: line, Err: err}
+			}
+			return doc, nil
+		}
+	})
+}
+
+func jsonDocRequest(o loaderOptions, data []byte) (DocRequest, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return DocRequest{}, err
+	}
+	if fields == nil {
+		return DocRequest{}, errors.New("not a JSON object")
+	}
+	field := func(name string) (string, bool) {
+		raw, ok := fields[name]
+		if !ok || string(raw) == "null" {
+			return "", false
+		}
+		var s string
+		if err := json.Unmarshal(raw, &s); err == nil {
+			return s, true
+		}
+		return string(raw), true
+	}
+
+	if !o.jsonDocuments {
+		return fieldsDocRequest(o, field)
+	}
+	id, ok := field(o.idField)
+	if !ok || id == "" {
+		return DocRequest{}, fmt.Errorf("no '%s' field", o.idField)
+	}
+	doc := NewDocRequestWithMime(id, string(data), MimeJSON)
+	if lang, ok := field(o.languageField); ok && o.languageField != "" {
+		doc = doc.WithLanguage(Language(lang))
+	}
+	return doc, nil
+}
+
+// fieldsDocRequest builds a document out of the fields of a record, such as a
+// JSON object or a CSV row.
+func fieldsDocRequest(o loaderOptions, field func(name string) (string, bool)) (DocRequest, error) {
+	id, ok := field(o.idField)
+	if !ok || id == "" {
+		return DocRequest{}, fmt.Errorf("no '%s' field", o.idField)
+	}
+	content := make([]string, len(o.contentFields))
+	for i, name := range o.contentFields {
+		if content[i], ok = field(name); !ok {
+			return DocRequest{}, fmt.Errorf("no '%s' field", name)
+		}
+	}
+	doc := NewDocRequest(id, strings.Join(content, "\n"))
+	if o.languageField != "" {
+		if lang, ok := field(o.languageField); ok {
+			doc = doc.WithLanguage(Language(lang))
+		}
+	}
+	return doc, nil
+}
@@ -0,0 +1,205 @@
+package visigoth
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/sonirico/vago/streams"
+)
+
+// LoadError reports a document that could not be loaded, e.g. a malformed
+// line of a JSON Lines file.
+type LoadError struct {
+	// Source is the file the document comes from, if known.
+	Source string
+	// Line is the 1-based line or CSV row the document starts at, or 0 if the
+	// error concerns a whole file.
+	Line int
+	Err  error
+}
+
+func (e *LoadError) Error() string {
+	switch {
+	case e.Source != "" && e.Line > 0:
+		return fmt.Sprintf("%s:%d: %v", e.Source, e.Line, e.Err)
+	case e.Line > 0:
+		return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+	case e.Source != "":
+		return fmt.Sprintf("%s: %v", e.Source, e.Err)
+	default:
+		return e.Err.Error()
+	}
+}
+
+func (e *LoadError) Unwrap() error {
+	return e.Err
+}
+
+// loaderOptions configure how loaders map records to documents.
+type loaderOptions struct {
+	source        string
+	idField       string
+	contentFields []string
+	languageField string
+	jsonDocuments bool
+	comma         rune
+	extensions    []string
+	onError       func(*LoadError) error
+}
+
+// LoaderOption configures a document loader.
+type LoaderOption func(*loaderOptions)
+
+// WithSourceName names the source documents are read from in errors, e.g.
+// the name of the file.
+func WithSourceName(name string) LoaderOption {
+	return func(o *loaderOptions) {
+		o.source = name
+	}
+}
+
+// WithIDField sets the JSON field, CSV column or front matter key holding
+// document IDs, "id" by default.
+func WithIDField(name string) LoaderOption {
+	return func(o *loaderOptions) {
+		o.idField = name
+	}
+}
+
+// WithContentFields sets the JSON fields or CSV columns whose values, joined
+// by new lines, are the content of documents. It defaults to "content".
+func WithContentFields(names ...string) LoaderOption {
+	return func(o *loaderOptions) {
+		o.contentFields = names
+	}
+}
+
+// WithLanguageField sets the JSON field, CSV column or front matter key
+// holding the language of documents. Documents are not tagged with a language
+// by default.
+func WithLanguageField(name string) LoaderOption {
+	return func(o *loaderOptions) {
+		o.languageField = name
+	}
+}
+
+// WithJSONDocuments makes the JSON Lines loader take every object whole as the
+// content of a MimeJSON document, rather than its content fields.
+func WithJSONDocuments() LoaderOption {
+	return func(o *loaderOptions) {
+		o.jsonDocuments = true
+	}
+}
+
+// WithCSVComma sets the field delimiter of CSV files, ',' by default.
+func WithCSVComma(comma rune) LoaderOption {
+	return func(o *loaderOptions) {
+		o.comma = comma
+	}
+}
+
+// WithExtensions sets the extensions of the files loaded from directories,
+// by default DefaultLoaderExtensions.
+func WithExtensions(exts ...string) LoaderOption {
+	return func(o *loaderOptions) {
+		o.extensions = exts
+	}
+}
+
+// WithLoadErrorHandler sets the function documents failing to load are
+// reported to. If it returns nil, the document is skipped and loading goes
+// on; otherwise, the stream ends with the error returned. By default, the
+// stream ends with the first LoadError.
+func WithLoadErrorHandler(handler func(*LoadError) error) LoaderOption {
+	return func(o *loaderOptions) {
+		o.onError = handler
+	}
+}
+
+func newLoaderOptions(opts []LoaderOption) loaderOptions {
+	o := loaderOptions{
+		idField:       "id",
+		contentFields: []string{"content"},
+		comma:         ',',
+		extensions:    DefaultLoaderExtensions,
+		onError:       func(err *LoadError) error { return err },
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// sourceError prefixes err with the name of the source, if any.
+func (o loaderOptions) sourceError(err error) error {
+	if o.source == "" {
+		return err
+	}
+	return fmt.Errorf("%s: %w", o.source, err)
+}
+
+// loaderStream is a stream of the documents returned by next, until it
+// returns io.EOF. Errors of type *LoadError go through the error handler,
+// any other error ends the stream.
+type loaderStream struct {
+	next    func() (DocRequest, error)
+	onError func(*LoadError) error
+	closer  io.Closer
+
+	current DocRequest
+	err     error
+	done    bool
+}
+
+func (s *loaderStream) Next() bool {
+	for !s.done {
+		doc, err := s.next()
+		var loadErr *LoadError
+		switch {
+		case err == nil:
+			s.current = doc
+			return true
+		case errors.As(err, &loadErr):
+			if err := s.onError(loadErr); err != nil {
+				s.err, s.done = err, true
+			}
+		case errors.Is(err, io.EOF):
+			s.done = true
+		default:
+			s.err, s.done = err, true
+		}
+	}
+	return false
+}
+
+func (s *loaderStream) Data() DocRequest {
+	return s.current
+}
+
+func (s *loaderStream) Err() error {
+	return s.err
+}
+
+func (s *loaderStream) Close() error {
+	s.done = true
+	if s.closer == nil {
+		return nil
+	}
+	return s.closer.Close()
+}
+
+func newLoaderStream(o loaderOptions, next func() (DocRequest, error)) *loaderStream {
+	return &loaderStream{next: next, onError: o.onError}
+}
+
+// PutAll indexes every document of the stream into the index, returning how
+// many were indexed. It stops at the first error of the stream.
+func PutAll(repo Repo, index string, docs streams.ReadStream[DocRequest]) (int, error) {
+	n := 0
+	for docs.Next() {
+		repo.Put(index, docs.Data())
+		n++
+	}
+	return n, docs.Err()
+}
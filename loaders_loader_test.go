@@ -0,0 +1,119 @@
+package visigoth
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sonirico/vago/streams"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func collectLoadErrors(errs *[]*LoadError) LoaderOption {
+	return WithLoadErrorHandler(func(err *LoadError) error {
+		*errs = append(*errs, err)
+		return nil
+	})
+}
+
+func TestJSONLinesLoader(t *testing.T) {
+	input := strings.Join([]string{
+		`{"id": "pulgar", "content": "este fue a por huevos", "lang": "spanish"}`,
+		``,
+		`{"id": 2, "content": "este los puso a freir"}`,
+		`{"id": "corazon"}`,
+		`{"id": "anular", "content": `,
+		`["menique"]`,
+	}, "\n")
+
+	var errs []*LoadError
+	docs, err := streams.Consume(NewJSONLinesLoader(strings.NewReader(input),
+		WithLanguageField("lang"), WithSourceName("dedos.jsonl"), collectLoadErrors(&errs)))
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	assert.Equal(t, "pulgar", docs[0].ID())
+	assert.Equal(t, "este fue a por huevos", docs[0].Statement())
+	assert.Equal(t, Language("spanish"), docs[0].Language)
+	assert.Equal(t, "2", docs[1].ID())
+
+	require.Len(t, errs, 3)
+	assert.Equal(t, "dedos.jsonl:4: no 'content' field", errs[0].Error())
+	assert.Equal(t, 5, errs[1].Line)
+	assert.Equal(t, 6, errs[2].Line)
+}
+
+func TestJSONLinesLoader_StopsAtFirstError(t *testing.T) {
+	docs := NewJSONLinesLoader(strings.NewReader("{\"id\": \"pulgar\", \"content\": \"\"}\n{"))
+	assert.True(t, docs.Next())
+	assert.False(t, docs.Next())
+	var loadErr *LoadError
+	require.ErrorAs(t, docs.Err(), &loadErr)
+	assert.Equal(t, 2, loadErr.Line)
+}
+
+func TestJSONLinesLoader_JSONDocuments(t *testing.T) {
+	line := `{"sku": "a1", "name": "huevos"}`
+	docs, err := streams.Consume(NewJSONLinesLoader(strings.NewReader(line),
+		WithIDField("sku"), WithJSONDocuments()))
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "a1", docs[0].ID())
+	assert.Equal(t, MimeJSON, docs[0].Mime())
+	assert.Equal(t, line, docs[0].Raw())
+}
+
+func TestCSVLoader(t *testing.T) {
+	input := strings.Join([]string{
+		`sku;name;description`,
+		`a1;huevos;"docena de huevos; camperos"`,
+		`a2;aceite`,
+		`;sal;fina`,
+		`a4;"pimen"ton";dulce`,
+		`a5;"pimentón";"de la
+vera"`,
+	}, "\n")
+
+	var errs []*LoadError
+	docs, err := streams.Consume(NewCSVLoader(strings.NewReader(input),
+		WithCSVComma(';'),
+		WithIDField("sku"),
+		WithContentFields("name", "description"),
+		collectLoadErrors(&errs)))
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	assert.Equal(t, "a1", docs[0].ID())
+	assert.Equal(t, "huevos\ndocena de huevos; camperos", docs[0].Raw())
+	assert.Equal(t, "a5", docs[1].ID())
+	assert.Equal(t, "pimentón\nde la\nvera", docs[1].Raw())
+
+	lines := make([]int, len(errs))
+	for i, err := range errs {
+		lines[i] = err.Line
+	}
+	assert.Equal(t, []int{3, 4, 5}, lines)
+}
+
+func TestCSVLoader_MissingColumn(t *testing.T) {
+	docs := NewCSVLoader(strings.NewReader("id,name\n1,huevos\n"),
+		WithSourceName("productos.csv"),
+		WithLoadErrorHandler(func(*LoadError) error { return nil }))
+	assert.False(t, docs.Next())
+	assert.EqualError(t, docs.Err(), "productos.csv: no 'content' column in CSV header")
+}
+
+func TestPutAll(t *testing.T) {
+	repo := newTestIndexRepo()
+	input := "{\"id\": \"pulgar\", \"content\": \"este fue a por huevos\"}\n" +
+		"{\"id\": \"indice\", \"content\": \"y este los puso a freir\"}\n"
+
+	n, err := PutAll(repo, "dedos", NewJSONLinesLoader(strings.NewReader(input)))
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	results, err := repo.Search("dedos", "huevos", HitsSearch)
+	require.NoError(t, err)
+	found, err := streams.Consume(results)
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, "pulgar", found[0].Doc().ID())
+}
@@ -0,0 +1,92 @@
+package visigoth
+
+import (
+	"errors"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// frontMatterDelimiter opens and closes the front matter of Markdown files.
+const frontMatterDelimiter = "---"
+
+// ParseFrontMatter splits Markdown text into its YAML front matter, if any,
+// and its body:
+//
+//	---
+//	title: Este fue a por huevos
+//	lang: spanish
+//	---
+//	Y este los puso a freir.
+//
+// Text with no front matter is returned whole as the body, with nil front
+// matter.
+func ParseFrontMatter(text string) (map[string]any, string, error) {
+	rest, ok := cutLine(text, frontMatterDelimiter)
+	if !ok {
+		return nil, text, nil
+	}
+	var yamlEnd, bodyStart int
+	for offset := 0; ; {
+		end := strings.IndexByte(rest[offset:], '\n')
+		line := rest[offset:]
+		if end >= 0 {
+			line = rest[offset : offset+end]
+		}
+		if strings.TrimRight(line, " \t\r") == frontMatterDelimiter {
+			yamlEnd, bodyStart = offset, len(rest)
+			if end >= 0 {
+				bodyStart = offset + end + 1
+			}
+			break
+		}
+		if end < 0 {
+			return nil, "", errors.New("front matter is not closed")
+		}
+		offset += end + 1
+	}
+
+	frontMatter := map[string]any{}
+	if err := yaml.Unmarshal([]byte(rest[:yamlEnd]), &frontMatter); err != nil {
+		return nil, "", err
+	}
+	return frontMatter, rest[bodyStart:], nil
+}
+
+// cutLine returns text after its first line if that line is line.
+func cutLine(text, line string) (string, bool) {
+	rest, ok := strings.CutPrefix(text, line)
+	if !ok {
+		return text, false
+	}
+	rest = strings.TrimLeft(rest, " \t")
+	if rest, ok = strings.CutPrefix(rest, "\n"); ok {
+		return rest, true
+	}
+	return strings.CutPrefix(rest, "\r\n")
+}
+
+// markdownDocRequest builds a document out of a Markdown file, whose body is
+// the content. The front matter may set the ID and language of the document,
+// in the keys set by WithIDField and WithLanguageField, and its "title" is
+// analyzed along with the body.
+func markdownDocRequest(o loaderOptions, id string, text string) (DocRequest, error) {
+	frontMatter, body, err := ParseFrontMatter(text)
+	if err != nil {
+		return DocRequest{}, err
+	}
+	if value, ok := frontMatter[o.idField].(string); ok && value != "" {
+		id = value
+	}
+	statement := body
+	if title, ok := frontMatter["title"].(string); ok && title != "" {
+		statement = title + "\n\n" + body
+	}
+	doc := NewDocRequestWith(id, body, statement)
+	if o.languageField != "" {
+		if lang, ok := frontMatter[o.languageField].(string); ok {
+			doc = doc.WithLanguage(Language(lang))
+		}
+	}
+	return doc, nil
+}
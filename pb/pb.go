@@ -0,0 +1,5 @@
+// Package pb holds the gRPC service definition of visigoth, served by
+// server.GRPCServer, and its generated code.
+package pb
+
+//go:generate buf generate
@@ -0,0 +1,39 @@
+package visigoth
+
+import "fmt"
+
+// NotFoundError is returned when a named index, alias or analyzer does not
+// exist.
+type NotFoundError struct {
+	// Kind of the missing object, such as "index" or "analyzer".
+	Kind string
+	Name string
+}
+
+func (e NotFoundError) Error() string {
+	return fmt.Sprintf("%s with name '%s' does not exist", e.Kind, e.Name)
+}
+
+// ExistsError is returned when an index or alias cannot be created because
+// another one with the same name exists.
+type ExistsError struct {
+	Kind string
+	Name string
+}
+
+func (e ExistsError) Error() string {
+	return fmt.Sprintf("%s with name '%s' already exists", e.Kind, e.Name)
+}
+
+// QuotaExceededError is returned when a tenant would keep more than its
+// quota allows.
+type QuotaExceededError struct {
+	Tenant string
+	// Resource is what would exceed the limit: "indices", "docs" or "bytes".
+	Resource string
+	Limit    int64
+}
+
+func (e QuotaExceededError) Error() string {
+	return fmt.Sprintf("tenant '%s' would exceed its quota of %d %s", e.Tenant, e.Limit, e.Resource)
+}
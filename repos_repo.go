@@ -16,8 +16,17 @@ type Repo interface {
 	HasAlias(name string) bool
 	Alias(alias string, in string) bool
 	UnAlias(alias, index string) bool
+	Create(in string) bool
 	Put(in string, req DocRequest)
 	Search(index string, terms string, engine Engine) (streams.ReadStream[SearchResult], error)
+	SearchWith(
+		index string,
+		terms string,
+		engine Engine,
+		opts ...SearchOption,
+	) (streams.ReadStream[SearchResult], error)
+	Suggest(index string, terms string) (Suggestion, error)
+	Analyze(name string, text string) (Analysis, error)
 	Rename(old string, new string) bool
 	Drop(in string) bool
 }
@@ -37,8 +46,22 @@ type IndexRepo struct {
 	indicesMu *sync.RWMutex
 	aliases   map[string][]string
 	aliasesMu *sync.RWMutex
+	tenants   map[string]*TenantRepo
+	tenantsMu *sync.RWMutex
 
 	indexBuilder Builder
+	analyzers    *AnalyzerRegistry
+}
+
+// IndexRepoOption configures an IndexRepo.
+type IndexRepoOption func(*IndexRepo)
+
+// WithAnalyzerRegistry sets the registry used to resolve analyzer names. It
+// defaults to one with the built-in analyzers only.
+func WithAnalyzerRegistry(registry *AnalyzerRegistry) IndexRepoOption {
+	return func(h *IndexRepo) {
+		h.analyzers = registry
+	}
 }
 
 func (h *IndexRepo) List() []string {
@@ -112,12 +135,14 @@ func (h *IndexRepo) UnAlias(alias, index string) bool {
 	// only remove an index-alias association
 	h.indicesMu.RLock()
 	if _, ok := h.indices[index]; !ok {
+		h.indicesMu.RUnlock()
 		return false
 	}
 	h.indicesMu.RUnlock()
 	h.aliasesMu.Lock()
 	indices, ok := h.aliases[alias]
 	if !ok {
+		h.aliasesMu.Unlock()
 		return false
 	}
 	// alias already exists, check if already has the index
@@ -135,13 +160,41 @@ func (h *IndexRepo) UnAlias(alias, index string) bool {
 	return true
 }
 
-// Rename handles index renaming
+// SetAlias points the alias to the index only, creating the alias if needed,
+// in a single step so that searches never find it missing. It fails with a
+// NotFoundError if the index does not exist, or with an ExistsError if an
+// index is named as the alias.
+func (h *IndexRepo) SetAlias(alias, index string) error {
+	h.indicesMu.RLock()
+	defer h.indicesMu.RUnlock()
+	if _, ok := h.indices[index]; !ok {
+		return NotFoundError{Kind: "index", Name: index}
+	}
+	if _, ok := h.indices[alias]; ok {
+		return ExistsError{Kind: "index", Name: alias}
+	}
+	h.aliasesMu.Lock()
+	h.aliases[alias] = []string{index}
+	h.aliasesMu.Unlock()
+	return nil
+}
+
+// Rename handles index renaming, unless an index or alias with the new name
+// exists, in which case it refuses to replace it.
 func (h *IndexRepo) Rename(old string, new string) bool {
-	// 1. Check the index exists
 	h.indicesMu.Lock()
+	defer h.indicesMu.Unlock()
+	h.aliasesMu.Lock()
+	defer h.aliasesMu.Unlock()
+	// 1. Check the index exists, and the new name is free
 	index, ok := h.indices[old]
 	if !ok {
-		h.indicesMu.Unlock()
+		return false
+	}
+	if _, ok := h.indices[new]; ok {
+		return false
+	}
+	if _, ok := h.aliases[new]; ok {
 		return false
 	}
 	// 2. Perform the swap
@@ -155,7 +208,6 @@ func (h *IndexRepo) Rename(old string, new string) bool {
 			}
 		}
 	}
-	h.indicesMu.Unlock()
 	return true
 }
 
@@ -163,6 +215,17 @@ func (h *IndexRepo) Search(
 	indexName string,
 	terms string,
 	engine Engine,
+) (streams.ReadStream[SearchResult], error) {
+	return h.SearchWith(indexName, terms, engine)
+}
+
+// SearchWith searches the index, or every index pointed by the alias, applying
+// the given options.
+func (h *IndexRepo) SearchWith(
+	indexName string,
+	terms string,
+	engine Engine,
+	opts ...SearchOption,
 ) (streams.ReadStream[SearchResult], error) {
 	h.indicesMu.RLock()
 	defer h.indicesMu.RUnlock()
@@ -179,7 +242,7 @@ func (h *IndexRepo) Search(
 		aliasedIndices, ok := h.aliases[indexName]
 		if !ok {
 			h.aliasesMu.RUnlock()
-			return nil, fmt.Errorf("index with name '%s' does not exist", indexName)
+			return nil, NotFoundError{Kind: "index", Name: indexName}
 		}
 		indices = make([]Index, len(aliasedIndices))
 		for i, index := range aliasedIndices {
@@ -189,7 +252,7 @@ func (h *IndexRepo) Search(
 	}
 
 	if len(indices) == 1 {
-		sr := indices[0].Search(terms, engine)
+		sr := indices[0].SearchWith(terms, engine, opts...)
 		return streams.MemReader(sr, nil), nil
 	}
 
@@ -203,7 +266,7 @@ func (h *IndexRepo) Search(
 	for _, index := range indices {
 		go func(idx Index) {
 			defer wg.Done()
-			sr := idx.Search(terms, engine)
+			sr := idx.SearchWith(terms, engine, opts...)
 			rl.Lock()
 			result.AppendVector(sr)
 			rl.Unlock()
@@ -213,17 +276,138 @@ func (h *IndexRepo) Search(
 	return streams.MemReader(result, nil), nil
 }
 
+// Suggest proposes spelling corrections for terms using the vocabulary of the
+// given index, or of every index pointed by the alias.
+func (h *IndexRepo) Suggest(indexName string, terms string) (Suggestion, error) {
+	h.indicesMu.RLock()
+	defer h.indicesMu.RUnlock()
+
+	var indices []Index
+	if in, ok := h.indices[indexName]; ok {
+		indices = []Index{in}
+	} else {
+		h.aliasesMu.RLock()
+		aliasedIndices, ok := h.aliases[indexName]
+		if !ok {
+			h.aliasesMu.RUnlock()
+			return Suggestion{}, NotFoundError{Kind: "index", Name: indexName}
+		}
+		indices = make([]Index, len(aliasedIndices))
+		for i, index := range aliasedIndices {
+			indices[i] = h.indices[index]
+		}
+		h.aliasesMu.RUnlock()
+	}
+
+	// Candidates are merged before keeping the best ones, for frequencies to
+	// add up over every index
+	suggestions := make([]Suggestion, 0, len(indices))
+	for _, index := range indices {
+		switch suggester := index.(type) {
+		case candidateSuggester:
+			suggestions = append(suggestions, suggester.suggestAll(terms, DefaultSuggestMaxEdits))
+		case Suggester:
+			suggestions = append(suggestions, suggester.Suggest(terms, DefaultSuggestMaxEdits))
+		}
+	}
+	if len(suggestions) == 0 {
+		return Suggestion{}, fmt.Errorf(
+			"index with name '%s' does not support suggestions",
+			indexName,
+		)
+	}
+	res := MergeSuggestions(suggestions...)
+	for _, index := range indices {
+		if suggester, ok := index.(candidateSuggester); ok {
+			fillWords(res, suggester)
+		}
+	}
+	return res, nil
+}
+
+// Analyze details, stage by stage, how text is analyzed by the index, by the
+// single index pointed by the alias, or else by the registered analyzer with
+// the given name.
+func (h *IndexRepo) Analyze(name string, text string) (Analysis, error) {
+	if indices, ok := h.getIndices(name); ok {
+		if len(indices) != 1 {
+			return Analysis{}, fmt.Errorf("alias '%s' points to %d indices", name, len(indices))
+		}
+		analyzer, ok := indices[0].(Analyzable)
+		if !ok {
+			return Analysis{}, fmt.Errorf("index with name '%s' does not support analysis", name)
+		}
+		return analyzer.Analyze(text), nil
+	}
+
+	analyzer, err := h.analyzers.Analyzer(name)
+	if err != nil {
+		return Analysis{}, NotFoundError{Kind: "index or analyzer", Name: name}
+	}
+	analysis := analyzer.Analyze(text)
+	analysis.Analyzer = name
+	return analysis, nil
+}
+
+// Create adds an empty index, unless an index or alias with the same name
+// already exists.
+func (h *IndexRepo) Create(indexName string) bool {
+	h.indicesMu.Lock()
+	defer h.indicesMu.Unlock()
+	if _, ok := h.indices[indexName]; ok {
+		return false
+	}
+	h.aliasesMu.RLock()
+	_, ok := h.aliases[indexName]
+	h.aliasesMu.RUnlock()
+	if ok {
+		return false
+	}
+	h.indices[indexName] = h.indexBuilder(indexName)
+	return true
+}
+
 func (h *IndexRepo) Put(indexName string, doc DocRequest) {
-	indices, ok := h.getIndices(indexName)
+	_ = h.put(indexName, doc, false)
+}
+
+// Insert puts the document as Put does, unless the index, or any index
+// pointed by the alias, has a document with the same ID, in which case it
+// fails with an ExistsError.
+func (h *IndexRepo) Insert(indexName string, doc DocRequest) error {
+	return h.put(indexName, doc, true)
+}
+
+func (h *IndexRepo) put(indexName string, doc DocRequest, unique bool) error {
 	h.indicesMu.Lock()
+	defer h.indicesMu.Unlock()
+	var indices []Index
+	in, ok := h.indices[indexName]
+	if ok {
+		indices = []Index{in}
+	} else {
+		h.aliasesMu.RLock()
+		var names []string
+		names, ok = h.aliases[indexName]
+		for _, name := range names {
+			indices = append(indices, h.indices[name])
+		}
+		h.aliasesMu.RUnlock()
+	}
 	if !ok {
 		// TODO sanitize name
 		// TODO parametrize index engine
 		in := h.indexBuilder(indexName)
 		h.indices[indexName] = in
 		in.Put(doc)
-		h.indicesMu.Unlock()
-		return
+		return nil
+	}
+	if unique {
+		for _, in := range indices {
+			if in.HasDocument(doc.ID()) {
+				return ExistsError{Kind: "document", Name: doc.ID()}
+			}
+		}
 	}
 	var wg sync.WaitGroup
 	wg.Add(len(indices))
@@ -234,7 +418,7 @@ func (h *IndexRepo) Put(indexName string, doc DocRequest) {
 		}(in)
 	}
 	wg.Wait()
-	h.indicesMu.Unlock()
+	return nil
 }
 
 func (h *IndexRepo) Drop(indexName string) bool {
@@ -273,7 +457,7 @@ func (h *IndexRepo) ListAliases() AliasesResult {
 	aliases := make([]AliasesResultRow, len(h.aliases))
 	i := 0
 	for k, v := range h.aliases {
-		aliases[i] = AliasesResultRow{Alias: k, Indices: v}
+		aliases[i] = AliasesResultRow{Alias: k, Indices: append([]string(nil), v...)}
 		i++
 	}
 	h.aliasesMu.RUnlock()
@@ -315,12 +499,21 @@ func (h *IndexRepo) String() string {
 	return buf.String()
 }
 
-func NewIndexRepo(builder Builder) *IndexRepo {
-	return &IndexRepo{
+func NewIndexRepo(builder Builder, opts ...IndexRepoOption) *IndexRepo {
+	h := &IndexRepo{
 		indices:      make(map[string]Index),
 		indicesMu:    new(sync.RWMutex),
 		aliases:      make(map[string][]string),
 		aliasesMu:    new(sync.RWMutex),
+		tenants:      make(map[string]*TenantRepo),
+		tenantsMu:    new(sync.RWMutex),
 		indexBuilder: builder,
 	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	if h.analyzers == nil {
+		h.analyzers = NewAnalyzerRegistry()
+	}
+	return h
 }
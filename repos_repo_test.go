@@ -1,9 +1,11 @@
 package visigoth
 
 import (
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func newTestIndexRepo() Repo {
@@ -34,6 +36,24 @@ func Test_IndexRepo_Alias_Index_DoesNotExist(t *testing.T) {
 	assert.False(t, ok, "alias should not be created for non-existent index")
 }
 
+func Test_IndexRepo_SetAlias(t *testing.T) {
+	repo := NewIndexRepo(NewMemoryIndexBuilder(NewKeepAlphanumericTokenizer()))
+	repo.Put("dedos", NewDocRequest("pulgar", "este fue a por huevos"))
+	repo.Put("pies", NewDocRequest("talon", "este fue a por sal"))
+	repo.Alias("extremidades", "dedos")
+	repo.Alias("extremidades", "pies")
+
+	require.NoError(t, repo.SetAlias("extremidades", "pies"))
+	assert.Equal(t, []AliasesResultRow{{Alias: "extremidades", Indices: []string{"pies"}}},
+		repo.ListAliases().Aliases)
+	require.NoError(t, repo.SetAlias("patas", "pies"))
+	assert.True(t, repo.HasAlias("patas"))
+
+	assert.Equal(t, NotFoundError{Kind: "index", Name: "manos"}, repo.SetAlias("patas", "manos"))
+	assert.Equal(t, ExistsError{Kind: "index", Name: "dedos"}, repo.SetAlias("dedos", "pies"))
+	assert.True(t, repo.HasAlias("patas"), "failed updates should keep the alias")
+}
+
 func Test_IndexRepo_UnAlias_All_Alias_Exists(t *testing.T) {
 	repo := newTestIndexRepo()
 
@@ -117,6 +137,66 @@ func Test_IndexRepo_Rename_IndexDoesNotExist(t *testing.T) {
 	assert.False(t, ok, "expected index 'deditos' to be non-existent")
 }
 
+func Test_IndexRepo_Rename_TargetExists(t *testing.T) {
+	repo := newTestIndexRepo()
+	repo.Put("dedos", NewDocRequest("pulgar", "este fue a por huevos"))
+	repo.Put("dedos_v2", NewDocRequest("menique", "este los zampo"))
+	repo.Alias("dedos:latest", "dedos_v2")
+
+	assert.False(t, repo.Rename("dedos", "dedos_v2"), "expected index 'dedos_v2' to be kept")
+	assert.False(
+		t,
+		repo.Rename("dedos", "dedos:latest"),
+		"expected alias 'dedos:latest' to be kept",
+	)
+	assert.ElementsMatch(t, []string{"dedos", "dedos_v2"}, repo.List())
+	stream, err := repo.Search("dedos_v2", "zampo", HitsSearch)
+	require.NoError(t, err)
+	assert.True(t, stream.Next(), "expected index 'dedos_v2' to keep its documents")
+}
+
+func Test_IndexRepo_Rename_Concurrent(t *testing.T) {
+	repo := newTestIndexRepo()
+	repo.Put("dedos", NewDocRequest("pulgar", "este fue a por huevos"))
+	repo.Alias("dedos:latest", "dedos")
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			repo.Rename("dedos", "deditos")
+			repo.Rename("deditos", "dedos")
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			for _, row := range repo.ListAliases().Aliases {
+				assert.Contains(t, []string{"dedos", "deditos"}, row.Indices[0])
+			}
+		}
+	}()
+	wg.Wait()
+	assert.Len(t, repo.ListAliases().Aliases, 1)
+}
+
+func Test_IndexRepo_Insert(t *testing.T) {
+	repo := newTestIndexRepo().(*IndexRepo)
+	require.NoError(t, repo.Insert("dedos", NewDocRequest("pulgar", "este fue a por huevos")))
+	repo.Put("manos", NewDocRequest("palma", "este no tiene huevos"))
+	require.True(t, repo.Alias("todo", "dedos"))
+	require.True(t, repo.Alias("todo", "manos"))
+
+	assert.Equal(t, ExistsError{Kind: "document", Name: "pulgar"},
+		repo.Insert("dedos", NewDocRequest("pulgar", "este los puso a freir")))
+	assert.Equal(t, ExistsError{Kind: "document", Name: "palma"},
+		repo.Insert("todo", NewDocRequest("palma", "este los puso a freir")))
+	assert.Empty(t, searchIDs(t, repo, "todo", "freir"))
+	require.NoError(t, repo.Insert("todo", NewDocRequest("indice", "este los puso a freir")))
+	assert.ElementsMatch(t, []string{"indice", "indice"}, searchIDs(t, repo, "todo", "freir"))
+}
+
 func Test_IndexRepo_HotSwap(t *testing.T) {
 	repo := newTestIndexRepo()
 	repo.Put("dedos", NewDocRequest("pulgar", "este fue a por huevos"))
@@ -194,3 +274,30 @@ func Test_IndexRepo_Search_Deterministic(t *testing.T) {
 	// Verify we got all expected documents
 	assert.Len(t, firstResult, 4, "Should find all 4 documents")
 }
+
+func Test_IndexRepo_UnAlias_Index_DoesNotExist(t *testing.T) {
+	repo := newTestIndexRepo()
+
+	repo.Put("dedos", NewDocRequest("pulgar", "este fue a por huevos"))
+	repo.Alias("dedos:latest", "dedos")
+
+	assert.False(t, repo.UnAlias("dedos:latest", "sabores"))
+	assert.False(t, repo.UnAlias("sabores:latest", "dedos"))
+	assert.True(t, repo.Drop("dedos"), "repo locks should have been released")
+}
+
+func Test_IndexRepo_Create(t *testing.T) {
+	repo := newTestIndexRepo()
+
+	assert.True(t, repo.Create("dedos"))
+	assert.True(t, repo.Has("dedos"))
+	assert.False(t, repo.Create("dedos"), "index already exists")
+
+	repo.Alias("dedos:latest", "dedos")
+	assert.False(t, repo.Create("dedos:latest"), "alias already exists")
+
+	_, err := repo.Search("sabores", "huevos", HitsSearch)
+	var notFound NotFoundError
+	assert.ErrorAs(t, err, &notFound)
+	assert.Equal(t, NotFoundError{Kind: "index", Name: "sabores"}, notFound)
+}
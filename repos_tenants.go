@@ -0,0 +1,332 @@
+package visigoth
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/sonirico/vago/streams"
+)
+
+var _ Repo = (*TenantRepo)(nil)
+
+// TenantQuota limits what a tenant keeps. Zero limits mean no limit.
+type TenantQuota struct {
+	MaxIndices int
+	MaxDocs    int
+	// MaxBytes limits the total size of the raw content of documents.
+	MaxBytes int64
+}
+
+// TenantUsage is what a tenant keeps. Documents put to an alias count once
+// per index they are put to.
+type TenantUsage struct {
+	Indices int
+	Docs    int
+	Bytes   int64
+}
+
+// indexUsage is what a tenant keeps in one of its indices.
+type indexUsage struct {
+	docs  int
+	bytes int64
+}
+
+// TenantRepo is the namespace of indices and aliases of a tenant of an
+// IndexRepo. Its names are its own, so that tenants may have indices with
+// the same names, and its aliases can only point to its own indices.
+//
+// Put drops documents which would exceed the quota of the tenant, and Create
+// refuses indices which would, just as when they fail for other reasons. Use
+// TryPut and TryCreate to know why.
+type TenantRepo struct {
+	name string
+	repo *IndexRepo
+
+	// mu serializes the changes to the usage of the tenant, so that it
+	// never goes over its quota.
+	mu      sync.Mutex
+	quota   TenantQuota
+	usage   TenantUsage
+	indices map[string]indexUsage
+}
+
+// Name returns the name of the tenant.
+func (t *TenantRepo) Name() string {
+	return t.name
+}
+
+func (t *TenantRepo) Quota() TenantQuota {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.quota
+}
+
+// SetQuota changes the quota of the tenant. A quota lower than the usage of
+// the tenant prevents it from growing, but keeps what it has.
+func (t *TenantRepo) SetQuota(quota TenantQuota) {
+	t.mu.Lock()
+	t.quota = quota
+	t.mu.Unlock()
+}
+
+func (t *TenantRepo) Usage() TenantUsage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.usage
+}
+
+func (t *TenantRepo) List() []string {
+	return t.repo.List()
+}
+
+func (t *TenantRepo) ListAliases() AliasesResult {
+	return t.repo.ListAliases()
+}
+
+func (t *TenantRepo) Has(name string) bool {
+	return t.repo.Has(name)
+}
+
+func (t *TenantRepo) HasAlias(name string) bool {
+	return t.repo.HasAlias(name)
+}
+
+// Alias adds the index to the alias. Aliases change under the lock of the
+// tenant, so that documents put to an alias are charged to the indices they
+// are put to.
+func (t *TenantRepo) Alias(alias string, in string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.repo.Alias(alias, in)
+}
+
+func (t *TenantRepo) UnAlias(alias, index string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.repo.UnAlias(alias, index)
+}
+
+// SetAlias points the alias to the index only, as IndexRepo.SetAlias does.
+func (t *TenantRepo) SetAlias(alias, index string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.repo.SetAlias(alias, index)
+}
+
+func (t *TenantRepo) Create(in string) bool {
+	return t.TryCreate(in) == nil
+}
+
+// TryCreate adds an empty index, failing with an ExistsError if an index or
+// alias with the same name exists, or with a QuotaExceededError if the
+// tenant has as many indices as its quota allows.
+func (t *TenantRepo) TryCreate(in string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	exists := ExistsError{Kind: "index or alias", Name: in}
+	if t.repo.Has(in) || t.repo.HasAlias(in) {
+		return exists
+	}
+	if err := t.checkIndices(1); err != nil {
+		return err
+	}
+	if !t.repo.Create(in) {
+		return exists
+	}
+	t.indices[in] = indexUsage{}
+	t.usage.Indices++
+	return nil
+}
+
+func (t *TenantRepo) Put(in string, req DocRequest) {
+	_ = t.TryPut(in, req)
+}
+
+// TryPut puts the document to the index, to every index pointed by the
+// alias, or else to a new index, unless the tenant would then exceed its
+// quota, in which case it fails with a QuotaExceededError.
+func (t *TenantRepo) TryPut(in string, req DocRequest) error {
+	return t.put(in, req, false)
+}
+
+// Insert puts the document as TryPut does, unless the index, or any index
+// pointed by the alias, has a document with the same ID, in which case it
+// fails with an ExistsError.
+func (t *TenantRepo) Insert(in string, req DocRequest) error {
+	return t.put(in, req, true)
+}
+
+func (t *TenantRepo) put(in string, req DocRequest, unique bool) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	names, ok := t.repo.indexNames(in)
+	if !ok {
+		if err := t.checkIndices(1); err != nil {
+			return err
+		}
+		names = []string{in}
+	}
+	docs, bytes := len(names), int64(len(names)*len(req.Raw()))
+	if t.quota.MaxDocs > 0 && t.usage.Docs+docs > t.quota.MaxDocs {
+		return QuotaExceededError{Tenant: t.name, Resource: "docs", Limit: int64(t.quota.MaxDocs)}
+	}
+	if t.quota.MaxBytes > 0 && t.usage.Bytes+bytes > t.quota.MaxBytes {
+		return QuotaExceededError{Tenant: t.name, Resource: "bytes", Limit: t.quota.MaxBytes}
+	}
+
+	if unique {
+		if err := t.repo.Insert(in, req); err != nil {
+			return err
+		}
+	} else {
+		t.repo.Put(in, req)
+	}
+	if !ok {
+		t.usage.Indices++
+	}
+	for _, name := range names {
+		usage := t.indices[name]
+		usage.docs++
+		usage.bytes += int64(len(req.Raw()))
+		t.indices[name] = usage
+	}
+	t.usage.Docs += docs
+	t.usage.Bytes += bytes
+	return nil
+}
+
+func (t *TenantRepo) checkIndices(n int) error {
+	if t.quota.MaxIndices > 0 && t.usage.Indices+n > t.quota.MaxIndices {
+		return QuotaExceededError{
+			Tenant:   t.name,
+			Resource: "indices",
+			Limit:    int64(t.quota.MaxIndices),
+		}
+	}
+	return nil
+}
+
+func (t *TenantRepo) Search(
+	index string,
+	terms string,
+	engine Engine,
+) (streams.ReadStream[SearchResult], error) {
+	return t.repo.Search(index, terms, engine)
+}
+
+func (t *TenantRepo) SearchWith(
+	index string,
+	terms string,
+	engine Engine,
+	opts ...SearchOption,
+) (streams.ReadStream[SearchResult], error) {
+	return t.repo.SearchWith(index, terms, engine, opts...)
+}
+
+func (t *TenantRepo) Suggest(index string, terms string) (Suggestion, error) {
+	return t.repo.Suggest(index, terms)
+}
+
+func (t *TenantRepo) Analyze(name string, text string) (Analysis, error) {
+	return t.repo.Analyze(name, text)
+}
+
+// Rename renames the index, unless an index or alias with the new name exists,
+// as IndexRepo.Rename does.
+func (t *TenantRepo) Rename(old string, new string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.repo.Rename(old, new) {
+		return false
+	}
+	t.indices[new] = t.indices[old]
+	delete(t.indices, old)
+	return true
+}
+
+func (t *TenantRepo) Drop(in string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.repo.Drop(in) {
+		return false
+	}
+	t.release(t.indices[in])
+	delete(t.indices, in)
+	return true
+}
+
+// release frees the usage of an index which no longer exists.
+func (t *TenantRepo) release(usage indexUsage) {
+	t.usage.Indices--
+	t.usage.Docs -= usage.docs
+	t.usage.Bytes -= usage.bytes
+}
+
+func newTenantRepo(name string, quota TenantQuota, h *IndexRepo) *TenantRepo {
+	return &TenantRepo{
+		name:    name,
+		repo:    NewIndexRepo(h.indexBuilder, WithAnalyzerRegistry(h.analyzers)),
+		quota:   quota,
+		indices: make(map[string]indexUsage),
+	}
+}
+
+// CreateTenant adds a tenant, whose indices are built as those of the repo,
+// limited by the quota, unless a tenant with the same name exists. The
+// indices and aliases of the repo itself belong to no tenant.
+func (h *IndexRepo) CreateTenant(name string, quota TenantQuota) (*TenantRepo, bool) {
+	h.tenantsMu.Lock()
+	defer h.tenantsMu.Unlock()
+	if _, ok := h.tenants[name]; ok {
+		return nil, false
+	}
+	tenant := newTenantRepo(name, quota, h)
+	h.tenants[name] = tenant
+	return tenant, true
+}
+
+// Tenant returns the tenant with the given name, if it exists.
+func (h *IndexRepo) Tenant(name string) (*TenantRepo, bool) {
+	h.tenantsMu.RLock()
+	defer h.tenantsMu.RUnlock()
+	tenant, ok := h.tenants[name]
+	return tenant, ok
+}
+
+// Tenants returns the names of the tenants, sorted.
+func (h *IndexRepo) Tenants() []string {
+	h.tenantsMu.RLock()
+	names := make([]string, 0, len(h.tenants))
+	for name := range h.tenants {
+		names = append(names, name)
+	}
+	h.tenantsMu.RUnlock()
+	sort.Strings(names)
+	return names
+}
+
+// DropTenant removes the tenant along with its indices and aliases.
+func (h *IndexRepo) DropTenant(name string) bool {
+	h.tenantsMu.Lock()
+	defer h.tenantsMu.Unlock()
+	if _, ok := h.tenants[name]; !ok {
+		return false
+	}
+	delete(h.tenants, name)
+	return true
+}
+
+// indexNames returns the name of the index, or the names of the indices
+// pointed by the alias.
+func (h *IndexRepo) indexNames(name string) ([]string, bool) {
+	h.indicesMu.RLock()
+	defer h.indicesMu.RUnlock()
+	if _, ok := h.indices[name]; ok {
+		return []string{name}, true
+	}
+	h.aliasesMu.RLock()
+	defer h.aliasesMu.RUnlock()
+	indices, ok := h.aliases[name]
+	return append([]string(nil), indices...), ok
+}
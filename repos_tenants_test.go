@@ -0,0 +1,151 @@
+package visigoth
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTenants(t *testing.T, quota TenantQuota) (*IndexRepo, *TenantRepo, *TenantRepo) {
+	repo := newTestIndexRepo().(*IndexRepo)
+	acme, ok := repo.CreateTenant("acme", quota)
+	require.True(t, ok)
+	globex, ok := repo.CreateTenant("globex", quota)
+	require.True(t, ok)
+	return repo, acme, globex
+}
+
+func searchIDs(t *testing.T, repo Repo, index, terms string) []string {
+	t.Helper()
+	stream, err := repo.Search(index, terms, HitsSearch)
+	require.NoError(t, err)
+	var ids []string
+	for stream.Next() {
+		ids = append(ids, stream.Data().Doc().ID())
+	}
+	return ids
+}
+
+func Test_IndexRepo_Tenants(t *testing.T) {
+	repo, acme, _ := newTestTenants(t, TenantQuota{})
+
+	_, ok := repo.CreateTenant("acme", TenantQuota{})
+	assert.False(t, ok, "tenant should not be created twice")
+	tenant, ok := repo.Tenant("acme")
+	require.True(t, ok)
+	assert.Same(t, acme, tenant)
+	assert.Equal(t, []string{"acme", "globex"}, repo.Tenants())
+
+	assert.True(t, repo.DropTenant("globex"))
+	assert.False(t, repo.DropTenant("globex"))
+	_, ok = repo.Tenant("globex")
+	assert.False(t, ok)
+	assert.Equal(t, []string{"acme"}, repo.Tenants())
+}
+
+func Test_TenantRepo_Namespaces(t *testing.T) {
+	repo, acme, globex := newTestTenants(t, TenantQuota{})
+
+	repo.Put("dedos", NewDocRequest("menique", "y este se lo comio"))
+	acme.Put("dedos", NewDocRequest("pulgar", "este fue a por huevos"))
+	globex.Put("dedos", NewDocRequest("indice", "este los puso a freir"))
+	globex.Put("manos", NewDocRequest("palma", "este no tiene huevos"))
+
+	assert.Equal(t, []string{"dedos"}, acme.List())
+	assert.Equal(t, []string{"dedos"}, repo.List())
+	assert.Equal(t, []string{"pulgar"}, searchIDs(t, acme, "dedos", "este"))
+	assert.Equal(t, []string{"indice"}, searchIDs(t, globex, "dedos", "este"))
+	assert.Equal(t, []string{"menique"}, searchIDs(t, repo, "dedos", "este"))
+	assert.Equal(t, ExistsError{Kind: "document", Name: "indice"},
+		globex.Insert("dedos", NewDocRequest("indice", "")))
+	require.NoError(t, acme.Insert("dedos", NewDocRequest("indice", "este los puso a freir")))
+	assert.Equal(t, TenantUsage{Indices: 2, Docs: 2, Bytes: 41}, globex.Usage())
+	_, err := acme.Search("manos", "huevos", HitsSearch)
+	assert.Equal(t, NotFoundError{Kind: "index", Name: "manos"}, err)
+
+	assert.False(t, acme.Alias("todo", "manos"),
+		"alias should not point to indices of other tenants")
+	assert.True(t, acme.Alias("todo", "dedos"))
+	assert.False(t, globex.HasAlias("todo"))
+	assert.Equal(t, []string{"pulgar"}, searchIDs(t, acme, "todo", "huevos"))
+
+	assert.True(t, acme.Drop("dedos"))
+	assert.True(t, globex.Has("dedos"))
+	assert.True(t, repo.Has("dedos"))
+}
+
+func Test_TenantRepo_Quota(t *testing.T) {
+	_, acme, globex := newTestTenants(t, TenantQuota{MaxIndices: 2, MaxDocs: 3, MaxBytes: 40})
+
+	require.NoError(t, acme.TryPut("dedos", NewDocRequest("pulgar", "este fue a por huevos")))
+	require.NoError(t, acme.TryCreate("manos"))
+	assert.Equal(t, ExistsError{Kind: "index or alias", Name: "manos"}, acme.TryCreate("manos"))
+	assert.Equal(t,
+		QuotaExceededError{Tenant: "acme", Resource: "indices", Limit: 2},
+		acme.TryCreate("pies"))
+	assert.Equal(t,
+		QuotaExceededError{Tenant: "acme", Resource: "indices", Limit: 2},
+		acme.TryPut("pies", NewDocRequest("dedo", "gordo")))
+	assert.False(t, acme.Has("pies"))
+
+	require.True(t, acme.Alias("todo", "dedos"))
+	require.True(t, acme.Alias("todo", "manos"))
+	err := acme.TryPut("todo", NewDocRequest("indice", "este los puso a freir"))
+	assert.Equal(t, QuotaExceededError{Tenant: "acme", Resource: "bytes", Limit: 40}, err)
+	require.NoError(t, acme.TryPut("todo", NewDocRequest("corazon", "sal")))
+	assert.Equal(t, TenantUsage{Indices: 2, Docs: 3, Bytes: 27}, acme.Usage())
+	err = acme.TryPut("manos", NewDocRequest("palma", ""))
+	assert.EqualError(t, err, "tenant 'acme' would exceed its quota of 3 docs")
+	acme.Put("manos", NewDocRequest("palma", ""))
+	assert.Empty(t, searchIDs(t, acme, "manos", "palma"))
+
+	assert.Equal(t, TenantUsage{}, globex.Usage(), "quotas should be kept per tenant")
+
+	assert.True(t, acme.Rename("dedos", "pies"))
+	assert.Equal(t, TenantUsage{Indices: 2, Docs: 3, Bytes: 27}, acme.Usage())
+	assert.True(t, acme.Drop("pies"))
+	assert.Equal(t, TenantUsage{Indices: 1, Docs: 1, Bytes: 3}, acme.Usage())
+	require.NoError(t, acme.TryPut("dedos", NewDocRequest("pulgar", "este fue a por huevos")))
+
+	acme.SetQuota(TenantQuota{MaxDocs: 1})
+	assert.Equal(t, TenantQuota{MaxDocs: 1}, acme.Quota())
+	assert.Error(t, acme.TryPut("dedos", NewDocRequest("indice", "")))
+	assert.Equal(t, 2, acme.Usage().Docs, "lowering the quota should keep documents")
+}
+
+func Test_TenantRepo_Usage_AliasChangedWhilePutting(t *testing.T) {
+	_, acme, _ := newTestTenants(t, TenantQuota{})
+	require.NoError(t, acme.TryCreate("dedos"))
+	require.NoError(t, acme.TryCreate("manos"))
+	require.True(t, acme.Alias("todo", "dedos"))
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		// The alias always points to an index, to either one in turn
+		from, to := "dedos", "manos"
+		for range 200 {
+			acme.Alias("todo", to)
+			acme.UnAlias("todo", from)
+			from, to = to, from
+		}
+	}()
+	for i := range 200 {
+		acme.Put("todo", NewDocRequest(fmt.Sprintf("doc-%d", i), "huevos"))
+	}
+	wg.Wait()
+
+	stream, err := acme.Search("manos", "", NoopAllSearch)
+	require.NoError(t, err)
+	var docs int
+	for stream.Next() {
+		docs++
+	}
+	require.True(t, acme.Drop("dedos"))
+	assert.Equal(t, TenantUsage{Indices: 1, Docs: docs, Bytes: int64(docs * len("huevos"))},
+		acme.Usage(), "documents should be charged to the indices they are put to")
+}
@@ -0,0 +1,213 @@
+package visigoth
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/sonirico/vago/slices"
+)
+
+type HighlightOptions struct {
+	// PreTag and PostTag wrap every matched term, "<em>" and "</em>" by default.
+	PreTag  string
+	PostTag string
+	// FragmentSize is the approximate length, in bytes, of every fragment.
+	// A value lower than 1 returns the whole text as a single fragment.
+	FragmentSize int
+	// MaxFragments caps the number of fragments returned. A value lower than 1
+	// returns every fragment.
+	MaxFragments int
+}
+
+func DefaultHighlightOptions() HighlightOptions {
+	return HighlightOptions{
+		PreTag:       "<em>",
+		PostTag:      "</em>",
+		FragmentSize: 100,
+		MaxFragments: 3,
+	}
+}
+
+// Highlighter marks the terms of a query in the text documents were indexed
+// with.
+//
+// Both query and text are run through the same TokenizationPipeline used for
+// indexing, so that surface forms are matched by their analyzed form: with a
+// SpanishStemmerFilter, the query "programar" highlights "programación" and
+// "programa" alike, while the tags wrap the text exactly as it was written.
+// Fragments show the text as char filters leave it, e.g. with no markup.
+type Highlighter struct {
+	analyzer *TokenizationPipeline
+	opts     HighlightOptions
+}
+
+// span is a byte range [start, end) of the original text.
+type span struct {
+	start int
+	end   int
+}
+
+type fragment struct {
+	span
+	matches []span
+}
+
+// Highlight returns the fragments of text containing terms of the query, with
+// every match wrapped in the configured tags. Fragments are sorted by number
+// of matches, then by position. Nil is returned when nothing matches.
+func (h *Highlighter) Highlight(query string, text string) []string {
+	text, analyzer := h.analyzer.filterChars(text)
+	matches := h.matches(query, text, analyzer)
+	if len(matches) == 0 {
+		return nil
+	}
+
+	if h.opts.FragmentSize < 1 {
+		return []string{h.mark(text, fragment{span: span{0, len(text)}, matches: matches})}
+	}
+
+	fragments := h.fragments(text, matches)
+	sort.SliceStable(fragments, func(i, j int) bool {
+		return len(fragments[i].matches) > len(fragments[j].matches)
+	})
+	if h.opts.MaxFragments > 0 && len(fragments) > h.opts.MaxFragments {
+		fragments = fragments[:h.opts.MaxFragments]
+	}
+
+	res := make([]string, len(fragments))
+	for i, frag := range fragments {
+		res[i] = h.mark(text, frag)
+	}
+	return res
+}
+
+// HighlightResults fills SearchResult.Highlights for every result, from the
+// text its document was indexed with.
+func (h *Highlighter) HighlightResults(
+	query string,
+	results slices.Slice[SearchResult],
+) slices.Slice[SearchResult] {
+	for i, result := range results {
+		results[i].Highlights = h.Highlight(query, result.Document.Text())
+	}
+	return results
+}
+
+// matches returns the offsets of the tokens of text, as analyzed by analyzer,
+// whose analyzed form is one of the analyzed terms of the query, in order of
+// appearance.
+func (h *Highlighter) matches(query string, text string, analyzer *TokenizationPipeline) []span {
+	terms := make(map[string]struct{})
+	for _, term := range h.analyzer.Tokenize(query) {
+		terms[term] = struct{}{}
+	}
+	if len(terms) == 0 {
+		return nil
+	}
+
+	var res []span
+	for _, tok := range analyzer.TokenStream(text) {
+		if _, ok := terms[tok.Term]; !ok {
+			continue
+		}
+		// Filters may inject several tokens for the same surface text, or
+		// spanning several words, as multi-word synonyms do. Overlapping
+		// tokens are merged so that matches never go backwards
+		if n := len(res); n > 0 && tok.Start < res[n-1].end {
+			res[n-1].end = max(res[n-1].end, tok.End)
+			continue
+		}
+		res = append(res, span{start: tok.Start, end: tok.End})
+	}
+	return res
+}
+
+// fragments groups matches into windows of roughly FragmentSize bytes, padded
+// with surrounding context and snapped to word boundaries.
+func (h *Highlighter) fragments(text string, matches []span) []fragment {
+	size := h.opts.FragmentSize
+	var res []fragment
+	for i := 0; i < len(matches); {
+		frag := fragment{span: matches[i], matches: []span{matches[i]}}
+		i++
+		for i < len(matches) && matches[i].end-frag.start <= size {
+			frag.end = matches[i].end
+			frag.matches = append(frag.matches, matches[i])
+			i++
+		}
+
+		first, last := frag.start, frag.end
+		if pad := size - (last - first); pad > 0 {
+			frag.start = max(0, first-pad/2)
+			frag.end = min(len(text), frag.start+size)
+			frag.start = max(0, frag.end-size)
+		}
+		if len(res) > 0 {
+			// Do not repeat the context already shown by the previous fragment
+			if prevEnd := res[len(res)-1].end; prevEnd <= first {
+				frag.start = max(frag.start, prevEnd)
+			}
+		}
+		frag.start = snapStart(text, frag.start, first)
+		frag.end = snapEnd(text, frag.end, last)
+		res = append(res, frag)
+	}
+	return res
+}
+
+// snapStart moves pos forward to the beginning of a word, without going past
+// limit.
+func snapStart(text string, pos int, limit int) int {
+	if pos == 0 {
+		return pos
+	}
+	for pos < limit && !utf8.RuneStart(text[pos]) {
+		pos++
+	}
+	prev, _ := utf8.DecodeLastRuneInString(text[:pos])
+	if unicode.IsSpace(prev) {
+		return pos
+	}
+	if next := strings.IndexFunc(text[pos:limit], unicode.IsSpace); next >= 0 {
+		return pos + next + 1
+	}
+	return pos
+}
+
+// snapEnd moves pos backwards to the end of a word, without going before limit.
+func snapEnd(text string, pos int, limit int) int {
+	if pos == len(text) {
+		return pos
+	}
+	for pos > limit && !utf8.RuneStart(text[pos]) {
+		pos--
+	}
+	next, _ := utf8.DecodeRuneInString(text[pos:])
+	if unicode.IsSpace(next) {
+		return pos
+	}
+	if prev := strings.LastIndexFunc(text[limit:pos], unicode.IsSpace); prev >= 0 {
+		return limit + prev
+	}
+	return pos
+}
+
+func (h *Highlighter) mark(text string, frag fragment) string {
+	var buf strings.Builder
+	cursor := frag.start
+	for _, m := range frag.matches {
+		buf.WriteString(text[cursor:m.start])
+		buf.WriteString(h.opts.PreTag)
+		buf.WriteString(text[m.start:m.end])
+		buf.WriteString(h.opts.PostTag)
+		cursor = m.end
+	}
+	buf.WriteString(text[cursor:frag.end])
+	return strings.TrimSpace(buf.String())
+}
+
+func NewHighlighter(analyzer *TokenizationPipeline, opts HighlightOptions) *Highlighter {
+	return &Highlighter{analyzer: analyzer, opts: opts}
+}
@@ -0,0 +1,125 @@
+package visigoth
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestHighlighter(opts HighlightOptions) *Highlighter {
+	return NewHighlighter(NewTokenizationPipeline(
+		NewKeepAlphanumericTokenizer(),
+		NewLowerCaseTokenizer(),
+		NewStopWordsFilter(SpanishStopWords),
+		NewSpanishStemmer(true),
+	), opts)
+}
+
+func TestHighlighter_Highlight(t *testing.T) {
+	t.Run("Stemmed tokens are mapped back to surface text", func(t *testing.T) {
+		opts := DefaultHighlightOptions()
+		opts.FragmentSize = 0
+		h := newTestHighlighter(opts)
+
+		res := h.Highlight("programar", "Curso de Programación en Java (León)")
+		assert.Equal(t, []string{"Curso de <em>Programación</em> en Java (León)"}, res)
+	})
+
+	t.Run("Custom tags and several matches", func(t *testing.T) {
+		h := newTestHighlighter(HighlightOptions{PreTag: "**", PostTag: "**"})
+
+		res := h.Highlight("java león", "Curso de programación en Java (León)")
+		assert.Equal(t, []string{"Curso de programación en **Java** (**León**)"}, res)
+	})
+
+	t.Run("Stopwords are never highlighted", func(t *testing.T) {
+		h := newTestHighlighter(DefaultHighlightOptions())
+
+		res := h.Highlight("de java", "Curso de programación en Java")
+		assert.Equal(t, []string{"Curso de programación en <em>Java</em>"}, res)
+	})
+
+	t.Run("No matches", func(t *testing.T) {
+		h := newTestHighlighter(DefaultHighlightOptions())
+
+		assert.Nil(t, h.Highlight("python", "Curso de programación en Java"))
+	})
+
+	t.Run("Fragments", func(t *testing.T) {
+		h := newTestHighlighter(HighlightOptions{
+			PreTag:       "[",
+			PostTag:      "]",
+			FragmentSize: 30,
+			MaxFragments: 2,
+		})
+		text := strings.Join([]string{
+			"El lenguaje Java nació en los noventa.",
+			"Hoy existen muchos otros lenguajes populares como Go o Rust.",
+			"Aun así, Java sigue siendo uno de los lenguajes más usados.",
+		}, " ")
+
+		res := h.Highlight("java", text)
+		assert.Len(t, res, 2)
+		for _, frag := range res {
+			assert.Contains(t, frag, "[Java]")
+			assert.LessOrEqual(t, len(frag), 30+len("[]"))
+			assert.False(
+				t,
+				strings.HasPrefix(frag, " "),
+				"fragments should start at word boundaries",
+			)
+		}
+		assert.Equal(t, "El lenguaje [Java] nació en los", res[0])
+		assert.Equal(t, "Aun así, [Java] sigue siendo", res[1])
+	})
+
+	t.Run("Multi-word synonyms", func(t *testing.T) {
+		synonyms := NewSynonymMap(true)
+		synonyms.AddEquivalent("ny", "new york", "big apple city")
+		h := NewHighlighter(NewTokenizationPipeline(
+			NewUnicodeTokenizer(),
+			NewLowerCaseTokenizer(),
+			NewSynonymFilter(synonyms),
+		), HighlightOptions{PreTag: "[", PostTag: "]"})
+
+		res := h.Highlight("ny", "I love new york today foo")
+		assert.Equal(t, []string{"I love [new york] today foo"}, res)
+	})
+
+	t.Run("Max fragments keeps the best ones", func(t *testing.T) {
+		h := newTestHighlighter(HighlightOptions{
+			PreTag:       "[",
+			PostTag:      "]",
+			FragmentSize: 20,
+			MaxFragments: 1,
+		})
+
+		res := h.Highlight("java go", "Go es rápido. Aprende sobre Java y Go con ejemplos")
+		assert.Equal(t, []string{"[Java] y [Go] con"}, res)
+	})
+}
+
+func TestHighlighter_HighlightResults(t *testing.T) {
+	h := newTestHighlighter(DefaultHighlightOptions())
+	in := NewMemoryIndex("testing", h.analyzer)
+	in.Put(NewDocRequest("/course/java", "Curso de programación en Java"))
+	in.Put(NewDocRequest("/course/php", "Curso de programación en PHP"))
+
+	results := h.HighlightResults("java", in.Search("java", HitsSearch))
+	assert.Equal(t, 1, results.Len())
+	assert.Equal(t, []string{"Curso de programación en <em>Java</em>"}, results[0].Highlights)
+}
+
+func TestHighlighter_HighlightResults_Statement(t *testing.T) {
+	h := newTestHighlighter(DefaultHighlightOptions())
+	in := NewMemoryIndex("testing", h.analyzer)
+	in.Put(NewDocRequestWith("/course/java",
+		`{"title":"Curso","body":"Programaci\u00f3n en Java"}`, "Curso\nProgramación en Java"))
+
+	// Documents are highlighted in the text they were indexed with
+	results := h.HighlightResults("programación", in.Search("programación", HitsSearch))
+	require.Equal(t, 1, results.Len())
+	assert.Equal(t, []string{"Curso\n<em>Programación</em> en Java"}, results[0].Highlights)
+}
@@ -0,0 +1,93 @@
+package visigoth
+
+// SearchOptions tweak how a single search is run.
+type SearchOptions struct {
+	// Languages restricts results to documents in any of these languages.
+	Languages []Language
+	// Analyzer analyzes the query instead of the search analyzer of the index.
+	Analyzer tokenizer
+}
+
+type SearchOption func(*SearchOptions)
+
+// WithLanguages restricts results to documents in any of the given languages.
+func WithLanguages(langs ...Language) SearchOption {
+	return func(o *SearchOptions) {
+		o.Languages = append(o.Languages, langs...)
+	}
+}
+
+// WithQueryAnalyzer analyzes the query with analyzer instead of with the
+// search analyzer of the index, e.g. to skip stemming on exact-match queries.
+func WithQueryAnalyzer(analyzer tokenizer) SearchOption {
+	return func(o *SearchOptions) {
+		o.Analyzer = analyzer
+	}
+}
+
+// NewSearchOptions returns the options resulting from applying opts.
+func NewSearchOptions(opts ...SearchOption) SearchOptions {
+	var o SearchOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// languageIndexer narrows an Indexer down to the documents in some languages,
+// or in any other language if exclude is set. Its documents are numbered
+// anew, so engines walking them instead of the postings only see those.
+type languageIndexer struct {
+	Indexer
+	// positions are the indices in Indexer of the documents, ascending.
+	positions []int
+	// local maps indices in Indexer to indices in positions.
+	local map[int]int
+}
+
+func (l languageIndexer) Len() int {
+	return len(l.positions)
+}
+
+func (l languageIndexer) Document(index int) Doc {
+	return l.Indexer.Document(l.positions[index])
+}
+
+func (l languageIndexer) Indexed(key string) []int {
+	indexed := l.Indexer.Indexed(key)
+	res := make([]int, 0, len(indexed))
+	for _, index := range indexed {
+		if local, ok := l.local[index]; ok {
+			res = append(res, local)
+		}
+	}
+	if len(res) == 0 {
+		return nil
+	}
+	return res
+}
+
+func narrowLanguages(indexer Indexer, exclude bool, langs ...Language) languageIndexer {
+	languages := make(map[Language]struct{}, len(langs))
+	for _, lang := range langs {
+		languages[lang] = struct{}{}
+	}
+	l := languageIndexer{Indexer: indexer, local: make(map[int]int)}
+	for i := 0; i < indexer.Len(); i++ {
+		if _, ok := languages[indexer.Document(i).Language]; ok != exclude {
+			l.local[i] = len(l.positions)
+			l.positions = append(l.positions, i)
+		}
+	}
+	return l
+}
+
+func newLanguageIndexer(indexer Indexer, langs ...Language) languageIndexer {
+	return narrowLanguages(indexer, false, langs...)
+}
+
+// newOtherLanguageIndexer narrows the indexer down to the documents in none of
+// the languages.
+func newOtherLanguageIndexer(indexer Indexer, langs ...Language) languageIndexer {
+	return narrowLanguages(indexer, true, langs...)
+}
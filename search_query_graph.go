@@ -0,0 +1,133 @@
+package visigoth
+
+import (
+	"slices"
+	"strings"
+)
+
+// segmentKeyPrefix marks the keys standing for query segments with
+// alternatives. It can't be produced by analysis, tokens never hold it.
+const segmentKeyPrefix = "\x00"
+
+// queryGraphIndexer resolves the keys of query segments with alternatives to
+// the documents matching any of them, and any other key as usual.
+type queryGraphIndexer struct {
+	Indexer
+	segments map[string][]int
+}
+
+func (q queryGraphIndexer) Indexed(key string) []int {
+	if indexed, ok := q.segments[key]; ok {
+		return indexed
+	}
+	return q.Indexer.Indexed(key)
+}
+
+// queryGraph turns analyzed query tokens into the terms engines look for.
+// Synonyms injected at query time are alternatives rather than terms that
+// must all be found: every run of overlapping positions holding synonyms
+// becomes a single key, matching the documents that contain every term of
+// any of the paths through it. For instance, "ml" and "machine learning"
+// match documents with either "ml" or both "machine" and "learning". Other
+// stacked tokens, such as n-grams, must all be found as usual, and so do
+// the tokens of queries with no synonyms.
+func queryGraph(tokens []Token, indexer Indexer) ([]string, Indexer) {
+	if !slices.ContainsFunc(tokens, isSynonym) {
+		return Terms(tokens), indexer
+	}
+
+	var (
+		terms []string
+		graph = queryGraphIndexer{Indexer: indexer, segments: make(map[string][]int)}
+	)
+	for start := 0; start < len(tokens); {
+		end := start + 1
+		last := tokens[start].Position + tokenLength(tokens[start])
+		for end < len(tokens) && tokens[end].Position < last {
+			last = max(last, tokens[end].Position+tokenLength(tokens[end]))
+			end++
+		}
+		segment := tokens[start:end]
+		start = end
+
+		if len(segment) == 1 || !slices.ContainsFunc(segment, isSynonym) {
+			terms = append(terms, Terms(segment)...)
+			continue
+		}
+		paths := segmentPaths(segment, segment[0].Position, last)
+		keys := make([]string, len(paths))
+		var indexed []int
+		for i, path := range paths {
+			keys[i] = strings.Join(path, " ")
+			docs := indexer.Indexed(path[0])
+			for _, term := range path[1:] {
+				docs = intersection(docs, indexer.Indexed(term))
+			}
+			indexed = union(indexed, docs)
+		}
+		if len(indexed) == 0 {
+			indexed = nil
+		}
+		key := segmentKeyPrefix + strings.Join(keys, "|")
+		graph.segments[key] = indexed
+		terms = append(terms, key)
+	}
+	return terms, graph
+}
+
+// isSynonym tells whether the token was injected by a SynonymFilter, or spans
+// the positions of a multi-word synonym.
+func isSynonym(tok Token) bool {
+	return tok.Type == TokenSynonym || tok.PositionLength > 1
+}
+
+func tokenLength(tok Token) int {
+	return max(tok.PositionLength, 1)
+}
+
+// segmentPaths lists the sequences of terms going from position from to
+// position to through segment. Positions no token starts at, left by removed
+// tokens, are skipped.
+func segmentPaths(segment []Token, from, to int) [][]string {
+	if from >= to {
+		return [][]string{nil}
+	}
+	var paths [][]string
+	next := to
+	for _, tok := range segment {
+		if tok.Position > from {
+			next = min(next, tok.Position)
+		}
+		if tok.Position != from {
+			continue
+		}
+		for _, rest := range segmentPaths(segment, from+tokenLength(tok), to) {
+			paths = append(paths, append([]string{tok.Term}, rest...))
+		}
+	}
+	if paths == nil {
+		return segmentPaths(segment, next, to)
+	}
+	return paths
+}
+
+// union merges two sorted lists of document indices.
+func union(a []int, b []int) []int {
+	r := make([]int, 0, len(a)+len(b))
+	var i, j int
+	for i < len(a) && j < len(b) {
+		if a[i] < b[j] {
+			r = append(r, a[i])
+			i++
+		} else if a[i] > b[j] {
+			r = append(r, b[j])
+			j++
+		} else {
+			r = append(r, a[i])
+			i++
+			j++
+		}
+	}
+	r = append(r, a[i:]...)
+	return append(r, b[j:]...)
+}
@@ -0,0 +1,84 @@
+package visigoth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryGraph_MultiWordSynonyms(t *testing.T) {
+	synonyms := NewSynonymMap(true)
+	synonyms.AddEquivalent("machine learning", "ml")
+	indexAnalyzer := NewTokenizationPipeline(NewUnicodeTokenizer(), NewLowerCaseTokenizer())
+	searchAnalyzer := NewTokenizationPipeline(
+		NewUnicodeTokenizer(),
+		NewLowerCaseTokenizer(),
+		NewSynonymFilter(synonyms),
+	)
+	in := NewMemoryIndex("courses", indexAnalyzer, WithSearchTokenizer(searchAnalyzer))
+	in.Put(NewDocRequest("ml", "ML course"))
+	in.Put(NewDocRequest("machine-learning", "Machine learning course"))
+	in.Put(NewDocRequest("machine", "Machine course about learning nothing"))
+	in.Put(NewDocRequest("learning", "Learning course"))
+
+	for _, query := range []string{"ml course", "machine learning course"} {
+		for _, engine := range []Engine{HitsSearch, LinearSearch} {
+			results := in.Search(query, engine)
+			var ids []string
+			for _, res := range results {
+				ids = append(ids, res.Doc().ID())
+			}
+			assert.ElementsMatch(t, []string{"ml", "machine-learning", "machine"}, ids, query)
+		}
+	}
+
+	assert.Equal(t, 0, in.Search("ml python", HitsSearch).Len())
+}
+
+func TestQueryGraph_NoStackedTokens(t *testing.T) {
+	tokens := []Token{
+		{Term: "go", Position: 0, PositionIncrement: 1},
+		{Term: "course", Position: 2, PositionIncrement: 2},
+	}
+	in := NewMemoryIndex("courses", nil)
+	terms, indexer := queryGraph(tokens, in)
+	assert.Equal(t, []string{"go", "course"}, terms)
+	assert.Equal(t, in, indexer)
+}
+
+func TestSegmentPaths(t *testing.T) {
+	segment := []Token{
+		{Term: "machine", Position: 0},
+		{Term: "ml", Position: 0, PositionLength: 3},
+		{Term: "learning", Position: 2},
+	}
+	assert.Equal(t,
+		[][]string{{"machine", "learning"}, {"ml"}},
+		segmentPaths(segment, 0, 3),
+		"positions no token starts at should be skipped",
+	)
+}
+
+func TestUnion(t *testing.T) {
+	assert.Equal(t, []int{1, 2, 3, 5, 8}, union([]int{1, 3, 8}, []int{2, 3, 5}))
+	assert.Equal(t, []int{4}, union(nil, []int{4}))
+}
+
+func TestQueryGraph_NGrams(t *testing.T) {
+	in := NewMemoryIndexBuilder(NewTokenizationPipeline(
+		NewKeepAlphanumericTokenizer(),
+		NewLowerCaseTokenizer(),
+		NewNGramFilter(3, 4, true),
+	))("recetas")
+	in.Put(NewDocRequest("programming", "Programación en Go"))
+	in.Put(NewDocRequest("ramen", "Ramen casero"))
+	in.Put(NewDocRequest("grande", "Tortilla grande"))
+
+	// Stacked n-grams are not alternatives, every one must be found
+	for _, engine := range []Engine{HitsSearch, LinearSearch} {
+		results := in.Search("gram", engine)
+		assert.Equal(t, 1, results.Len())
+		res, _ := results.Get(0)
+		assert.Equal(t, "programming", res.Doc().ID())
+	}
+}
@@ -4,8 +4,9 @@ package visigoth
 
 //easyjson:json
 type SearchResult struct {
-	Document Doc `json:"doc"`
-	Hits     int `json:"hits"`
+	Document   Doc      `json:"doc"`
+	Hits       int      `json:"hits"`
+	Highlights []string `json:"highlights,omitempty"`
 }
 
 func (r SearchResult) GetDoc() Doc {
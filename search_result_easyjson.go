@@ -41,6 +41,29 @@ func easyjsonBb771ebaDecodeGithubComSoniricoVisigoth(in *jlexer.Lexer, out *Sear
 			easyjsonBb771ebaDecodeGithubComSoniricoVisigoth1(in, &out.Document)
 		case "hits":
 			out.Hits = int(in.Int())
+		case "highlights":
+			if in.IsNull() {
+				in.Skip()
+				out.Highlights = nil
+			} else {
+				in.Delim('[')
+				if out.Highlights == nil {
+					if !in.IsDelim(']') {
+						out.Highlights = make([]string, 0, 4)
+					} else {
+						out.Highlights = []string{}
+					}
+				} else {
+					out.Highlights = (out.Highlights)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v1 string
+					v1 = string(in.String())
+					out.Highlights = append(out.Highlights, v1)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -65,6 +88,20 @@ func easyjsonBb771ebaEncodeGithubComSoniricoVisigoth(out *jwriter.Writer, in Sea
 		out.RawString(prefix)
 		out.Int(int(in.Hits))
 	}
+	if len(in.Highlights) != 0 {
+		const prefix string = ",\"highlights\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v2, v3 := range in.Highlights {
+				if v2 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v3))
+			}
+			out.RawByte(']')
+		}
+	}
 	out.RawByte('}')
 }
 
@@ -114,6 +151,10 @@ func easyjsonBb771ebaDecodeGithubComSoniricoVisigoth1(in *jlexer.Lexer, out *Doc
 			out.Name = string(in.String())
 		case "raw":
 			out.Content = string(in.String())
+		case "statement":
+			out.Statement = string(in.String())
+		case "language":
+			out.Language = Language(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -138,5 +179,15 @@ func easyjsonBb771ebaEncodeGithubComSoniricoVisigoth1(out *jwriter.Writer, in Do
 		out.RawString(prefix)
 		out.String(string(in.Content))
 	}
+	if in.Statement != "" {
+		const prefix string = ",\"statement\":"
+		out.RawString(prefix)
+		out.String(string(in.Statement))
+	}
+	if in.Language != "" {
+		const prefix string = ",\"language\":"
+		out.RawString(prefix)
+		out.String(string(in.Language))
+	}
 	out.RawByte('}')
 }
@@ -1,6 +1,12 @@
 package visigoth
 
-import "github.com/sonirico/vago/slices"
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/sonirico/vago/slices"
+)
 
 type EngineType byte
 
@@ -20,3 +26,46 @@ type Indexer interface {
 
 // Engine defines the function signature for search functions
 type Engine func(tokens []string, indexable Indexer) slices.Slice[SearchResult]
+
+// engines are the built-in engines by name.
+var engines = map[string]Engine{
+	"hits":      HitsSearch,
+	"linear":    LinearSearch,
+	"noop_zero": NoopZeroSearch,
+	"noop_all":  NoopAllSearch,
+}
+
+// EngineByName returns the built-in engine with the given name, one of
+// EngineNames.
+func EngineByName(name string) (Engine, error) {
+	engine, ok := engines[name]
+	if !ok {
+		return nil, fmt.Errorf("engine with name '%s' does not exist", name)
+	}
+	return engine, nil
+}
+
+// EngineName returns the name of a built-in engine, or false for any other
+// engine.
+func EngineName(engine Engine) (string, bool) {
+	if engine == nil {
+		return "", false
+	}
+	ptr := reflect.ValueOf(engine).Pointer()
+	for name, e := range engines {
+		if reflect.ValueOf(e).Pointer() == ptr {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// EngineNames returns the names of the built-in engines, sorted.
+func EngineNames() []string {
+	names := make([]string, 0, len(engines))
+	for name := range engines {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,25 @@
+package visigoth
+
+import (
+	"testing"
+
+	"github.com/sonirico/vago/slices"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngineName(t *testing.T) {
+	for _, name := range EngineNames() {
+		engine, err := EngineByName(name)
+		require.NoError(t, err)
+		got, ok := EngineName(engine)
+		assert.True(t, ok)
+		assert.Equal(t, name, got)
+	}
+
+	custom := func([]string, Indexer) slices.Slice[SearchResult] { return nil }
+	_, ok := EngineName(custom)
+	assert.False(t, ok)
+	_, ok = EngineName(nil)
+	assert.False(t, ok)
+}
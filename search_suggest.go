@@ -0,0 +1,331 @@
+package visigoth
+
+import (
+	"sort"
+	"strings"
+)
+
+// DefaultSuggestMaxEdits is the edit distance used by IndexRepo.Suggest.
+// Two edits cover the vast majority of real-world typos.
+const DefaultSuggestMaxEdits = 2
+
+// maxSuggestCandidates bounds how many alternatives are kept per token.
+const maxSuggestCandidates = 5
+
+// Vocabulary exposes the indexed terms along with their document frequency.
+type Vocabulary interface {
+	Terms() []string
+	DocFreq(term string) int
+}
+
+// Suggester is implemented by indices able to propose spelling corrections
+// from their own vocabulary.
+type Suggester interface {
+	Suggest(terms string, maxEdits int) Suggestion
+}
+
+// candidateSuggester is implemented by indices able to suggest every
+// candidate within the edits, so that suggestions of several indices are
+// merged before the best candidates are kept, and to tell the word of their
+// documents a term is analyzed from.
+type candidateSuggester interface {
+	suggestAll(terms string, maxEdits int) Suggestion
+	word(term string) string
+}
+
+type SuggestionCandidate struct {
+	Term string `json:"term"`
+	// Word is a word of the indexed documents analyzed as Term, so that
+	// corrections read as words rather than as stems.
+	Word     string `json:"word,omitempty"`
+	Distance int    `json:"distance"`
+	DocFreq  int    `json:"doc_freq"`
+}
+
+type TokenSuggestion struct {
+	Token string `json:"token"`
+	// Text is the word of the query the token is analyzed from, found at the
+	// byte offsets [Start, End) of the query.
+	Text       string                `json:"text,omitempty"`
+	Start      int                   `json:"start"`
+	End        int                   `json:"end"`
+	DocFreq    int                   `json:"doc_freq"`
+	Candidates []SuggestionCandidate `json:"candidates"`
+}
+
+// Best returns the most likely correction for the token, as a word when
+// known. Tokens already present in the vocabulary are considered correct and
+// returned as typed.
+func (t TokenSuggestion) Best() string {
+	if !t.misspelled() {
+		if t.Text != "" {
+			return t.Text
+		}
+		return t.Token
+	}
+	if best := t.Candidates[0]; best.Word != "" {
+		return best.Word
+	}
+	return t.Candidates[0].Term
+}
+
+func (t TokenSuggestion) misspelled() bool {
+	return t.DocFreq < 1 && len(t.Candidates) > 0
+}
+
+type Suggestion struct {
+	// Text is the query the tokens are analyzed from.
+	Text   string            `json:"text,omitempty"`
+	Tokens []TokenSuggestion `json:"tokens"`
+}
+
+// Query returns the whole query rewritten with the best correction per token.
+// Words of the query are replaced only when misspelled, keeping the rest of
+// the query as typed.
+func (s Suggestion) Query() string {
+	if !s.located() {
+		terms := make([]string, len(s.Tokens))
+		for i, tok := range s.Tokens {
+			terms[i] = tok.Best()
+		}
+		return strings.Join(terms, " ")
+	}
+	// Words analyzed into several tokens, e.g. expanded with synonyms, are
+	// correct when any of them is
+	known := make(map[[2]int]bool, len(s.Tokens))
+	for _, tok := range s.Tokens {
+		if !tok.misspelled() {
+			known[[2]int{tok.Start, tok.End}] = true
+		}
+	}
+	var b strings.Builder
+	last := 0
+	for _, tok := range s.Tokens {
+		if !tok.misspelled() || known[[2]int{tok.Start, tok.End}] || tok.Start < last {
+			continue
+		}
+		b.WriteString(s.Text[last:tok.Start])
+		b.WriteString(tok.Best())
+		last = tok.End
+	}
+	b.WriteString(s.Text[last:])
+	return b.String()
+}
+
+// located reports whether every token knows the word of Text it is analyzed
+// from.
+func (s Suggestion) located() bool {
+	if s.Text == "" {
+		return false
+	}
+	for _, tok := range s.Tokens {
+		if tok.Text == "" || tok.End > len(s.Text) || s.Text[tok.Start:tok.End] != tok.Text {
+			return false
+		}
+	}
+	return true
+}
+
+// Changed reports whether any token would be replaced by Query.
+func (s Suggestion) Changed() bool {
+	return s.Query() != s.typed()
+}
+
+// typed returns the query as Query would return it with no correction.
+func (s Suggestion) typed() string {
+	if s.located() {
+		return s.Text
+	}
+	terms := make([]string, len(s.Tokens))
+	for i, tok := range s.Tokens {
+		terms[i] = tok.Token
+	}
+	return strings.Join(terms, " ")
+}
+
+// Suggest proposes, for each already analyzed token, the vocabulary terms
+// within maxEdits edits (Damerau-Levenshtein, optimal string alignment).
+//
+// Candidates are ranked by:
+// 1. Edit distance, ascending
+// 2. Document frequency, descending (more common terms are more likely)
+// 3. Term, ascending, for determinism
+//
+// Only tokens missing from the vocabulary receive candidates.
+func Suggest(tokens []string, vocab Vocabulary, maxEdits int) Suggestion {
+	stream := make([]Token, len(tokens))
+	for i, token := range tokens {
+		stream[i] = Token{Term: token}
+	}
+	return capCandidates(suggest("", stream, vocab, maxEdits))
+}
+
+// suggest proposes every candidate for the tokens analyzed from text.
+func suggest(text string, tokens []Token, vocab Vocabulary, maxEdits int) Suggestion {
+	res := Suggestion{Text: text, Tokens: make([]TokenSuggestion, len(tokens))}
+	var terms []string
+	for i, token := range tokens {
+		ts := TokenSuggestion{
+			Token:   token.Term,
+			Start:   token.Start,
+			End:     token.End,
+			DocFreq: vocab.DocFreq(token.Term),
+		}
+		if text != "" && token.Start < token.End && token.End <= len(text) {
+			ts.Text = text[token.Start:token.End]
+		}
+		if ts.DocFreq < 1 {
+			if terms == nil {
+				terms = vocab.Terms()
+			}
+			ts.Candidates = suggestCandidates(token.Term, terms, vocab, maxEdits)
+		}
+		res.Tokens[i] = ts
+	}
+	return res
+}
+
+// MergeSuggestions combines suggestions computed against several indices for
+// the same query, adding up document frequencies per term. Frequencies are
+// exact only for suggestions with every candidate, as the best ones are kept
+// once merged.
+func MergeSuggestions(suggestions ...Suggestion) Suggestion {
+	if len(suggestions) == 0 {
+		return Suggestion{}
+	}
+	if len(suggestions) == 1 {
+		return capCandidates(suggestions[0])
+	}
+	res := Suggestion{
+		Text:   suggestions[0].Text,
+		Tokens: make([]TokenSuggestion, len(suggestions[0].Tokens)),
+	}
+	for i, first := range suggestions[0].Tokens {
+		merged := first
+		merged.DocFreq, merged.Candidates = 0, nil
+		byTerm := make(map[string]SuggestionCandidate)
+		for _, s := range suggestions {
+			if i >= len(s.Tokens) {
+				continue
+			}
+			merged.DocFreq += s.Tokens[i].DocFreq
+			for _, c := range s.Tokens[i].Candidates {
+				if prev, ok := byTerm[c.Term]; ok {
+					c.DocFreq += prev.DocFreq
+					if prev.Word != "" {
+						c.Word = prev.Word
+					}
+				}
+				byTerm[c.Term] = c
+			}
+		}
+		if merged.DocFreq < 1 {
+			for _, c := range byTerm {
+				merged.Candidates = append(merged.Candidates, c)
+			}
+			sortCandidates(merged.Candidates)
+		}
+		res.Tokens[i] = merged
+	}
+	return capCandidates(res)
+}
+
+// capCandidates keeps the best maxSuggestCandidates candidates per token.
+func capCandidates(s Suggestion) Suggestion {
+	for i, tok := range s.Tokens {
+		if len(tok.Candidates) > maxSuggestCandidates {
+			s.Tokens[i].Candidates = tok.Candidates[:maxSuggestCandidates]
+		}
+	}
+	return s
+}
+
+// fillWords sets the words of the candidates in s the index knows.
+func fillWords(s Suggestion, index candidateSuggester) {
+	for _, tok := range s.Tokens {
+		for j, c := range tok.Candidates {
+			if c.Word == "" {
+				tok.Candidates[j].Word = index.word(c.Term)
+			}
+		}
+	}
+}
+
+func suggestCandidates(
+	token string,
+	terms []string,
+	vocab Vocabulary,
+	maxEdits int,
+) []SuggestionCandidate {
+	source := []rune(token)
+	var candidates []SuggestionCandidate
+	for _, term := range terms {
+		target := []rune(term)
+		if abs(len(source)-len(target)) > maxEdits {
+			continue
+		}
+		d := editDistance(source, target, maxEdits)
+		if d > maxEdits {
+			continue
+		}
+		candidates = append(candidates, SuggestionCandidate{
+			Term:     term,
+			Distance: d,
+			DocFreq:  vocab.DocFreq(term),
+		})
+	}
+	sortCandidates(candidates)
+	return candidates
+}
+
+func sortCandidates(candidates []SuggestionCandidate) {
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.Distance != b.Distance {
+			return a.Distance < b.Distance
+		}
+		if a.DocFreq != b.DocFreq {
+			return a.DocFreq > b.DocFreq
+		}
+		return a.Term < b.Term
+	})
+}
+
+// editDistance computes the optimal string alignment distance between a and
+// b. Computation stops early returning maxEdits+1 once every cell of a row
+// exceeds maxEdits.
+func editDistance(a, b []rune, maxEdits int) int {
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+			rowMin = min(rowMin, curr[j])
+		}
+		if rowMin > maxEdits {
+			return maxEdits + 1
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return prev[len(b)]
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,143 @@
+package visigoth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{a: "java", b: "java", expected: 0},
+		{a: "jvaa", b: "java", expected: 1},
+		{a: "jav", b: "java", expected: 1},
+		{a: "jaba", b: "java", expected: 1},
+		{a: "programacion", b: "programación", expected: 1},
+		{a: "pyhton", b: "python", expected: 1},
+		{a: "go", b: "java", expected: 4},
+	}
+
+	for _, test := range tests {
+		d := editDistance([]rune(test.a), []rune(test.b), 4)
+		assert.Equal(
+			t,
+			test.expected,
+			d,
+			"unexpected distance between '%s' and '%s'",
+			test.a,
+			test.b,
+		)
+	}
+
+	assert.Equal(t, 3, editDistance([]rune("golang"), []rune("java"), 2),
+		"distance beyond maxEdits should be capped to maxEdits+1")
+}
+
+func TestMemoryIndex_Suggest(t *testing.T) {
+	pipeline := NewTokenizationPipeline(NewKeepAlphanumericTokenizer(), NewLowerCaseTokenizer())
+	in := NewMemoryIndex("testing", pipeline)
+	in.Put(NewDocRequest("java-course", "programming course java"))
+	in.Put(NewDocRequest("javascript-course", "programming course javascript"))
+	in.Put(NewDocRequest("lava-course", "volcano lava course"))
+	in.Put(NewDocRequest("python-course", "programming course python"))
+
+	t.Run("Misspelled token", func(t *testing.T) {
+		s := in.Suggest("pyhton", DefaultSuggestMaxEdits)
+		assert.Len(t, s.Tokens, 1)
+		assert.Equal(t, "python", s.Tokens[0].Best())
+		assert.Equal(t, "python", s.Query())
+		assert.True(t, s.Changed())
+	})
+
+	t.Run("Ties broken by document frequency", func(t *testing.T) {
+		in.Put(NewDocRequest("java-book", "java book"))
+		s := in.Suggest("jaxa", DefaultSuggestMaxEdits)
+		assert.Equal(t, "java", s.Tokens[0].Best(), "'java' is more frequent than 'lava'")
+		assert.Equal(t, "lava", s.Tokens[0].Candidates[1].Term)
+	})
+
+	t.Run("Known tokens are kept", func(t *testing.T) {
+		s := in.Suggest("programming jvaa", DefaultSuggestMaxEdits)
+		assert.Equal(t, "programming java", s.Query())
+		assert.Empty(t, s.Tokens[0].Candidates)
+	})
+
+	t.Run("Candidates are capped", func(t *testing.T) {
+		in := NewMemoryIndex("rhymes", pipeline)
+		in.Put(NewDocRequest("cats", "bat hat mat rat sat pat fat vat"))
+		s := in.Suggest("cat", DefaultSuggestMaxEdits)
+		assert.Len(t, s.Tokens[0].Candidates, maxSuggestCandidates)
+		assert.Equal(t, "bat", s.Tokens[0].Best())
+
+		other := NewMemoryIndex("more rhymes", pipeline)
+		other.Put(NewDocRequest("cats", "eat gat kat lat nat oat"))
+		merged := MergeSuggestions(s, other.Suggest("cat", DefaultSuggestMaxEdits))
+		assert.Len(t, merged.Tokens[0].Candidates, maxSuggestCandidates)
+	})
+
+	t.Run("Words are kept as typed", func(t *testing.T) {
+		s := in.Suggest("Programming,  jvaa!", DefaultSuggestMaxEdits)
+		assert.Equal(t, "Programming,  java!", s.Query())
+		assert.True(t, s.Changed())
+	})
+
+	t.Run("No candidate within distance", func(t *testing.T) {
+		s := in.Suggest("haskell", DefaultSuggestMaxEdits)
+		assert.Equal(t, "haskell", s.Query())
+		assert.False(t, s.Changed())
+	})
+}
+
+func Test_IndexRepo_Suggest_By_Alias(t *testing.T) {
+	repo := newTestIndexRepo()
+	repo.Put("dedos", NewDocRequest("pulgar", "este fue a por huevos"))
+	repo.Put("comida", NewDocRequest("huevos", "los huevos son cuerpos redondeados"))
+	repo.Alias("huevos:latest", "dedos")
+	repo.Alias("huevos:latest", "comida")
+
+	s, err := repo.Suggest("huevos:latest", "guevos redondeado")
+	assert.NoError(t, err)
+	assert.Equal(t, "huevos redondeados", s.Query())
+	assert.Equal(
+		t,
+		2,
+		s.Tokens[0].Candidates[0].DocFreq,
+		"frequencies should add up across indices",
+	)
+
+	_, err = repo.Suggest("sabores", "guevos")
+	assert.Error(t, err, "suggesting on a non-existent index should fail")
+}
+
+func TestMemoryIndex_Suggest_Words(t *testing.T) {
+	analyzer := NewTokenizationPipeline(
+		NewKeepAlphanumericTokenizer(),
+		NewLowerCaseTokenizer(),
+		NewStopWordsFilter(SpanishStopWords),
+		NewSpanishStemmer(true),
+	)
+	in := NewMemoryIndex("comida", analyzer)
+	in.Put(NewDocRequest("huevos", "Los Huevos son cuerpos redondeados"))
+
+	s := in.Suggest("guevos Redondeados", DefaultSuggestMaxEdits)
+	assert.Equal(t, "huev", s.Tokens[0].Candidates[0].Term)
+	assert.Equal(t, "huevos", s.Tokens[0].Best(), "corrections should be words, not stems")
+	assert.Equal(t, "huevos Redondeados", s.Query())
+}
+
+func Test_IndexRepo_Suggest_Merges_Before_Capping(t *testing.T) {
+	repo := newTestIndexRepo()
+	repo.Put("rhymes", NewDocRequest("cats", "bat hat mat pat rat sat"))
+	repo.Put("more rhymes", NewDocRequest("cats", "sat"))
+	repo.Alias("rhymes:latest", "rhymes")
+	repo.Alias("rhymes:latest", "more rhymes")
+
+	s, err := repo.Suggest("rhymes:latest", "cat")
+	assert.NoError(t, err)
+	assert.Len(t, s.Tokens[0].Candidates, maxSuggestCandidates)
+	assert.Equal(t, "sat", s.Tokens[0].Best(), "'sat' is the most frequent across indices")
+	assert.Equal(t, 2, s.Tokens[0].Candidates[0].DocFreq)
+}
@@ -0,0 +1,215 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Role is what an API key may do with some indices and aliases. Every role
+// includes the ones before it.
+type Role string
+
+const (
+	// RoleRead allows searching, suggesting and analyzing.
+	RoleRead Role = "read"
+	// RoleWrite allows indexing documents to existing indices and aliases,
+	// besides reading.
+	RoleWrite Role = "write"
+	// RoleAdmin allows creating, dropping, renaming and aliasing indices,
+	// also by indexing documents to missing ones, besides writing.
+	RoleAdmin Role = "admin"
+)
+
+var roleLevels = map[Role]int{RoleRead: 1, RoleWrite: 2, RoleAdmin: 3}
+
+// includes tells whether the role allows what other does.
+func (r Role) includes(other Role) bool {
+	return roleLevels[r] >= roleLevels[other]
+}
+
+// Grant gives a role on the indices and aliases whose names match any of
+// the patterns, in the syntax of path.Match, such as "logs-*".
+type Grant struct {
+	Role    Role     `json:"role"    yaml:"role"`
+	Indices []string `json:"indices" yaml:"indices"`
+}
+
+// APIKey is a key callers authenticate with, along with what it allows.
+type APIKey struct {
+	// Name identifies the key in errors and logs.
+	Name string `json:"name"                 yaml:"name"`
+	// Key is the secret, or empty when given as its SHA-256 digest.
+	Key string `json:"key,omitempty"        yaml:"key"`
+	// KeySHA256 is the hex encoded SHA-256 digest of the secret, so that it
+	// needs not be written down.
+	KeySHA256 string  `json:"key_sha256,omitempty" yaml:"key_sha256"`
+	Grants    []Grant `json:"grants"               yaml:"grants"`
+}
+
+// Allows tells whether the key grants the role on the index or alias.
+func (k *APIKey) Allows(role Role, name string) bool {
+	for _, grant := range k.Grants {
+		if !grant.Role.includes(role) {
+			continue
+		}
+		for _, pattern := range grant.Indices {
+			if ok, _ := path.Match(pattern, name); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// KeysConfig declares API keys:
+//
+//	keys:
+//	  - name: search-frontend
+//	    key: s3cr3t
+//	    grants:
+//	      - role: read
+//	        indices: ["products", "products-*"]
+//	  - name: ops
+//	    key_sha256: 4e07408562bedb8b60ce05c1decfe3ad16b72230967de01f640b7e4729b49fce
+//	    grants:
+//	      - role: admin
+//	        indices: ["*"]
+type KeysConfig struct {
+	Keys []APIKey `json:"keys" yaml:"keys"`
+}
+
+// KeyStore authenticates callers by their API key.
+type KeyStore struct {
+	keys map[[sha256.Size]byte]*APIKey
+}
+
+// Authenticate returns the key matching the secret, or false if none does.
+func (s *KeyStore) Authenticate(secret string) (*APIKey, bool) {
+	if secret == "" {
+		return nil, false
+	}
+	key, ok := s.keys[sha256.Sum256([]byte(secret))]
+	return key, ok
+}
+
+// authenticate returns the key of the credentials of a request, given in
+// an Authorization header with the Bearer, ApiKey or Basic schemes, with the
+// secret as the password of the latter, or in an X-API-Key header.
+func (s *KeyStore) authenticate(authorization, apiKey string) (*APIKey, error) {
+	secret := apiKey
+	if authorization != "" {
+		scheme, credentials, _ := strings.Cut(authorization, " ")
+		switch strings.ToLower(scheme) {
+		case "bearer", "apikey":
+			secret = credentials
+		case "basic":
+			decoded, err := base64.StdEncoding.DecodeString(credentials)
+			if err != nil {
+				return nil, unauthorized("malformed basic credentials")
+			}
+			_, secret, _ = strings.Cut(string(decoded), ":")
+		default:
+			return nil, unauthorized(fmt.Sprintf("unsupported authorization scheme '%s'", scheme))
+		}
+	}
+	if secret == "" {
+		return nil, unauthorized("missing API key")
+	}
+	key, ok := s.Authenticate(strings.TrimSpace(secret))
+	if !ok {
+		return nil, unauthorized("invalid API key")
+	}
+	return key, nil
+}
+
+// NewKeyStore validates the keys and returns a store of them.
+func NewKeyStore(keys []APIKey) (*KeyStore, error) {
+	s := &KeyStore{keys: make(map[[sha256.Size]byte]*APIKey, len(keys))}
+	for i := range keys {
+		key := &keys[i]
+		if key.Name == "" {
+			return nil, fmt.Errorf("key #%d with no name", i+1)
+		}
+		var digest [sha256.Size]byte
+		switch {
+		case key.Key != "" && key.KeySHA256 != "":
+			return nil, fmt.Errorf("key '%s' with both 'key' and 'key_sha256'", key.Name)
+		case key.Key != "":
+			digest = sha256.Sum256([]byte(key.Key))
+		case key.KeySHA256 != "":
+			decoded, err := hex.DecodeString(key.KeySHA256)
+			if err != nil || len(decoded) != sha256.Size {
+				return nil, fmt.Errorf("key '%s' with malformed 'key_sha256'", key.Name)
+			}
+			copy(digest[:], decoded)
+		default:
+			return nil, fmt.Errorf("key '%s' with no secret", key.Name)
+		}
+		if prev, ok := s.keys[digest]; ok {
+			return nil, fmt.Errorf("keys '%s' and '%s' share their secret", prev.Name, key.Name)
+		}
+		for _, grant := range key.Grants {
+			if _, ok := roleLevels[grant.Role]; !ok {
+				return nil, fmt.Errorf("key '%s' with unknown role '%s'", key.Name, grant.Role)
+			}
+			for _, pattern := range grant.Indices {
+				if _, err := path.Match(pattern, ""); err != nil {
+					return nil, fmt.Errorf(
+						"key '%s' with malformed pattern '%s'",
+						key.Name,
+						pattern,
+					)
+				}
+			}
+		}
+		s.keys[digest] = key
+	}
+	return s, nil
+}
+
+// LoadKeyStore reads the keys from a YAML file, if its extension is .yaml or
+// .yml, or from a JSON one otherwise.
+func LoadKeyStore(file string) (*KeyStore, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var config KeysConfig
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		err = dec.Decode(&config)
+	default:
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(&config)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parsing keys: %w", err)
+	}
+	return NewKeyStore(config.Keys)
+}
+
+type apiKeyContextKey struct{}
+
+func contextWithAPIKey(ctx context.Context, key *APIKey) context.Context {
+	return context.WithValue(ctx, apiKeyContextKey{}, key)
+}
+
+// apiKeyFromContext returns the key of the caller, if authenticated.
+func apiKeyFromContext(ctx context.Context) (*APIKey, bool) {
+	key, ok := ctx.Value(apiKeyContextKey{}).(*APIKey)
+	return key, ok
+}
@@ -0,0 +1,171 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/sonirico/vago/streams"
+	"github.com/sonirico/visigoth"
+)
+
+// authorizedRepo is the repo as seen by the caller with an API key, allowed
+// to do only what the key grants. Indices and aliases the key may not read
+// are hidden, and operations the key does not allow fail, or do nothing.
+//
+// The operations shared by every API check the key first, through
+// authorize, so as to fail with a forbidden error rather than with the
+// outcome of the operation.
+type authorizedRepo struct {
+	repo visigoth.Repo
+	key  *APIKey
+}
+
+// authorize returns a forbidden error unless the repo allows the role on
+// every name. Repos with no key allow anything.
+func authorize(repo visigoth.Repo, role Role, names ...string) error {
+	if a, ok := repo.(authorizedRepo); ok {
+		return a.authorize(role, names...)
+	}
+	return nil
+}
+
+func (a authorizedRepo) authorize(role Role, names ...string) error {
+	for _, name := range names {
+		if !a.key.Allows(role, name) {
+			return forbidden(
+				fmt.Sprintf("key '%s' is not allowed to %s '%s'", a.key.Name, role, name),
+			)
+		}
+	}
+	return nil
+}
+
+func (a authorizedRepo) allows(role Role, names ...string) bool {
+	return a.authorize(role, names...) == nil
+}
+
+func (a authorizedRepo) List() []string {
+	var indices []string
+	for _, index := range a.repo.List() {
+		if a.allows(RoleRead, index) {
+			indices = append(indices, index)
+		}
+	}
+	return indices
+}
+
+// ListAliases returns the aliases the key may read, with the indices it may
+// read too.
+func (a authorizedRepo) ListAliases() visigoth.AliasesResult {
+	var res visigoth.AliasesResult
+	for _, row := range a.repo.ListAliases().Aliases {
+		if !a.allows(RoleRead, row.Alias) {
+			continue
+		}
+		var indices []string
+		for _, index := range row.Indices {
+			if a.allows(RoleRead, index) {
+				indices = append(indices, index)
+			}
+		}
+		res.Aliases = append(res.Aliases, visigoth.AliasesResultRow{
+			Alias:   row.Alias,
+			Indices: indices,
+		})
+	}
+	return res
+}
+
+func (a authorizedRepo) Has(name string) bool {
+	return a.allows(RoleRead, name) && a.repo.Has(name)
+}
+
+func (a authorizedRepo) HasAlias(name string) bool {
+	return a.allows(RoleRead, name) && a.repo.HasAlias(name)
+}
+
+func (a authorizedRepo) Alias(alias string, in string) bool {
+	return a.allows(RoleAdmin, alias, in) && a.repo.Alias(alias, in)
+}
+
+func (a authorizedRepo) UnAlias(alias, index string) bool {
+	names := []string{alias}
+	if index != "" {
+		names = append(names, index)
+	}
+	return a.allows(RoleAdmin, names...) && a.repo.UnAlias(alias, index)
+}
+
+func (a authorizedRepo) SetAlias(alias, index string) error {
+	if err := a.authorize(RoleAdmin, alias, index); err != nil {
+		return err
+	}
+	return setAlias(a.repo, alias, index)
+}
+
+func (a authorizedRepo) Create(in string) bool {
+	return a.allows(RoleAdmin, in) && a.repo.Create(in)
+}
+
+func (a authorizedRepo) Put(in string, req visigoth.DocRequest) {
+	if authorizePut(a, in) == nil {
+		a.repo.Put(in, req)
+	}
+}
+
+func (a authorizedRepo) Insert(in string, req visigoth.DocRequest) error {
+	if err := authorizePut(a, in); err != nil {
+		return err
+	}
+	return insertDocument(a.repo, in, req)
+}
+
+func (a authorizedRepo) Search(
+	index string,
+	terms string,
+	engine visigoth.Engine,
+) (streams.ReadStream[visigoth.SearchResult], error) {
+	return a.SearchWith(index, terms, engine)
+}
+
+func (a authorizedRepo) SearchWith(
+	index string,
+	terms string,
+	engine visigoth.Engine,
+	opts ...visigoth.SearchOption,
+) (streams.ReadStream[visigoth.SearchResult], error) {
+	if err := a.authorize(RoleRead, index); err != nil {
+		return nil, err
+	}
+	return a.repo.SearchWith(index, terms, engine, opts...)
+}
+
+func (a authorizedRepo) Suggest(index string, terms string) (visigoth.Suggestion, error) {
+	if err := a.authorize(RoleRead, index); err != nil {
+		return visigoth.Suggestion{}, err
+	}
+	return a.repo.Suggest(index, terms)
+}
+
+// Analyze requires reading the index or alias with the name, if any, while
+// analyzers may be used by any key. Indices and aliases the key cannot read
+// are not found, as names of nothing are, so that the key cannot tell them
+// apart.
+func (a authorizedRepo) Analyze(name string, text string) (visigoth.Analysis, error) {
+	if !a.allows(RoleRead, name) && (a.repo.Has(name) || a.repo.HasAlias(name)) {
+		return visigoth.Analysis{}, visigoth.NotFoundError{Kind: "index or analyzer", Name: name}
+	}
+	return a.repo.Analyze(name, text)
+}
+
+func (a authorizedRepo) Rename(old string, new string) bool {
+	return a.allows(RoleAdmin, old, new) && a.repo.Rename(old, new)
+}
+
+func (a authorizedRepo) Drop(in string) bool {
+	return a.allows(RoleAdmin, in) && a.repo.Drop(in)
+}
+
+// AuthorizedRepo returns the repo as seen by the caller with the key.
+func AuthorizedRepo(repo visigoth.Repo, key *APIKey) visigoth.Repo {
+	return authorizedRepo{repo: repo, key: key}
+}
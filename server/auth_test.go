@@ -0,0 +1,188 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testKeys are the keys of the tests: reader reads the indices of dedos,
+// writer writes them, and admin does anything.
+func testKeys(t *testing.T) *KeyStore {
+	keys, err := NewKeyStore([]APIKey{
+		{Name: "reader", Key: "r34d", Grants: []Grant{
+			{Role: RoleRead, Indices: []string{"dedos", "dedos-*"}},
+		}},
+		{Name: "writer", Key: "wr1t3", Grants: []Grant{
+			{Role: RoleWrite, Indices: []string{"dedos"}},
+		}},
+		{
+			Name: "admin",
+			// sha256("4dm1n")
+			KeySHA256: "6a4e4054fa6e08046c40df5745530cdde6e4e091de831d04d7d902635b9350ac",
+			Grants:    []Grant{{Role: RoleAdmin, Indices: []string{"*"}}},
+		},
+	})
+	require.NoError(t, err)
+	return keys
+}
+
+func TestAPIKey_Allows(t *testing.T) {
+	key := APIKey{Grants: []Grant{
+		{Role: RoleRead, Indices: []string{"logs-*"}},
+		{Role: RoleWrite, Indices: []string{"logs-app"}},
+	}}
+
+	assert.True(t, key.Allows(RoleRead, "logs-db"))
+	assert.False(t, key.Allows(RoleWrite, "logs-db"))
+	assert.True(t, key.Allows(RoleRead, "logs-app"))
+	assert.True(t, key.Allows(RoleWrite, "logs-app"))
+	assert.False(t, key.Allows(RoleAdmin, "logs-app"))
+	assert.False(t, key.Allows(RoleRead, "metrics"))
+}
+
+func TestNewKeyStore(t *testing.T) {
+	tests := []struct {
+		name string
+		keys []APIKey
+		err  string
+	}{
+		{"no name", []APIKey{{Key: "k"}}, "key #1 with no name"},
+		{"no secret", []APIKey{{Name: "a"}}, "key 'a' with no secret"},
+		{"both secrets", []APIKey{{Name: "a", Key: "k", KeySHA256: "00"}},
+			"key 'a' with both 'key' and 'key_sha256'"},
+		{"malformed digest", []APIKey{{Name: "a", KeySHA256: "00"}},
+			"key 'a' with malformed 'key_sha256'"},
+		{"shared secret", []APIKey{{Name: "a", Key: "k"}, {Name: "b", Key: "k"}},
+			"keys 'a' and 'b' share their secret"},
+		{"unknown role", []APIKey{{Name: "a", Key: "k", Grants: []Grant{{Role: "root"}}}},
+			"key 'a' with unknown role 'root'"},
+		{"malformed pattern", []APIKey{{Name: "a", Key: "k", Grants: []Grant{
+			{Role: RoleRead, Indices: []string{"["}},
+		}}}, "key 'a' with malformed pattern '['"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewKeyStore(test.keys)
+			assert.EqualError(t, err, test.err)
+		})
+	}
+
+	keys := testKeys(t)
+	key, ok := keys.Authenticate("r34d")
+	require.True(t, ok)
+	assert.Equal(t, "reader", key.Name)
+	key, ok = keys.Authenticate("4dm1n")
+	require.True(t, ok)
+	assert.Equal(t, "admin", key.Name)
+	_, ok = keys.Authenticate("")
+	assert.False(t, ok)
+}
+
+func TestLoadKeyStore(t *testing.T) {
+	dir := t.TempDir()
+	yamlFile := filepath.Join(dir, "keys.yaml")
+	require.NoError(t, os.WriteFile(yamlFile, []byte(`
+keys:
+  - name: search
+    key: s3cr3t
+    grants:
+      - role: read
+        indices: ["products"]
+`), 0o600))
+	keys, err := LoadKeyStore(yamlFile)
+	require.NoError(t, err)
+	key, ok := keys.Authenticate("s3cr3t")
+	require.True(t, ok)
+	assert.Equal(t, []Grant{{Role: RoleRead, Indices: []string{"products"}}}, key.Grants)
+
+	jsonFile := filepath.Join(dir, "keys.json")
+	require.NoError(t, os.WriteFile(jsonFile,
+		[]byte(`{"keys": [{"name": "search", "secret": "s3cr3t"}]}`), 0o600))
+	_, err = LoadKeyStore(jsonFile)
+	assert.ErrorContains(t, err, `unknown field "secret"`)
+}
+
+func TestHTTPHandler_Auth(t *testing.T) {
+	h := newTestHandler(WithKeyStore(testKeys(t)))
+
+	send := func(method, target, body string, header ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		for i := 0; i < len(header); i += 2 {
+			req.Header.Set(header[i], header[i+1])
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+	const (
+		reader = "Bearer r34d"
+		writer = "ApiKey wr1t3"
+		admin  = "Bearer 4dm1n"
+	)
+
+	assert.Equal(t, http.StatusOK, send("GET", "/health", "").Code)
+	rec := send("GET", "/indices", "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, `Bearer realm="visigoth"`, rec.Header().Get("WWW-Authenticate"))
+	assert.Equal(t, http.StatusUnauthorized,
+		send("GET", "/indices", "", "Authorization", "Bearer nope").Code)
+	assert.Equal(t, http.StatusUnauthorized,
+		send("GET", "/indices", "", "Authorization", "Digest r34d").Code)
+
+	assert.Equal(t, http.StatusForbidden, send("POST", "/indices/dedos/docs",
+		`{"id": "pulgar", "content": "huevos"}`, "Authorization", writer).Code,
+		"writer should not create indices by putting documents")
+	assert.Equal(t, http.StatusForbidden, send("POST", "/indices/dedos/_bulk",
+		`{"id": "pulgar", "content": "huevos"}`, "Authorization", writer).Code)
+	for _, name := range []string{"dedos", "manos"} {
+		require.Equal(t, http.StatusCreated,
+			send("PUT", "/indices/"+name, "", "Authorization", admin).Code)
+	}
+	assert.Equal(t, http.StatusForbidden,
+		send("PUT", "/indices/dedos-2", "", "Authorization", writer).Code)
+	assert.Equal(t, http.StatusForbidden, send("POST", "/indices/manos/docs",
+		`{"id": "palma", "content": "huevos"}`, "Authorization", writer).Code)
+	assert.Equal(t, http.StatusCreated, send("POST", "/indices/dedos/docs",
+		`{"id": "pulgar", "content": "huevos"}`, "Authorization", writer).Code)
+	assert.Equal(t, http.StatusForbidden, send("POST", "/indices/manos/_bulk",
+		`{"id": "palma", "content": "huevos"}`, "Authorization", writer).Code)
+	assert.Equal(t, http.StatusForbidden, send("POST", "/indices/dedos/docs",
+		`{"id": "indice", "content": "huevos"}`, "Authorization", reader).Code)
+
+	rec = send("GET", "/indices", "", "X-API-Key", "r34d")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"indices": ["dedos"]}`, rec.Body.String())
+	// Basic credentials of user:r34d
+	rec = send("GET", "/indices/dedos/_search?q=huevos", "", "Authorization", "Basic dXNlcjpyMzRk")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"total":1`)
+	assert.Equal(t, http.StatusForbidden,
+		send("GET", "/indices/manos/_search?q=huevos", "", "Authorization", reader).Code)
+	assert.Equal(t, http.StatusOK,
+		send("GET", "/indices/dedos/_analyze?text=huevos", "", "Authorization", reader).Code)
+	// Indices the key cannot read cannot be told from those not existing
+	hidden := send("GET", "/indices/manos/_analyze?text=huevos", "", "Authorization", reader)
+	missing := send("GET", "/indices/pies/_analyze?text=huevos", "", "Authorization", reader)
+	assert.Equal(t, http.StatusNotFound, hidden.Code)
+	assert.Equal(t, http.StatusNotFound, missing.Code)
+	assert.Equal(t, strings.ReplaceAll(missing.Body.String(), "pies", "manos"), hidden.Body.String())
+
+	assert.Equal(t, http.StatusOK,
+		send("PUT", "/aliases/mano/manos", "", "Authorization", admin).Code)
+	rec = send("GET", "/aliases", "", "Authorization", reader)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"aliases": []}`, rec.Body.String())
+	assert.Equal(t, http.StatusForbidden,
+		send("POST", "/indices/manos/_rename", `{"name": "dedos-3"}`, "Authorization", writer).Code)
+	assert.Equal(t, http.StatusForbidden,
+		send("DELETE", "/indices/dedos", "", "Authorization", writer).Code)
+	assert.Equal(t, http.StatusOK,
+		send("DELETE", "/indices/dedos", "", "Authorization", admin).Code)
+}
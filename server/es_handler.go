@@ -0,0 +1,594 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/sonirico/visigoth"
+)
+
+// ESCompatibleVersion is the Elasticsearch version reported by ESHandler.
+const ESCompatibleVersion = "8.11.0"
+
+// defaultESSize is the number of hits of searches not setting a size.
+const defaultESSize = 10
+
+// codeESVersionConflict is the code of the errors of putting documents with
+// the ID of another, which Elasticsearch reports apart from other conflicts.
+const codeESVersionConflict = "version_conflict"
+
+// esErrorTypes are the Elasticsearch error types of API error codes.
+var esErrorTypes = map[string]string{
+	CodeBadRequest:        "illegal_argument_exception",
+	CodeNotFound:          "resource_not_found_exception",
+	CodeMethodNotAllowed:  "illegal_argument_exception",
+	CodeConflict:          "resource_already_exists_exception",
+	codeESVersionConflict: "version_conflict_engine_exception",
+	CodeUnauthorized:      "security_exception",
+	CodeForbidden:         "security_exception",
+	CodeTooLarge:          "content_too_long_exception",
+	CodeInternalError:     "exception",
+}
+
+// ESHandler serves a visigoth.Repo with a subset of the Elasticsearch REST
+// API, so that clients written for Elasticsearch can use it:
+//
+//	GET    /
+//	GET    /{index}                 also HEAD, to check it exists
+//	PUT    /{index}
+//	DELETE /{index}
+//	PUT    /{index}/_doc/{id}       any JSON object, also with POST
+//	POST   /_bulk                   index and create actions, also /{index}/_bulk
+//	POST   /{index}/_search         match_all, match, term and bool queries, also GET
+//	GET    /_aliases
+//	POST   /_aliases                add and remove actions
+//	GET    /_cat/indices            ?v&format=json
+//
+// Documents are indexed as JSON, analyzing the values of all their fields
+// together, and returned as the _source of hits. The _score of hits is their
+// number of hits. Documents are never replaced: putting one with the ID of
+// another fails with a version conflict, as creating it does in
+// Elasticsearch. See esQueryEval for how queries are evaluated. Errors are
+// returned in the format of Elasticsearch, with a type derived from their
+// code.
+type ESHandler struct {
+	*HTTPHandler
+}
+
+func (h *ESHandler) routes() {
+	h.handle("GET /{$}", h.info)
+	h.handle("GET /{index}", h.getIndex)
+	h.handle("PUT /{index}", h.createIndex)
+	h.handle("DELETE /{index}", h.dropIndex)
+	h.handle("PUT /{index}/_doc/{id}", h.putDocument)
+	h.handle("POST /{index}/_doc/{id}", h.putDocument)
+	h.handle("POST /_bulk", h.bulk)
+	h.handle("PUT /_bulk", h.bulk)
+	h.handle("POST /{index}/_bulk", h.bulk)
+	h.handle("PUT /{index}/_bulk", h.bulk)
+	h.handle("GET /{index}/_search", h.search)
+	h.handle("POST /{index}/_search", h.search)
+	h.handle("GET /_aliases", h.listAliases)
+	h.handle("POST /_aliases", h.updateAliases)
+	h.handle("GET /_cat/indices", h.catIndices)
+}
+
+// esNoRouteError returns the error of a request matching no route, as
+// Elasticsearch words it.
+func esNoRouteError(r *http.Request, allowed string) *APIError {
+	if allowed != "" {
+		return newAPIError(
+			http.StatusMethodNotAllowed,
+			CodeMethodNotAllowed,
+			fmt.Sprintf("Incorrect HTTP method for uri [%s] and method [%s], allowed: [%s]",
+				r.URL.Path, r.Method, allowed),
+		)
+	}
+	return newAPIError(
+		http.StatusNotFound,
+		CodeNotFound,
+		fmt.Sprintf("no handler found for uri [%s] and method [%s]", r.URL.Path, r.Method),
+	)
+}
+
+// ServeHTTP tells clients checking it that they talk to Elasticsearch.
+func (h *ESHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("X-Elastic-Product", "Elasticsearch")
+	h.HTTPHandler.ServeHTTP(w, r)
+}
+
+func (h *ESHandler) info(w http.ResponseWriter, _ *http.Request) error {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"name":         "visigoth",
+		"cluster_name": "visigoth",
+		"version": map[string]string{
+			"number":       ESCompatibleVersion,
+			"build_flavor": "default",
+		},
+		"tagline": "You Know, for Search",
+	})
+	return nil
+}
+
+// getIndex describes the index, with its aliases and no mappings nor settings.
+func (h *ESHandler) getIndex(w http.ResponseWriter, r *http.Request) error {
+	index := r.PathValue("index")
+	repo := h.repoFor(r)
+	if !repo.Has(index) {
+		return notFound("index", index)
+	}
+	aliases := map[string]struct{}{}
+	for _, alias := range listAliases(repo) {
+		for _, aliased := range alias.Indices {
+			if aliased == index {
+				aliases[alias.Alias] = struct{}{}
+			}
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		index: map[string]any{
+			"aliases":  aliases,
+			"mappings": struct{}{},
+			"settings": struct{}{},
+		},
+	})
+	return nil
+}
+
+func (h *ESHandler) createIndex(w http.ResponseWriter, r *http.Request) error {
+	index := r.PathValue("index")
+	if err := createIndex(h.repoFor(r), index); err != nil {
+		return err
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"acknowledged":        true,
+		"shards_acknowledged": true,
+		"index":               index,
+	})
+	return nil
+}
+
+func (h *ESHandler) dropIndex(w http.ResponseWriter, r *http.Request) error {
+	if err := dropIndex(h.repoFor(r), r.PathValue("index")); err != nil {
+		return err
+	}
+	writeJSON(w, http.StatusOK, AcknowledgedResponse{Acknowledged: true})
+	return nil
+}
+
+// esDocResult is the outcome of indexing a document, alone or in bulk.
+type esDocResult struct {
+	Index   string       `json:"_index"`
+	ID      string       `json:"_id"`
+	Version int          `json:"_version,omitempty"`
+	Result  string       `json:"result,omitempty"`
+	Status  int          `json:"status,omitempty"`
+	Error   *esErrorBody `json:"error,omitempty"`
+}
+
+func (h *ESHandler) putDocument(w http.ResponseWriter, r *http.Request) error {
+	source, err := io.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	index, id := r.PathValue("index"), r.PathValue("id")
+	req, err := esDocRequest(id, source)
+	if err != nil {
+		return err
+	}
+	if err := esPutDocument(h.repoFor(r), index, req); err != nil {
+		return err
+	}
+	writeJSON(w, http.StatusCreated, esDocResult{
+		Index:   index,
+		ID:      id,
+		Version: 1,
+		Result:  "created",
+	})
+	return nil
+}
+
+// esBulkAction is the action and metadata line of a bulk request.
+type esBulkAction struct {
+	Index string `json:"_index"`
+	ID    string `json:"_id"`
+}
+
+// bulk runs the index and create actions of a bulk request, each followed by
+// its document in the next line. Other actions fail, as do actions on
+// documents with no ID, without preventing the others from running.
+func (h *ESHandler) bulk(w http.ResponseWriter, r *http.Request) error {
+	start := time.Now()
+	scanner := bufio.NewScanner(r.Body)
+	scanner.Buffer(make([]byte, 0, 64<<10), maxBulkLineBytes)
+	nextLine := func() ([]byte, bool) {
+		for scanner.Scan() {
+			if line := bytes.TrimSpace(scanner.Bytes()); len(line) > 0 {
+				return line, true
+			}
+		}
+		return nil, false
+	}
+
+	var (
+		items  []map[string]esDocResult
+		failed bool
+	)
+	for {
+		line, ok := nextLine()
+		if !ok {
+			break
+		}
+		name, meta, err := esSingleKey(line, "bulk action")
+		if err != nil {
+			return err
+		}
+		var action esBulkAction
+		if err := json.Unmarshal(meta, &action); err != nil {
+			return badRequest(fmt.Errorf("malformed %s action: %w", name, err))
+		}
+		if action.Index == "" {
+			action.Index = r.PathValue("index")
+		}
+
+		result := esDocResult{Index: action.Index, ID: action.ID}
+		switch name {
+		case "index", "create":
+			source, ok := nextLine()
+			if !ok {
+				return badRequest(fmt.Errorf("%s action with no document", name))
+			}
+			err = esBulkPut(h.repoFor(r), action, source)
+		case "update":
+			// Skip the partial document
+			if _, ok := nextLine(); !ok {
+				return badRequest(errors.New("update action with no document"))
+			}
+			fallthrough
+		case "delete":
+			err = badRequest(fmt.Errorf("unsupported bulk action [%s]", name))
+		default:
+			return badRequest(fmt.Errorf("unknown bulk action [%s]", name))
+		}
+
+		if err != nil {
+			apiErr := toAPIError(err)
+			result.Status = apiErr.Status
+			result.Error = esError(apiErr)
+			failed = true
+		} else {
+			result.Version = 1
+			result.Result = "created"
+			result.Status = http.StatusCreated
+		}
+		items = append(items, map[string]esDocResult{name: result})
+	}
+	if err := scanner.Err(); err != nil {
+		return badRequest(err)
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"took":   time.Since(start).Milliseconds(),
+		"errors": failed,
+		"items":  items,
+	})
+	return nil
+}
+
+func esBulkPut(repo visigoth.Repo, action esBulkAction, source []byte) error {
+	if action.Index == "" {
+		return badRequest(errors.New("document with no _index"))
+	}
+	req, err := esDocRequest(action.ID, source)
+	if err != nil {
+		return err
+	}
+	return esPutDocument(repo, action.Index, req)
+}
+
+// esPutDocument puts the document unless another with the same ID exists, in
+// which case it fails with a version conflict.
+func esPutDocument(repo visigoth.Repo, index string, req visigoth.DocRequest) error {
+	err := insertDocument(repo, index, req)
+	if errors.As(err, new(visigoth.ExistsError)) {
+		return newAPIError(http.StatusConflict, codeESVersionConflict,
+			fmt.Sprintf("[%s]: version conflict, document already exists", req.ID()))
+	}
+	return err
+}
+
+// esSearchRequest is the body of a search request.
+type esSearchRequest struct {
+	Query json.RawMessage `json:"query"`
+	From  *int            `json:"from"`
+	Size  *int            `json:"size"`
+}
+
+type esHit struct {
+	Index  string          `json:"_index"`
+	ID     string          `json:"_id"`
+	Score  float64         `json:"_score"`
+	Source json.RawMessage `json:"_source"`
+}
+
+// search runs the query of the body, or a match query of the q parameter,
+// matching every document with neither. The from and size parameters
+// override those of the body.
+func (h *ESHandler) search(w http.ResponseWriter, r *http.Request) error {
+	start := time.Now()
+	index := r.PathValue("index")
+	var req esSearchRequest
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	if len(bytes.TrimSpace(body)) > 0 {
+		if err := decodeJSON(bytes.NewReader(body), &req); err != nil {
+			return err
+		}
+	}
+
+	query := r.URL.Query()
+	from, size := 0, defaultESSize
+	if req.From != nil {
+		from = *req.From
+	}
+	if req.Size != nil {
+		size = *req.Size
+	}
+	if from, err = intParam(query.Get("from"), "from", from); err != nil {
+		return err
+	}
+	if size, err = intParam(query.Get("size"), "size", size); err != nil {
+		return err
+	}
+	if from < 0 || size < 0 {
+		return badRequest(errors.New("from and size should be non-negative"))
+	}
+	switch q := query.Get("q"); {
+	case q != "":
+		req.Query, _ = json.Marshal(map[string]any{"match": map[string]string{"_all": q}})
+	case len(req.Query) == 0:
+		req.Query = json.RawMessage(`{"match_all":{}}`)
+	}
+
+	matches, err := esQueryEval{repo: h.repoFor(r), index: index}.eval(req.Query)
+	if err != nil {
+		return err
+	}
+	results := matches.sorted()
+	total := len(results)
+	results = results[min(from, total):]
+	results = results[:min(size, len(results))]
+
+	hits := make([]esHit, len(results))
+	maxScore := 0.0
+	for i, result := range results {
+		hits[i] = esHit{
+			Index:  index,
+			ID:     result.Document.ID(),
+			Score:  float64(result.Hits),
+			Source: esSource(result.Document.Raw()),
+		}
+		maxScore = max(maxScore, hits[i].Score)
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"took":      time.Since(start).Milliseconds(),
+		"timed_out": false,
+		"_shards":   map[string]int{"total": 1, "successful": 1, "skipped": 0, "failed": 0},
+		"hits": map[string]any{
+			"total":     map[string]any{"value": total, "relation": "eq"},
+			"max_score": maxScore,
+			"hits":      hits,
+		},
+	})
+	return nil
+}
+
+// listAliases returns every index with its aliases.
+func (h *ESHandler) listAliases(w http.ResponseWriter, r *http.Request) error {
+	repo := h.repoFor(r)
+	res := map[string]map[string]map[string]struct{}{}
+	for _, index := range listIndices(repo) {
+		res[index] = map[string]map[string]struct{}{"aliases": {}}
+	}
+	for _, alias := range listAliases(repo) {
+		for _, index := range alias.Indices {
+			if aliases, ok := res[index]; ok {
+				aliases["aliases"][alias.Alias] = struct{}{}
+			}
+		}
+	}
+	writeJSON(w, http.StatusOK, res)
+	return nil
+}
+
+// esAliasAction is an add or remove action of an aliases request, naming
+// either one or several indices and aliases.
+type esAliasAction struct {
+	Index   string   `json:"index"`
+	Indices []string `json:"indices"`
+	Alias   string   `json:"alias"`
+	Aliases []string `json:"aliases"`
+}
+
+// updateAliases runs the actions of the request in order. Unlike with
+// Elasticsearch, actions run before a failing one are not undone.
+func (h *ESHandler) updateAliases(w http.ResponseWriter, r *http.Request) error {
+	var req struct {
+		Actions []map[string]esAliasAction `json:"actions"`
+	}
+	if err := decodeJSON(r.Body, &req); err != nil {
+		return err
+	}
+	repo := h.repoFor(r)
+	for _, actions := range req.Actions {
+		for name, action := range actions {
+			indices := append(action.Indices, action.Index)
+			aliases := append(action.Aliases, action.Alias)
+			var run func(alias, index string) error
+			switch name {
+			case "add":
+				run = func(alias, index string) error { return addAlias(repo, alias, index) }
+			case "remove":
+				run = func(alias, index string) error { return removeAlias(repo, alias, index) }
+			default:
+				return badRequest(fmt.Errorf("unsupported alias action [%s]", name))
+			}
+			for _, index := range indices {
+				for _, alias := range aliases {
+					if index == "" || alias == "" {
+						continue
+					}
+					if err := run(alias, index); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+	writeJSON(w, http.StatusOK, AcknowledgedResponse{Acknowledged: true})
+	return nil
+}
+
+type esCatIndex struct {
+	Health    string `json:"health"`
+	Status    string `json:"status"`
+	Index     string `json:"index"`
+	Pri       string `json:"pri"`
+	Rep       string `json:"rep"`
+	DocsCount string `json:"docs.count"`
+}
+
+// catIndices lists the indices with their number of documents, as a table
+// with a header row for the v parameter, or as JSON for format=json.
+func (h *ESHandler) catIndices(w http.ResponseWriter, r *http.Request) error {
+	var rows []esCatIndex
+	repo := h.repoFor(r)
+	for _, index := range listIndices(repo) {
+		total, _, err := searchPage(repo, searchParams{index: index, engine: "noop_all"})
+		if err != nil {
+			return err
+		}
+		rows = append(rows, esCatIndex{
+			Health:    "green",
+			Status:    "open",
+			Index:     index,
+			Pri:       "1",
+			Rep:       "0",
+			DocsCount: fmt.Sprint(total),
+		})
+	}
+
+	query := r.URL.Query()
+	if query.Get("format") == "json" {
+		writeJSON(w, http.StatusOK, append([]esCatIndex{}, rows...))
+		return nil
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=UTF-8")
+	tw := tabwriter.NewWriter(w, 0, 4, 1, ' ', 0)
+	if query.Has("v") {
+		fmt.Fprintln(tw, "health\tstatus\tindex\tpri\trep\tdocs.count")
+	}
+	for _, row := range rows {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			row.Health, row.Status, row.Index, row.Pri, row.Rep, row.DocsCount)
+	}
+	return tw.Flush()
+}
+
+// esDocRequest indexes the source, which must be a JSON object, as a JSON
+// document analyzing the values of its fields.
+func esDocRequest(id string, source []byte) (visigoth.DocRequest, error) {
+	if id == "" {
+		return visigoth.DocRequest{}, badRequest(errors.New("document with no _id"))
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(source, &fields); err != nil {
+		return visigoth.DocRequest{}, badRequest(fmt.Errorf("malformed document: %w", err))
+	}
+	var values []string
+	esValues(fields, &values)
+
+	req := visigoth.NewDocRequestWith(id, string(bytes.TrimSpace(source)),
+		strings.Join(values, "\n"))
+	req.MimeType = visigoth.MimeJSON
+	return req, nil
+}
+
+// esValues appends the values within v, in the order of their keys.
+func esValues(v any, values *[]string) {
+	switch v := v.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			esValues(v[key], values)
+		}
+	case []any:
+		for _, item := range v {
+			esValues(item, values)
+		}
+	case nil:
+	default:
+		*values = append(*values, fmt.Sprint(v))
+	}
+}
+
+// esSource returns the raw content of a document as its _source, wrapping
+// that of documents not indexed as JSON objects.
+func esSource(raw string) json.RawMessage {
+	trimmed := strings.TrimSpace(raw)
+	if strings.HasPrefix(trimmed, "{") && json.Valid([]byte(trimmed)) {
+		return json.RawMessage(trimmed)
+	}
+	source, _ := json.Marshal(map[string]string{"content": raw})
+	return source
+}
+
+type esErrorBody struct {
+	RootCause []esErrorBody `json:"root_cause,omitempty"`
+	Type      string        `json:"type"`
+	Reason    string        `json:"reason"`
+}
+
+func esError(err *APIError) *esErrorBody {
+	typ, ok := esErrorTypes[err.Code]
+	if !ok {
+		typ = esErrorTypes[CodeInternalError]
+	}
+	return &esErrorBody{Type: typ, Reason: err.Message}
+}
+
+// writeESError writes the error as Elasticsearch does, which reports
+// conflicts as bad requests.
+func writeESError(w http.ResponseWriter, err *APIError) {
+	status := err.Status
+	if err.Code == CodeConflict {
+		status = http.StatusBadRequest
+	}
+	body := esError(err)
+	body.RootCause = []esErrorBody{{Type: body.Type, Reason: body.Reason}}
+	writeJSON(w, status, map[string]any{"error": body, "status": status})
+}
+
+// NewESHandler returns a handler serving the repo with a subset of the
+// Elasticsearch REST API. It accepts the options of HTTPHandler.
+func NewESHandler(repo visigoth.Repo, opts ...HTTPHandlerOption) *ESHandler {
+	h := &ESHandler{HTTPHandler: newHTTPHandler(repo, opts)}
+	h.writeError = writeESError
+	h.noRoute = esNoRouteError
+	h.routes()
+	return h
+}
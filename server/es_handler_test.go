@@ -0,0 +1,188 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sonirico/visigoth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestESHandler(t *testing.T) *ESHandler {
+	repo := visigoth.NewIndexRepo(visigoth.NewMemoryIndexBuilder(
+		visigoth.NewTokenizationPipeline(
+			visigoth.NewKeepAlphanumericTokenizer(),
+			visigoth.NewLowerCaseTokenizer(),
+		),
+	))
+	h := NewESHandler(repo)
+
+	require.Equal(t, http.StatusOK, do(t, h, "PUT", "/dedos", "", nil))
+	body := `{"index": {"_id": "pulgar"}}
+{"title": "pulgar", "body": "este fue a por huevos"}
+{"create": {"_index": "dedos", "_id": "indice"}}
+{"title": "indice", "body": "este los puso a cocer"}
+
+{"index": {"_id": "corazon"}}
+{"title": "corazon", "body": "este les echo la sal", "tags": ["sal", "cocina"]}
+`
+	var res struct {
+		Errors bool `json:"errors"`
+		Items  []map[string]esDocResult
+	}
+	require.Equal(t, http.StatusOK, do(t, h, "POST", "/dedos/_bulk", body, &res))
+	require.False(t, res.Errors)
+	require.Len(t, res.Items, 3)
+	assert.Equal(t, http.StatusCreated, res.Items[1]["create"].Status)
+	return h
+}
+
+type esSearchResponse struct {
+	Hits struct {
+		Total struct {
+			Value int `json:"value"`
+		} `json:"total"`
+		Hits []struct {
+			ID     string         `json:"_id"`
+			Score  float64        `json:"_score"`
+			Source map[string]any `json:"_source"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+func (r esSearchResponse) ids() []string {
+	ids := make([]string, len(r.Hits.Hits))
+	for i, hit := range r.Hits.Hits {
+		ids[i] = hit.ID
+	}
+	return ids
+}
+
+func TestESHandler_Search(t *testing.T) {
+	h := newTestESHandler(t)
+
+	tests := []struct {
+		name  string
+		query string
+		total int
+		ids   []string
+	}{
+		{"match_all", `{}`, 3, []string{"corazon", "indice", "pulgar"}},
+		{"match any word", `{"query": {"match": {"body": "huevos cocer"}}}`,
+			2, []string{"indice", "pulgar"}},
+		{"match all words",
+			`{"query": {"match": {"body": {"query": "este huevos", "operator": "AND"}}}}`,
+			1, []string{"pulgar"}},
+		{"term", `{"query": {"term": {"tags": {"value": "cocina"}}}}`, 1, []string{"corazon"}},
+		{"bool", `{"query": {"bool": {
+			"must": {"match": {"body": "este"}},
+			"should": [{"term": {"title": "indice"}}],
+			"must_not": [{"match": {"body": "sal"}}]
+		}}}`, 2, []string{"indice", "pulgar"}},
+		{"bool should", `{"query": {"bool": {"should": [
+			{"term": {"title": "indice"}}, {"term": {"title": "corazon"}}
+		]}}}`, 2, []string{"corazon", "indice"}},
+		{"paging", `{"query": {"match": {"body": "este"}}, "from": 1, "size": 1}`,
+			3, []string{"indice"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var res esSearchResponse
+			require.Equal(t, http.StatusOK,
+				do(t, h, "POST", "/dedos/_search", test.query, &res))
+			assert.Equal(t, test.total, res.Hits.Total.Value)
+			assert.Equal(t, test.ids, res.ids())
+		})
+	}
+
+	var res esSearchResponse
+	require.Equal(t, http.StatusOK, do(t, h, "GET", "/dedos/_search?q=huevos", "", &res))
+	require.Equal(t, []string{"pulgar"}, res.ids())
+	assert.Equal(t,
+		map[string]any{"title": "pulgar", "body": "este fue a por huevos"},
+		res.Hits.Hits[0].Source)
+	assert.Equal(t, 1.0, res.Hits.Hits[0].Score)
+
+	var errRes struct {
+		Error  esErrorBody `json:"error"`
+		Status int         `json:"status"`
+	}
+	assert.Equal(t, http.StatusBadRequest, do(t, h, "POST", "/dedos/_search",
+		`{"query": {"fuzzy": {"body": "huevo"}}}`, &errRes))
+	assert.Equal(t, "illegal_argument_exception", errRes.Error.Type)
+	assert.Equal(t, "unsupported query [fuzzy]", errRes.Error.Reason)
+	assert.Equal(t, http.StatusNotFound,
+		do(t, h, "POST", "/meñique/_search", `{}`, &errRes))
+}
+
+func TestESHandler_Indices(t *testing.T) {
+	h := newTestESHandler(t)
+
+	var errRes struct {
+		Error esErrorBody `json:"error"`
+	}
+	assert.Equal(t, http.StatusBadRequest, do(t, h, "PUT", "/dedos", "", &errRes))
+	assert.Equal(t, "resource_already_exists_exception", errRes.Error.Type)
+
+	var doc esDocResult
+	assert.Equal(t, http.StatusCreated, do(t, h, "PUT", "/dedos/_doc/menique",
+		`{"body": "y este pícaro gordo se los comió"}`, &doc))
+	assert.Equal(t, esDocResult{Index: "dedos", ID: "menique", Version: 1, Result: "created"}, doc)
+	assert.Equal(t, http.StatusBadRequest,
+		do(t, h, "PUT", "/dedos/_doc/menique", `"no object"`, nil))
+	errRes.Error = esErrorBody{}
+	assert.Equal(t, http.StatusConflict, do(t, h, "PUT", "/dedos/_doc/menique",
+		`{"body": "y este se los zampo"}`, &errRes))
+	assert.Equal(t, "version_conflict_engine_exception", errRes.Error.Type)
+	var bulk struct {
+		Errors bool `json:"errors"`
+		Items  []map[string]esDocResult
+	}
+	assert.Equal(t, http.StatusOK, do(t, h, "POST", "/_bulk",
+		`{"index": {"_index": "dedos", "_id": "pulgar"}}`+"\n"+`{"body": "otra vez"}`+"\n", &bulk))
+	assert.True(t, bulk.Errors)
+	item := bulk.Items[0]["index"]
+	assert.Equal(t, http.StatusConflict, item.Status)
+	assert.Empty(t, item.Result)
+	assert.Equal(t, "version_conflict_engine_exception", item.Error.Type)
+
+	assert.Equal(t, http.StatusOK, do(t, h, "POST", "/_aliases", `{"actions": [
+		{"add": {"index": "dedos", "aliases": ["mano", "manos"]}},
+		{"remove": {"index": "dedos", "alias": "mano"}}
+	]}`, nil))
+	var aliases map[string]map[string]map[string]any
+	assert.Equal(t, http.StatusOK, do(t, h, "GET", "/_aliases", "", &aliases))
+	assert.Equal(t,
+		map[string]map[string]map[string]any{"dedos": {"aliases": {"manos": map[string]any{}}}},
+		aliases)
+
+	var res esSearchResponse
+	require.Equal(t, http.StatusOK, do(t, h, "GET", "/manos/_search?size=1", "", &res))
+	assert.Equal(t, 4, res.Hits.Total.Value)
+
+	req := httptest.NewRequest("GET", "/_cat/indices?v", nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, "health status index pri rep docs.count\n"+
+		"green  open   dedos 1   0   4\n", rec.Body.String())
+	assert.Equal(t, "Elasticsearch", rec.Header().Get("X-Elastic-Product"))
+
+	errRes.Error = esErrorBody{}
+	assert.Equal(t, http.StatusNotFound, do(t, h, "GET", "/dedos/_nowhere", "", &errRes))
+	assert.Equal(t, "no handler found for uri [/dedos/_nowhere] and method [GET]",
+		errRes.Error.Reason)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("PUT", "/_cat/indices", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	assert.Equal(t, "GET, HEAD", rec.Header().Get("Allow"))
+	assert.Contains(t, rec.Body.String(),
+		"Incorrect HTTP method for uri [/_cat/indices] and method [PUT], allowed: [GET, HEAD]")
+
+	assert.Equal(t, http.StatusOK, do(t, h, "DELETE", "/dedos", "", nil))
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("HEAD", "/dedos", strings.NewReader("")))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,276 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sonirico/visigoth"
+)
+
+// esMatches are the documents matching a query by ID, with their score as
+// hits.
+type esMatches map[string]visigoth.SearchResult
+
+// esQueryEval evaluates queries of the Elasticsearch query DSL against an
+// index of the repo:
+//
+//	{"match_all": {}}
+//	{"match": {"field": "text"}}
+//	{"match": {"field": {"query": "text", "operator": "and"}}}
+//	{"term": {"field": "value"}}
+//	{"term": {"field": {"value": "value"}}}
+//	{"bool": {"must": [...], "filter": [...], "should": [...], "must_not": [...]}}
+//
+// Documents are not split into fields, so fields are ignored and every query
+// matches the whole document. A match query matches documents with any of
+// its words, or with all of them for the "and" operator, while a term query
+// matches documents with all the terms its value analyzes to. A bool query
+// with no must or filter clauses matches documents matching any should
+// clause, which otherwise only add to the score.
+type esQueryEval struct {
+	repo  visigoth.Repo
+	index string
+}
+
+func (e esQueryEval) eval(query json.RawMessage) (esMatches, error) {
+	kind, body, err := esSingleKey(query, "query")
+	if err != nil {
+		return nil, err
+	}
+	switch kind {
+	case "match_all":
+		return e.search("", "noop_all")
+	case "match":
+		return e.match(body)
+	case "term":
+		return e.term(body)
+	case "bool":
+		return e.bool(body)
+	default:
+		return nil, badRequest(fmt.Errorf("unsupported query [%s]", kind))
+	}
+}
+
+func (e esQueryEval) match(body json.RawMessage) (esMatches, error) {
+	_, params, err := esSingleKey(body, "match")
+	if err != nil {
+		return nil, err
+	}
+	var match struct {
+		Query    string `json:"query"`
+		Operator string `json:"operator"`
+	}
+	if err := json.Unmarshal(params, &match.Query); err != nil {
+		if err := json.Unmarshal(params, &match); err != nil {
+			return nil, badRequest(fmt.Errorf("malformed match query: %w", err))
+		}
+	}
+
+	switch strings.ToLower(match.Operator) {
+	case "and":
+		return e.search(match.Query, DefaultEngine)
+	case "", "or":
+	default:
+		return nil, badRequest(fmt.Errorf("unknown operator [%s]", match.Operator))
+	}
+	matches := esMatches{}
+	for _, word := range strings.Fields(match.Query) {
+		wordMatches, err := e.search(word, DefaultEngine)
+		if err != nil {
+			return nil, err
+		}
+		matches.union(wordMatches)
+	}
+	return matches, nil
+}
+
+func (e esQueryEval) term(body json.RawMessage) (esMatches, error) {
+	_, params, err := esSingleKey(body, "term")
+	if err != nil {
+		return nil, err
+	}
+	var term struct {
+		Value json.RawMessage `json:"value"`
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(params), []byte("{")) {
+		if err := json.Unmarshal(params, &term); err != nil {
+			return nil, badRequest(fmt.Errorf("malformed term query: %w", err))
+		}
+	} else {
+		term.Value = params
+	}
+	var value any
+	if err := json.Unmarshal(term.Value, &value); err != nil || value == nil {
+		return nil, badRequest(errors.New("term query with no value"))
+	}
+	return e.search(fmt.Sprint(value), DefaultEngine)
+}
+
+func (e esQueryEval) bool(body json.RawMessage) (esMatches, error) {
+	var clauses struct {
+		Must    esClauses `json:"must"`
+		Filter  esClauses `json:"filter"`
+		Should  esClauses `json:"should"`
+		MustNot esClauses `json:"must_not"`
+	}
+	if err := json.Unmarshal(body, &clauses); err != nil {
+		return nil, badRequest(fmt.Errorf("malformed bool query: %w", err))
+	}
+
+	var matches esMatches
+	for _, query := range clauses.Must {
+		clauseMatches, err := e.eval(query)
+		if err != nil {
+			return nil, err
+		}
+		matches = matches.intersect(clauseMatches, true)
+	}
+	for _, query := range clauses.Filter {
+		clauseMatches, err := e.eval(query)
+		if err != nil {
+			return nil, err
+		}
+		matches = matches.intersect(clauseMatches, false)
+	}
+
+	should := esMatches{}
+	for _, query := range clauses.Should {
+		clauseMatches, err := e.eval(query)
+		if err != nil {
+			return nil, err
+		}
+		should.union(clauseMatches)
+	}
+	switch {
+	case matches == nil && len(clauses.Should) > 0:
+		matches = should
+	case matches == nil:
+		// Only must_not clauses, which exclude from every document
+		var err error
+		if matches, err = e.search("", "noop_all"); err != nil {
+			return nil, err
+		}
+	default:
+		for id, result := range matches {
+			result.Hits += should[id].Hits
+			matches[id] = result
+		}
+	}
+
+	for _, query := range clauses.MustNot {
+		clauseMatches, err := e.eval(query)
+		if err != nil {
+			return nil, err
+		}
+		for id := range clauseMatches {
+			delete(matches, id)
+		}
+	}
+	return matches, nil
+}
+
+func (e esQueryEval) search(terms, engine string) (esMatches, error) {
+	_, results, err := searchPage(e.repo, searchParams{
+		index:  e.index,
+		terms:  terms,
+		engine: engine,
+		size:   -1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	matches := make(esMatches, len(results))
+	for _, result := range results {
+		// Documents of indices sharing an alias may be found twice
+		matches.add(result)
+	}
+	return matches, nil
+}
+
+// add adds the result to the matches, summing the scores of a document
+// matched already.
+func (m esMatches) add(result visigoth.SearchResult) {
+	if prev, ok := m[result.Document.ID()]; ok {
+		result.Hits += prev.Hits
+	}
+	m[result.Document.ID()] = result
+}
+
+// union adds the other matches.
+func (m esMatches) union(other esMatches) {
+	for _, result := range other {
+		m.add(result)
+	}
+}
+
+// intersect returns the documents matched by both, or those of other if m is
+// nil, adding the scores of other if scored.
+func (m esMatches) intersect(other esMatches, scored bool) esMatches {
+	if !scored {
+		other = other.unscored()
+	}
+	if m == nil {
+		return other
+	}
+	res := esMatches{}
+	for id, result := range m {
+		if o, ok := other[id]; ok {
+			result.Hits += o.Hits
+			res[id] = result
+		}
+	}
+	return res
+}
+
+func (m esMatches) unscored() esMatches {
+	res := make(esMatches, len(m))
+	for id, result := range m {
+		result.Hits = 0
+		res[id] = result
+	}
+	return res
+}
+
+// sorted returns the matches sorted by relevance.
+func (m esMatches) sorted() []visigoth.SearchResult {
+	results := make([]visigoth.SearchResult, 0, len(m))
+	for _, result := range m {
+		results = append(results, result)
+	}
+	sort.Sort(visigoth.SearchResults(results))
+	return results
+}
+
+// esClauses are the clauses of a bool query, given as a single query or an
+// array of them.
+type esClauses []json.RawMessage
+
+func (c *esClauses) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		return json.Unmarshal(data, (*[]json.RawMessage)(c))
+	}
+	*c = esClauses{data}
+	return nil
+}
+
+// esSingleKey returns the key and value of an object with exactly one key,
+// such as a query or the field of a match query.
+func esSingleKey(data json.RawMessage, what string) (string, json.RawMessage, error) {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return "", nil, badRequest(fmt.Errorf("malformed %s: %w", what, err))
+	}
+	if len(obj) != 1 {
+		return "", nil, badRequest(
+			fmt.Errorf("%s should have exactly one key, got %d", what, len(obj)),
+		)
+	}
+	for key, value := range obj {
+		return key, value, nil
+	}
+	panic("unreachable")
+}
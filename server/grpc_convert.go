@@ -0,0 +1,93 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/sonirico/visigoth"
+	"github.com/sonirico/visigoth/pb"
+)
+
+func putRequest(req *pb.PutRequest) (visigoth.DocRequest, error) {
+	doc := req.GetDocument()
+	if doc == nil {
+		return visigoth.DocRequest{}, errors.New("put with no document")
+	}
+	var mime string
+	switch doc.GetMime() {
+	case pb.Mime_MIME_UNSPECIFIED, pb.Mime_MIME_TEXT:
+		mime = "text"
+	case pb.Mime_MIME_JSON:
+		mime = "json"
+	default:
+		return visigoth.DocRequest{}, fmt.Errorf("unknown mime type %d", doc.GetMime())
+	}
+	return Document{
+		ID:        doc.GetId(),
+		Content:   doc.GetContent(),
+		Statement: doc.GetStatement(),
+		Mime:      mime,
+		Language:  doc.GetLanguage(),
+	}.DocRequest()
+}
+
+func searchResultToPB(result visigoth.SearchResult) *pb.SearchResult {
+	return &pb.SearchResult{
+		Doc: &pb.Doc{
+			Id:       result.Document.ID(),
+			Raw:      result.Document.Raw(),
+			Language: string(result.Document.Language),
+		},
+		Hits:       int32(result.Hits),
+		Highlights: result.Highlights,
+	}
+}
+
+func suggestionToPB(suggestion visigoth.Suggestion) *pb.SuggestResponse {
+	res := &pb.SuggestResponse{
+		Tokens: make([]*pb.TokenSuggestion, len(suggestion.Tokens)),
+		Query:  suggestion.Query(),
+	}
+	for i, tok := range suggestion.Tokens {
+		candidates := make([]*pb.SuggestionCandidate, len(tok.Candidates))
+		for j, c := range tok.Candidates {
+			candidates[j] = &pb.SuggestionCandidate{
+				Term:     c.Term,
+				Distance: int32(c.Distance),
+				DocFreq:  int32(c.DocFreq),
+			}
+		}
+		res.Tokens[i] = &pb.TokenSuggestion{
+			Token:      tok.Token,
+			DocFreq:    int32(tok.DocFreq),
+			Candidates: candidates,
+		}
+	}
+	return res
+}
+
+func analysisToPB(analysis visigoth.Analysis) *pb.AnalyzeResponse {
+	res := &pb.AnalyzeResponse{
+		Analyzer:     analysis.Analyzer,
+		Language:     string(analysis.Language),
+		CharFiltered: analysis.CharFiltered,
+		Stages:       make([]*pb.AnalysisStage, len(analysis.Stages)),
+	}
+	for i, stage := range analysis.Stages {
+		tokens := make([]*pb.Token, len(stage.Tokens))
+		for j, tok := range stage.Tokens {
+			tokens[j] = &pb.Token{
+				Term:              tok.Term,
+				Position:          int32(tok.Position),
+				PositionIncrement: int32(tok.PositionIncrement),
+				PositionLength:    int32(tok.PositionLength),
+				Start:             int32(tok.Start),
+				End:               int32(tok.End),
+				Type:              string(tok.Type),
+				Keyword:           tok.Keyword,
+			}
+		}
+		res.Stages[i] = &pb.AnalysisStage{Name: stage.Name, Tokens: tokens}
+	}
+	return res
+}
@@ -0,0 +1,337 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/sonirico/visigoth"
+	"github.com/sonirico/visigoth/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// TotalHeader is the header of search streams holding the total number of
+// results.
+const TotalHeader = "visigoth-total"
+
+// GRPCServer serves a visigoth.Repo as the pb.VisigothServer service. Errors
+// are returned with the gRPC code matching their ErrorResponse status.
+//
+// With a key store, calls authenticate with an API key in the
+// "authorization" or "x-api-key" metadata, as HTTP requests do with the
+// headers of the same names.
+type GRPCServer struct {
+	pb.UnimplementedVisigothServer
+	repo visigoth.Repo
+	keys *KeyStore
+}
+
+// GRPCServerOption configures a GRPCServer.
+type GRPCServerOption func(*GRPCServer)
+
+// WithGRPCKeyStore requires calls to authenticate with a key of the store,
+// and limits them to what the key allows.
+func WithGRPCKeyStore(keys *KeyStore) GRPCServerOption {
+	return func(s *GRPCServer) {
+		s.keys = keys
+	}
+}
+
+// repoFor returns the repo as seen by the caller.
+func (s *GRPCServer) repoFor(ctx context.Context) (visigoth.Repo, error) {
+	if s.keys == nil {
+		return s.repo, nil
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	key, err := s.keys.authenticate(
+		firstMetadata(md, "authorization"),
+		firstMetadata(md, "x-api-key"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return AuthorizedRepo(s.repo, key), nil
+}
+
+func firstMetadata(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func (s *GRPCServer) ListIndices(
+	ctx context.Context,
+	_ *pb.ListIndicesRequest,
+) (*pb.ListIndicesResponse, error) {
+	repo, err := s.repoFor(ctx)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.ListIndicesResponse{Indices: listIndices(repo)}, nil
+}
+
+func (s *GRPCServer) CreateIndex(
+	ctx context.Context,
+	req *pb.CreateIndexRequest,
+) (*pb.CreateIndexResponse, error) {
+	repo, err := s.repoFor(ctx)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	if err := createIndex(repo, req.GetIndex()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.CreateIndexResponse{}, nil
+}
+
+func (s *GRPCServer) DropIndex(
+	ctx context.Context,
+	req *pb.DropIndexRequest,
+) (*pb.DropIndexResponse, error) {
+	repo, err := s.repoFor(ctx)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	if err := dropIndex(repo, req.GetIndex()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.DropIndexResponse{}, nil
+}
+
+func (s *GRPCServer) RenameIndex(
+	ctx context.Context,
+	req *pb.RenameIndexRequest,
+) (*pb.RenameIndexResponse, error) {
+	repo, err := s.repoFor(ctx)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	if err := renameIndex(repo, req.GetIndex(), req.GetName()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.RenameIndexResponse{}, nil
+}
+
+func (s *GRPCServer) Put(ctx context.Context, req *pb.PutRequest) (*pb.PutResponse, error) {
+	repo, err := s.repoFor(ctx)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	doc, err := putRequest(req)
+	if err != nil {
+		return nil, toStatus(badRequest(err))
+	}
+	if err := putDocument(repo, req.GetIndex(), doc); err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.PutResponse{}, nil
+}
+
+func (s *GRPCServer) BulkPut(
+	stream grpc.ClientStreamingServer[pb.PutRequest, pb.BulkPutResponse],
+) error {
+	repo, err := s.repoFor(stream.Context())
+	if err != nil {
+		return toStatus(err)
+	}
+	res := &pb.BulkPutResponse{}
+	for position := int32(1); ; position++ {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return stream.SendAndClose(res)
+		}
+		if err != nil {
+			return err
+		}
+		doc, err := putRequest(req)
+		if err == nil {
+			err = putDocument(repo, req.GetIndex(), doc)
+		}
+		if err != nil {
+			res.Errors = append(res.Errors, &pb.BulkError{Position: position, Message: err.Error()})
+			continue
+		}
+		res.Indexed++
+	}
+}
+
+func (s *GRPCServer) Search(
+	req *pb.SearchRequest,
+	stream grpc.ServerStreamingServer[pb.SearchResult],
+) error {
+	if req.GetFrom() < 0 || req.GetSize() < 0 {
+		return status.Error(codes.InvalidArgument, "from and size should be non-negative")
+	}
+	repo, err := s.repoFor(stream.Context())
+	if err != nil {
+		return toStatus(err)
+	}
+	size := -1
+	if req.Size != nil {
+		size = int(req.GetSize())
+	}
+	total, results, err := searchPage(repo, searchParams{
+		index:     req.GetIndex(),
+		terms:     req.GetQuery(),
+		engine:    req.GetEngine(),
+		from:      int(req.GetFrom()),
+		size:      size,
+		languages: req.GetLanguages(),
+	})
+	if err != nil {
+		return toStatus(err)
+	}
+	if err := stream.SendHeader(metadata.Pairs(TotalHeader, strconv.Itoa(total))); err != nil {
+		return err
+	}
+	for _, result := range results {
+		if err := stream.Send(searchResultToPB(result)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *GRPCServer) Suggest(
+	ctx context.Context,
+	req *pb.SuggestRequest,
+) (*pb.SuggestResponse, error) {
+	repo, err := s.repoFor(ctx)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	suggestion, err := repo.Suggest(req.GetIndex(), req.GetQuery())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return suggestionToPB(suggestion), nil
+}
+
+func (s *GRPCServer) Analyze(
+	ctx context.Context,
+	req *pb.AnalyzeRequest,
+) (*pb.AnalyzeResponse, error) {
+	repo, err := s.repoFor(ctx)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	analysis, err := repo.Analyze(req.GetName(), req.GetText())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return analysisToPB(analysis), nil
+}
+
+func (s *GRPCServer) ListAliases(
+	ctx context.Context,
+	_ *pb.ListAliasesRequest,
+) (*pb.ListAliasesResponse, error) {
+	repo, err := s.repoFor(ctx)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	aliases := listAliases(repo)
+	res := &pb.ListAliasesResponse{Aliases: make([]*pb.Alias, len(aliases))}
+	for i, alias := range aliases {
+		res.Aliases[i] = &pb.Alias{Alias: alias.Alias, Indices: alias.Indices}
+	}
+	return res, nil
+}
+
+func (s *GRPCServer) Alias(ctx context.Context, req *pb.AliasRequest) (*pb.AliasResponse, error) {
+	repo, err := s.repoFor(ctx)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	if err := addAlias(repo, req.GetAlias(), req.GetIndex()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.AliasResponse{}, nil
+}
+
+func (s *GRPCServer) UnAlias(
+	ctx context.Context,
+	req *pb.UnAliasRequest,
+) (*pb.UnAliasResponse, error) {
+	repo, err := s.repoFor(ctx)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	if err := removeAlias(repo, req.GetAlias(), req.GetIndex()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.UnAliasResponse{}, nil
+}
+
+// grpcCodes map the statuses of API errors to gRPC codes.
+var grpcCodes = map[int]codes.Code{
+	http.StatusBadRequest:            codes.InvalidArgument,
+	http.StatusUnauthorized:          codes.Unauthenticated,
+	http.StatusForbidden:             codes.PermissionDenied,
+	http.StatusNotFound:              codes.NotFound,
+	http.StatusConflict:              codes.AlreadyExists,
+	http.StatusRequestEntityTooLarge: codes.ResourceExhausted,
+	http.StatusTooManyRequests:       codes.ResourceExhausted,
+}
+
+func toStatus(err error) error {
+	apiErr := toAPIError(err)
+	code, ok := grpcCodes[apiErr.Status]
+	if !ok {
+		code = codes.Internal
+	}
+	return status.Error(code, apiErr.Message)
+}
+
+// ServeGRPC runs srv on the listener until ctx is done, and then stops it
+// gracefully, waiting up to timeout for in-flight calls to finish before
+// cancelling them.
+func ServeGRPC(
+	ctx context.Context,
+	srv *grpc.Server,
+	ln net.Listener,
+	timeout time.Duration,
+) error {
+	errs := make(chan error, 1)
+	go func() {
+		errs <- srv.Serve(ln)
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		srv.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(timeout):
+		srv.Stop()
+	}
+	if err := <-errs; err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+		return fmt.Errorf("serving gRPC: %w", err)
+	}
+	return nil
+}
+
+func NewGRPCServer(repo visigoth.Repo, opts ...GRPCServerOption) *GRPCServer {
+	s := &GRPCServer{repo: repo}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
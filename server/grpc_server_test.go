@@ -0,0 +1,190 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/sonirico/visigoth"
+	"github.com/sonirico/visigoth/pb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newTestGRPCClient(t *testing.T, opts ...GRPCServerOption) pb.VisigothClient {
+	repo := visigoth.NewIndexRepo(visigoth.NewMemoryIndexBuilder(
+		visigoth.NewTokenizationPipeline(
+			visigoth.NewKeepAlphanumericTokenizer(),
+			visigoth.NewLowerCaseTokenizer(),
+		),
+	))
+	ln := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	pb.RegisterVisigothServer(srv, NewGRPCServer(repo, opts...))
+	go func() { _ = srv.Serve(ln) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return ln.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return pb.NewVisigothClient(conn)
+}
+
+func TestGRPCServer_Search(t *testing.T) {
+	ctx := context.Background()
+	c := newTestGRPCClient(t)
+
+	_, err := c.CreateIndex(ctx, &pb.CreateIndexRequest{Index: "dedos"})
+	require.NoError(t, err)
+	_, err = c.CreateIndex(ctx, &pb.CreateIndexRequest{Index: "dedos"})
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	bulk, err := c.BulkPut(ctx)
+	require.NoError(t, err)
+	for _, doc := range []*pb.Document{
+		{Id: "pulgar", Content: "este fue a por huevos"},
+		{Content: "no id"},
+		{Id: "corazon", Content: "este fue a por huevos y los probo"},
+	} {
+		require.NoError(t, bulk.Send(&pb.PutRequest{Index: "dedos", Document: doc}))
+	}
+	bulkRes, err := bulk.CloseAndRecv()
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), bulkRes.GetIndexed())
+	require.Len(t, bulkRes.GetErrors(), 1)
+	assert.Equal(t, int32(2), bulkRes.GetErrors()[0].GetPosition())
+
+	size := int32(1)
+	stream, err := c.Search(ctx, &pb.SearchRequest{Index: "dedos", Query: "huevos", Size: &size})
+	require.NoError(t, err)
+	header, err := stream.Header()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2"}, header.Get(TotalHeader))
+	var ids []string
+	for {
+		res, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		ids = append(ids, res.GetDoc().GetId())
+	}
+	assert.Equal(t, []string{"corazon"}, ids)
+
+	stream, err = c.Search(ctx, &pb.SearchRequest{Index: "sabores", Query: "huevos"})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	stream, err = c.Search(ctx, &pb.SearchRequest{Index: "dedos", Engine: "nope"})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGRPCServer_Aliases(t *testing.T) {
+	ctx := context.Background()
+	c := newTestGRPCClient(t)
+
+	_, err := c.Put(ctx, &pb.PutRequest{
+		Index:    "dedos",
+		Document: &pb.Document{Id: "pulgar", Content: "Este fue a por huevos"},
+	})
+	require.NoError(t, err)
+	_, err = c.Alias(ctx, &pb.AliasRequest{Alias: "latest", Index: "dedos"})
+	require.NoError(t, err)
+	aliases, err := c.ListAliases(ctx, &pb.ListAliasesRequest{})
+	require.NoError(t, err)
+	require.Len(t, aliases.GetAliases(), 1)
+	assert.Equal(t, []string{"dedos"}, aliases.GetAliases()[0].GetIndices())
+
+	analysis, err := c.Analyze(ctx, &pb.AnalyzeRequest{Name: "latest", Text: "Huevos"})
+	require.NoError(t, err)
+	stages := analysis.GetStages()
+	require.Len(t, stages, 2)
+	assert.Equal(t, "huevos", stages[1].GetTokens()[0].GetTerm())
+
+	_, err = c.UnAlias(ctx, &pb.UnAliasRequest{Alias: "latest"})
+	require.NoError(t, err)
+	_, err = c.RenameIndex(ctx, &pb.RenameIndexRequest{Index: "dedos", Name: "manos"})
+	require.NoError(t, err)
+	indices, err := c.ListIndices(ctx, &pb.ListIndicesRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"manos"}, indices.GetIndices())
+	_, err = c.DropIndex(ctx, &pb.DropIndexRequest{Index: "dedos"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestGRPCServer_Auth(t *testing.T) {
+	c := newTestGRPCClient(t, WithGRPCKeyStore(testKeys(t)))
+	as := func(authorization string) context.Context {
+		return metadata.AppendToOutgoingContext(
+			context.Background(),
+			"authorization",
+			authorization,
+		)
+	}
+
+	_, err := c.ListIndices(context.Background(), &pb.ListIndicesRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = c.CreateIndex(as("Bearer r34d"), &pb.CreateIndexRequest{Index: "dedos"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = c.Put(as("Bearer wr1t3"), &pb.PutRequest{
+		Index:    "dedos",
+		Document: &pb.Document{Id: "pulgar", Content: "este fue a por huevos"},
+	})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err),
+		"writer should not create indices by putting documents")
+	for _, index := range []string{"dedos", "manos"} {
+		_, err = c.CreateIndex(as("Bearer 4dm1n"), &pb.CreateIndexRequest{Index: index})
+		require.NoError(t, err)
+	}
+
+	bulk, err := c.BulkPut(as("Bearer wr1t3"))
+	require.NoError(t, err)
+	require.NoError(t, bulk.Send(&pb.PutRequest{
+		Index:    "dedos",
+		Document: &pb.Document{Id: "pulgar", Content: "este fue a por huevos"},
+	}))
+	require.NoError(t, bulk.Send(&pb.PutRequest{
+		Index:    "manos",
+		Document: &pb.Document{Id: "palma", Content: "huevos"},
+	}))
+	res, err := bulk.CloseAndRecv()
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, res.GetIndexed())
+	require.Len(t, res.GetErrors(), 1)
+	assert.Equal(t, int32(2), res.GetErrors()[0].GetPosition())
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "r34d")
+	indices, err := c.ListIndices(ctx, &pb.ListIndicesRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dedos"}, indices.GetIndices())
+	stream, err := c.Search(ctx, &pb.SearchRequest{Index: "manos", Query: "huevos"})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestServeGRPC_GracefulStop(t *testing.T) {
+	ln := bufconn.Listen(1 << 20)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- ServeGRPC(ctx, grpc.NewServer(), ln, DefaultShutdownTimeout)
+	}()
+	cancel()
+	assert.NoError(t, <-done)
+}
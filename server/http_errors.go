@@ -0,0 +1,82 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/sonirico/visigoth"
+)
+
+// Error codes of API errors.
+const (
+	CodeBadRequest       = "bad_request"
+	CodeNotFound         = "not_found"
+	CodeMethodNotAllowed = "method_not_allowed"
+	CodeConflict         = "conflict"
+	CodeUnauthorized     = "unauthorized"
+	CodeForbidden        = "forbidden"
+	CodeTooLarge         = "request_too_large"
+	CodeInternalError    = "internal_error"
+)
+
+// APIError is the structured error returned by the API, within an
+// ErrorResponse.
+type APIError struct {
+	Status  int    `json:"status"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *APIError) Error() string {
+	return e.Message
+}
+
+type ErrorResponse struct {
+	Error *APIError `json:"error"`
+}
+
+func newAPIError(status int, code string, message string) *APIError {
+	return &APIError{Status: status, Code: code, Message: message}
+}
+
+func badRequest(err error) *APIError {
+	return newAPIError(http.StatusBadRequest, CodeBadRequest, err.Error())
+}
+
+func notFound(kind, name string) *APIError {
+	return toAPIError(visigoth.NotFoundError{Kind: kind, Name: name})
+}
+
+func conflict(message string) *APIError {
+	return newAPIError(http.StatusConflict, CodeConflict, message)
+}
+
+func unauthorized(message string) *APIError {
+	return newAPIError(http.StatusUnauthorized, CodeUnauthorized, message)
+}
+
+func forbidden(message string) *APIError {
+	return newAPIError(http.StatusForbidden, CodeForbidden, message)
+}
+
+// toAPIError maps errors returned by the repo to API errors.
+func toAPIError(err error) *APIError {
+	var (
+		apiErr      *APIError
+		notFoundErr visigoth.NotFoundError
+		existsErr   visigoth.ExistsError
+		tooLarge    *http.MaxBytesError
+	)
+	switch {
+	case errors.As(err, &apiErr):
+		return apiErr
+	case errors.As(err, &notFoundErr):
+		return newAPIError(http.StatusNotFound, CodeNotFound, err.Error())
+	case errors.As(err, &existsErr):
+		return conflict(err.Error())
+	case errors.As(err, &tooLarge):
+		return newAPIError(http.StatusRequestEntityTooLarge, CodeTooLarge, err.Error())
+	default:
+		return newAPIError(http.StatusInternalServerError, CodeInternalError, err.Error())
+	}
+}
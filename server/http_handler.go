@@ -0,0 +1,471 @@
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mailru/easyjson/jwriter"
+	"github.com/sonirico/visigoth"
+)
+
+const (
+	// DefaultEngine is the engine used by searches not choosing one.
+	DefaultEngine = "hits"
+	// DefaultMaxBodyBytes limits the size of request bodies.
+	DefaultMaxBodyBytes = 32 << 20
+	// maxBulkLineBytes limits the size of a single document of a bulk request.
+	maxBulkLineBytes = 4 << 20
+)
+
+// HTTPHandler serves a visigoth.Repo over HTTP/JSON:
+//
+//	GET    /health
+//	GET    /indices
+//	PUT    /indices/{index}
+//	DELETE /indices/{index}
+//	POST   /indices/{index}/_rename         {"name": "new"}
+//	POST   /indices/{index}/docs            {"id": "", "content": "", ...}
+//	POST   /indices/{index}/_bulk           one document per line
+//	GET    /indices/{index}/_search         ?q=&engine=hits&from=0&size=10&lang=
+//	GET    /indices/{index}/_suggest        ?q=
+//	GET    /indices/{index}/_analyze        ?text=
+//	GET    /aliases
+//	PUT    /aliases/{alias}/{index}
+//	DELETE /aliases/{alias}/{index}
+//	DELETE /aliases/{alias}
+//
+// Search, suggest and analyze requests accept aliases in place of indices.
+// Errors are returned as an ErrorResponse, with a 405 and the allowed methods
+// for paths of routes requested with other methods.
+//
+// With WithKeyStore, every request to a route but health checks must carry an
+// API key, and may only do what the key grants.
+type HTTPHandler struct {
+	repo         visigoth.Repo
+	logger       *slog.Logger
+	maxBodyBytes int64
+	keys         *KeyStore
+	mux          *http.ServeMux
+	// writeError writes the errors of handlers, as an ErrorResponse.
+	writeError func(w http.ResponseWriter, err *APIError)
+	// noRoute returns the error of requests matching no route, whose path
+	// matches the routes of the allowed methods, if any.
+	noRoute func(r *http.Request, allowed string) *APIError
+}
+
+// HTTPHandlerOption configures an HTTPHandler.
+type HTTPHandlerOption func(*HTTPHandler)
+
+// WithLogger sets the logger requests are logged to. Nothing is logged by
+// default.
+func WithLogger(logger *slog.Logger) HTTPHandlerOption {
+	return func(h *HTTPHandler) {
+		h.logger = logger
+	}
+}
+
+// WithMaxBodyBytes limits the size of request bodies, DefaultMaxBodyBytes by
+// default.
+func WithMaxBodyBytes(n int64) HTTPHandlerOption {
+	return func(h *HTTPHandler) {
+		h.maxBodyBytes = n
+	}
+}
+
+// WithKeyStore requires requests to authenticate with a key of the store,
+// given as a bearer token or in an X-API-Key header. Anyone may do anything
+// by default.
+func WithKeyStore(keys *KeyStore) HTTPHandlerOption {
+	return func(h *HTTPHandler) {
+		h.keys = keys
+	}
+}
+
+func (h *HTTPHandler) routes() {
+	h.handlePublic("GET /health", h.health)
+	h.handle("GET /indices", h.listIndices)
+	h.handle("PUT /indices/{index}", h.createIndex)
+	h.handle("DELETE /indices/{index}", h.dropIndex)
+	h.handle("POST /indices/{index}/_rename", h.renameIndex)
+	h.handle("POST /indices/{index}/docs", h.putDocument)
+	h.handle("POST /indices/{index}/_bulk", h.bulk)
+	h.handle("GET /indices/{index}/_search", h.search)
+	h.handle("GET /indices/{index}/_suggest", h.suggest)
+	h.handle("GET /indices/{index}/_analyze", h.analyze)
+	h.handle("POST /indices/{index}/_analyze", h.analyze)
+	h.handle("GET /aliases", h.listAliases)
+	h.handle("PUT /aliases/{alias}/{index}", h.alias)
+	h.handle("DELETE /aliases/{alias}/{index}", h.unAlias)
+	h.handle("DELETE /aliases/{alias}", h.unAlias)
+}
+
+// noRouteError returns the error of a request matching no route, listing the
+// allowed methods if its path matches some.
+func noRouteError(r *http.Request, allowed string) *APIError {
+	if allowed != "" {
+		return newAPIError(
+			http.StatusMethodNotAllowed,
+			CodeMethodNotAllowed,
+			fmt.Sprintf("method %s not allowed for %s, allowed: %s", r.Method, r.URL.Path, allowed),
+		)
+	}
+	return newAPIError(
+		http.StatusNotFound,
+		CodeNotFound,
+		fmt.Sprintf("no route for %s %s", r.Method, r.URL.Path),
+	)
+}
+
+// handlerFunc is an http.HandlerFunc which may fail with an error to be
+// written as an ErrorResponse.
+type handlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+// handle serves the pattern with fn, to authenticated callers only when
+// keys are required.
+func (h *HTTPHandler) handle(pattern string, fn handlerFunc) {
+	h.handlePublic(pattern, func(w http.ResponseWriter, r *http.Request) error {
+		if h.keys == nil {
+			return fn(w, r)
+		}
+		key, err := h.keys.authenticate(r.Header.Get("Authorization"), r.Header.Get("X-API-Key"))
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="visigoth"`)
+			return err
+		}
+		return fn(w, r.WithContext(contextWithAPIKey(r.Context(), key)))
+	})
+}
+
+// handlePublic serves the pattern with fn, to anyone.
+func (h *HTTPHandler) handlePublic(pattern string, fn handlerFunc) {
+	h.mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		if r.Body != nil {
+			r.Body = http.MaxBytesReader(w, r.Body, h.maxBodyBytes)
+		}
+		if err := fn(w, r); err != nil {
+			apiErr := toAPIError(err)
+			if apiErr.Status >= http.StatusInternalServerError {
+				h.logger.ErrorContext(r.Context(), "request failed",
+					"method", r.Method, "path", r.URL.Path, "error", err)
+			}
+			h.writeError(w, apiErr)
+		}
+	})
+}
+
+func (h *HTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	// Requests matching no route need no API key to be told so
+	if handler, pattern := h.mux.Handler(r); pattern == "" {
+		h.noRouteHandler(handler).ServeHTTP(rec, r)
+	} else {
+		h.mux.ServeHTTP(rec, r)
+	}
+	h.logger.InfoContext(r.Context(), "request",
+		"method", r.Method,
+		"path", r.URL.Path,
+		"status", rec.status,
+		"duration", time.Since(start))
+}
+
+// noRouteHandler answers requests matching no route with an error, given the
+// handler of the mux for them, which tells whether it is a 404 or a 405 and
+// the methods allowed then.
+func (h *HTTPHandler) noRouteHandler(mux http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := &headerRecorder{header: http.Header{}}
+		mux.ServeHTTP(rec, r)
+		allowed := ""
+		if rec.status == http.StatusMethodNotAllowed {
+			allowed = rec.header.Get("Allow")
+			w.Header().Set("Allow", allowed)
+		}
+		h.writeError(w, h.noRoute(r, allowed))
+	})
+}
+
+// headerRecorder records the header and status written to it, discarding the
+// body.
+type headerRecorder struct {
+	header http.Header
+	status int
+}
+
+func (h *headerRecorder) Header() http.Header         { return h.header }
+func (h *headerRecorder) Write(b []byte) (int, error) { return len(b), nil }
+func (h *headerRecorder) WriteHeader(status int)      { h.status = status }
+
+// repoFor returns the repo as seen by the caller of the request.
+func (h *HTTPHandler) repoFor(r *http.Request) visigoth.Repo {
+	if key, ok := apiKeyFromContext(r.Context()); ok {
+		return AuthorizedRepo(h.repo, key)
+	}
+	return h.repo
+}
+
+func (h *HTTPHandler) health(w http.ResponseWriter, _ *http.Request) error {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	return nil
+}
+
+func (h *HTTPHandler) listIndices(w http.ResponseWriter, r *http.Request) error {
+	writeJSON(w, http.StatusOK, IndicesResponse{Indices: listIndices(h.repoFor(r))})
+	return nil
+}
+
+func (h *HTTPHandler) createIndex(w http.ResponseWriter, r *http.Request) error {
+	if err := createIndex(h.repoFor(r), r.PathValue("index")); err != nil {
+		return err
+	}
+	writeJSON(w, http.StatusCreated, AcknowledgedResponse{Acknowledged: true})
+	return nil
+}
+
+func (h *HTTPHandler) dropIndex(w http.ResponseWriter, r *http.Request) error {
+	if err := dropIndex(h.repoFor(r), r.PathValue("index")); err != nil {
+		return err
+	}
+	writeJSON(w, http.StatusOK, AcknowledgedResponse{Acknowledged: true})
+	return nil
+}
+
+func (h *HTTPHandler) renameIndex(w http.ResponseWriter, r *http.Request) error {
+	var req RenameRequest
+	if err := decodeJSON(r.Body, &req); err != nil {
+		return err
+	}
+	if err := renameIndex(h.repoFor(r), r.PathValue("index"), req.Name); err != nil {
+		return err
+	}
+	writeJSON(w, http.StatusOK, AcknowledgedResponse{Acknowledged: true})
+	return nil
+}
+
+func (h *HTTPHandler) putDocument(w http.ResponseWriter, r *http.Request) error {
+	var doc Document
+	if err := decodeJSON(r.Body, &doc); err != nil {
+		return err
+	}
+	req, err := doc.DocRequest()
+	if err != nil {
+		return badRequest(err)
+	}
+	if err := putDocument(h.repoFor(r), r.PathValue("index"), req); err != nil {
+		return err
+	}
+	writeJSON(w, http.StatusCreated, AcknowledgedResponse{Acknowledged: true})
+	return nil
+}
+
+// bulk indexes one JSON document per line. Documents failing to decode are
+// reported by line and do not prevent the others from being indexed.
+func (h *HTTPHandler) bulk(w http.ResponseWriter, r *http.Request) error {
+	repo, index := h.repoFor(r), r.PathValue("index")
+	if err := authorizePut(repo, index); err != nil {
+		return err
+	}
+	scanner := bufio.NewScanner(r.Body)
+	scanner.Buffer(make([]byte, 0, 64<<10), maxBulkLineBytes)
+
+	var res BulkResponse
+	for line := 1; scanner.Scan(); line++ {
+		data := scanner.Bytes()
+		if len(strings.TrimSpace(string(data))) == 0 {
+			continue
+		}
+		var doc Document
+		err := json.Unmarshal(data, &doc)
+		var req visigoth.DocRequest
+		if err == nil {
+			req, err = doc.DocRequest()
+		}
+		if err != nil {
+			res.Errors = append(res.Errors, BulkError{Line: line, Message: err.Error()})
+			continue
+		}
+		if err := putDocument(repo, index, req); err != nil {
+			return err
+		}
+		res.Indexed++
+	}
+	if err := scanner.Err(); err != nil {
+		return badRequest(err)
+	}
+	writeJSON(w, http.StatusOK, res)
+	return nil
+}
+
+func (h *HTTPHandler) search(w http.ResponseWriter, r *http.Request) error {
+	query := r.URL.Query()
+	from, err := intParam(query.Get("from"), "from", 0)
+	if err != nil {
+		return err
+	}
+	size, err := intParam(query.Get("size"), "size", -1)
+	if err != nil {
+		return err
+	}
+	total, results, err := searchPage(h.repoFor(r), searchParams{
+		index:     r.PathValue("index"),
+		terms:     query.Get("q"),
+		engine:    query.Get("engine"),
+		from:      from,
+		size:      size,
+		languages: query["lang"],
+	})
+	if err != nil {
+		return err
+	}
+	writeSearchResponse(w, SearchResponse{Total: total, From: from, Results: results})
+	return nil
+}
+
+func (h *HTTPHandler) suggest(w http.ResponseWriter, r *http.Request) error {
+	suggestion, err := h.repoFor(r).Suggest(r.PathValue("index"), r.URL.Query().Get("q"))
+	if err != nil {
+		return err
+	}
+	writeJSON(w, http.StatusOK, suggestion)
+	return nil
+}
+
+// analyze takes the text from the "text" query parameter, or from the body
+// of POST requests.
+func (h *HTTPHandler) analyze(w http.ResponseWriter, r *http.Request) error {
+	text := r.URL.Query().Get("text")
+	if r.Method == http.MethodPost {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			return err
+		}
+		text = string(body)
+	}
+	analysis, err := h.repoFor(r).Analyze(r.PathValue("index"), text)
+	if err != nil {
+		return err
+	}
+	writeJSON(w, http.StatusOK, analysis)
+	return nil
+}
+
+func (h *HTTPHandler) listAliases(w http.ResponseWriter, r *http.Request) error {
+	writeJSON(w, http.StatusOK, AliasesResponse{Aliases: listAliases(h.repoFor(r))})
+	return nil
+}
+
+func (h *HTTPHandler) alias(w http.ResponseWriter, r *http.Request) error {
+	if err := addAlias(h.repoFor(r), r.PathValue("alias"), r.PathValue("index")); err != nil {
+		return err
+	}
+	writeJSON(w, http.StatusOK, AcknowledgedResponse{Acknowledged: true})
+	return nil
+}
+
+// unAlias removes an index from an alias, or the whole alias if no index is
+// given.
+func (h *HTTPHandler) unAlias(w http.ResponseWriter, r *http.Request) error {
+	if err := removeAlias(h.repoFor(r), r.PathValue("alias"), r.PathValue("index")); err != nil {
+		return err
+	}
+	writeJSON(w, http.StatusOK, AcknowledgedResponse{Acknowledged: true})
+	return nil
+}
+
+func intParam(value, name string, def int) (int, error) {
+	if value == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, badRequest(
+			fmt.Errorf("parameter '%s' should be a non-negative integer, got '%s'", name, value),
+		)
+	}
+	return n, nil
+}
+
+func decodeJSON(r io.Reader, v any) error {
+	if err := json.NewDecoder(r).Decode(v); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return err
+		}
+		return badRequest(fmt.Errorf("invalid JSON body: %w", err))
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeSearchResponse writes the results with their easyjson marshallers,
+// avoiding reflection for the potentially many of them.
+func writeSearchResponse(w http.ResponseWriter, res SearchResponse) {
+	out := jwriter.Writer{}
+	out.RawString(`{"total":`)
+	out.Int(res.Total)
+	out.RawString(`,"from":`)
+	out.Int(res.From)
+	out.RawString(`,"results":[`)
+	for i, result := range res.Results {
+		if i > 0 {
+			out.RawByte(',')
+		}
+		result.MarshalEasyJSON(&out)
+	}
+	out.RawString("]}\n")
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = out.DumpTo(w)
+}
+
+// statusRecorder remembers the status code written to a response.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(status int) {
+	s.status = status
+	s.ResponseWriter.WriteHeader(status)
+}
+
+func writeErrorResponse(w http.ResponseWriter, err *APIError) {
+	writeJSON(w, err.Status, ErrorResponse{Error: err})
+}
+
+// newHTTPHandler returns a handler with no routes.
+func newHTTPHandler(repo visigoth.Repo, opts []HTTPHandlerOption) *HTTPHandler {
+	h := &HTTPHandler{
+		repo:         repo,
+		logger:       slog.New(discardHandler{}),
+		maxBodyBytes: DefaultMaxBodyBytes,
+		mux:          http.NewServeMux(),
+		writeError:   writeErrorResponse,
+		noRoute:      noRouteError,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// NewHTTPHandler returns a handler serving the repo over HTTP/JSON.
+func NewHTTPHandler(repo visigoth.Repo, opts ...HTTPHandlerOption) *HTTPHandler {
+	h := newHTTPHandler(repo, opts)
+	h.routes()
+	return h
+}
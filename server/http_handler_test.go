@@ -0,0 +1,178 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sonirico/visigoth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestHandler(opts ...HTTPHandlerOption) *HTTPHandler {
+	repo := visigoth.NewIndexRepo(visigoth.NewMemoryIndexBuilder(
+		visigoth.NewTokenizationPipeline(
+			visigoth.NewKeepAlphanumericTokenizer(),
+			visigoth.NewLowerCaseTokenizer(),
+		),
+	))
+	return NewHTTPHandler(repo, opts...)
+}
+
+func do(t *testing.T, h http.Handler, method, target, body string, out any) int {
+	t.Helper()
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	if out != nil {
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), out), rec.Body.String())
+	}
+	return rec.Code
+}
+
+func TestHTTPHandler_Indices(t *testing.T) {
+	h := newTestHandler()
+
+	assert.Equal(t, http.StatusCreated, do(t, h, "PUT", "/indices/dedos", "", nil))
+	var errRes ErrorResponse
+	assert.Equal(t, http.StatusConflict, do(t, h, "PUT", "/indices/dedos", "", &errRes))
+	assert.Equal(t, CodeConflict, errRes.Error.Code)
+
+	assert.Equal(t, http.StatusOK,
+		do(t, h, "POST", "/indices/dedos/_rename", `{"name": "manos"}`, nil))
+	var indices IndicesResponse
+	do(t, h, "GET", "/indices", "", &indices)
+	assert.Equal(t, []string{"manos"}, indices.Indices)
+	assert.Equal(t, http.StatusCreated, do(t, h, "PUT", "/indices/pies", "", nil))
+	assert.Equal(t, http.StatusConflict,
+		do(t, h, "POST", "/indices/manos/_rename", `{"name": "pies"}`, nil))
+	assert.Equal(t, http.StatusNotFound,
+		do(t, h, "POST", "/indices/dedos/_rename", `{"name": "dedos_v2"}`, nil))
+	assert.Equal(t, http.StatusOK, do(t, h, "DELETE", "/indices/pies", "", nil))
+
+	assert.Equal(t, http.StatusOK, do(t, h, "DELETE", "/indices/manos", "", nil))
+	errRes = ErrorResponse{}
+	assert.Equal(t, http.StatusNotFound, do(t, h, "DELETE", "/indices/manos", "", &errRes))
+	assert.Equal(t, &APIError{
+		Status:  http.StatusNotFound,
+		Code:    CodeNotFound,
+		Message: "index with name 'manos' does not exist",
+	}, errRes.Error)
+}
+
+func TestHTTPHandler_Search(t *testing.T) {
+	h := newTestHandler()
+
+	code := do(t, h, "POST", "/indices/dedos/docs",
+		`{"id": "pulgar", "content": "este fue a por huevos"}`, nil)
+	require.Equal(t, http.StatusCreated, code)
+
+	var bulk BulkResponse
+	do(t, h, "POST", "/indices/dedos/_bulk", strings.Join([]string{
+		`{"id": "indice", "content": "este los puso a freir, y este los echo sal"}`,
+		`{"content": "no id"}`,
+		``,
+		`{"id": "corazon", "content": "este fue a por huevos y los probo"}`,
+		`not json`,
+	}, "\n"), &bulk)
+	assert.Equal(t, 2, bulk.Indexed)
+	require.Len(t, bulk.Errors, 2)
+	assert.Equal(t, 2, bulk.Errors[0].Line)
+	assert.Equal(t, 5, bulk.Errors[1].Line)
+
+	var res struct {
+		Total   int                     `json:"total"`
+		From    int                     `json:"from"`
+		Results []visigoth.SearchResult `json:"results"`
+	}
+	assert.Equal(t, http.StatusOK, do(t, h, "GET", "/indices/dedos/_search?q=huevos", "", &res))
+	assert.Equal(t, 2, res.Total)
+	require.Len(t, res.Results, 2)
+	assert.Equal(t, "corazon", res.Results[0].Doc().ID())
+	assert.Equal(t, "pulgar", res.Results[1].Doc().ID())
+
+	do(t, h, "GET", "/indices/dedos/_search?q=huevos&engine=linear&from=1&size=5", "", &res)
+	assert.Equal(t, 2, res.Total)
+	assert.Equal(t, 1, res.From)
+	require.Len(t, res.Results, 1)
+	assert.Equal(t, "pulgar", res.Results[0].Doc().ID())
+
+	var errRes ErrorResponse
+	code = do(t, h, "GET", "/indices/dedos/_search?q=huevos&engine=nope", "", &errRes)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, "engine with name 'nope' does not exist", errRes.Error.Message)
+	code = do(t, h, "GET", "/indices/sabores/_search?q=huevos", "", &errRes)
+	assert.Equal(t, http.StatusNotFound, code)
+}
+
+func TestHTTPHandler_Aliases(t *testing.T) {
+	h := newTestHandler()
+
+	do(t, h, "POST", "/indices/dedos/docs", `{"id": "pulgar", "content": "huevos"}`, nil)
+	assert.Equal(t, http.StatusNotFound, do(t, h, "PUT", "/aliases/latest/sabores", "", nil))
+	assert.Equal(t, http.StatusOK, do(t, h, "PUT", "/aliases/latest/dedos", "", nil))
+	assert.Equal(t, http.StatusConflict, do(t, h, "PUT", "/aliases/latest/dedos", "", nil))
+
+	var aliases AliasesResponse
+	do(t, h, "GET", "/aliases", "", &aliases)
+	assert.Equal(t, []Alias{{Alias: "latest", Indices: []string{"dedos"}}}, aliases.Aliases)
+
+	var res SearchResponse
+	do(t, h, "GET", "/indices/latest/_search?q=huevos", "", &res)
+	assert.Equal(t, 1, res.Total)
+
+	assert.Equal(t, http.StatusNotFound, do(t, h, "DELETE", "/aliases/latest/sabores", "", nil))
+	assert.Equal(t, http.StatusOK, do(t, h, "DELETE", "/aliases/latest", "", nil))
+	assert.Equal(t, http.StatusNotFound, do(t, h, "DELETE", "/aliases/latest", "", nil))
+}
+
+func TestHTTPHandler_BadRequests(t *testing.T) {
+	h := NewHTTPHandler(visigoth.NewIndexRepo(visigoth.NewMemoryIndexBuilder(
+		visigoth.NewKeepAlphanumericTokenizer(),
+	)), WithMaxBodyBytes(64))
+
+	var errRes ErrorResponse
+	code := do(t, h, "POST", "/indices/dedos/docs", `{"id": `, &errRes)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, CodeBadRequest, errRes.Error.Code)
+
+	code = do(t, h, "POST", "/indices/dedos/docs",
+		`{"id": "pulgar", "content": "`+strings.Repeat("huevos ", 20)+`"}`, &errRes)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, code)
+
+	code = do(t, h, "GET", "/indices/dedos/_search?size=-1", "", &errRes)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code = do(t, h, "GET", "/nowhere", "", &errRes)
+	assert.Equal(t, http.StatusNotFound, code)
+}
+
+func TestHTTPHandler_NoRoute(t *testing.T) {
+	h := newTestHandler(WithKeyStore(testKeys(t)))
+
+	// Requests matching no route are told so before authenticating
+	var errRes ErrorResponse
+	assert.Equal(t, http.StatusNotFound, do(t, h, "GET", "/nowhere", "", &errRes))
+	assert.Equal(t, CodeNotFound, errRes.Error.Code)
+	assert.Equal(t, "no route for GET /nowhere", errRes.Error.Message)
+
+	req := httptest.NewRequest("POST", "/indices", nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	assert.Equal(t, "GET, HEAD", rec.Header().Get("Allow"))
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &errRes))
+	assert.Equal(t, CodeMethodNotAllowed, errRes.Error.Code)
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/indices/dedos", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	assert.Equal(t, "DELETE, PUT", rec.Header().Get("Allow"))
+
+	assert.Equal(t, http.StatusUnauthorized, do(t, h, "GET", "/indices", "", nil),
+		"routes should still require a key")
+}
@@ -0,0 +1,83 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/sonirico/visigoth"
+)
+
+// Document is the JSON representation of a document to index.
+type Document struct {
+	ID      string `json:"id"`
+	Content string `json:"content"`
+	// Statement is the text to analyze, when different from the content.
+	Statement string `json:"statement,omitempty"`
+	// Mime is either "text", the default, or "json".
+	Mime     string `json:"mime,omitempty"`
+	Language string `json:"language,omitempty"`
+}
+
+// DocRequest validates the document and turns it into a DocRequest.
+func (d Document) DocRequest() (visigoth.DocRequest, error) {
+	if d.ID == "" {
+		return visigoth.DocRequest{}, fmt.Errorf("document with no 'id'")
+	}
+	var mime visigoth.MimeType
+	switch d.Mime {
+	case "", "text":
+		mime = visigoth.MimeText
+	case "json":
+		mime = visigoth.MimeJSON
+	default:
+		return visigoth.DocRequest{}, fmt.Errorf("unknown mime type '%s'", d.Mime)
+	}
+	statement := d.Statement
+	if statement == "" {
+		statement = d.Content
+	}
+	req := visigoth.NewDocRequestWith(d.ID, d.Content, statement)
+	req.MimeType = mime
+	return req.WithLanguage(visigoth.Language(d.Language)), nil
+}
+
+type IndicesResponse struct {
+	Indices []string `json:"indices"`
+}
+
+type RenameRequest struct {
+	Name string `json:"name"`
+}
+
+type AliasesResponse struct {
+	Aliases []Alias `json:"aliases"`
+}
+
+type Alias struct {
+	Alias   string   `json:"alias"`
+	Indices []string `json:"indices"`
+}
+
+// BulkResponse reports how many documents of a bulk request were indexed, and
+// why the others were not.
+type BulkResponse struct {
+	Indexed int         `json:"indexed"`
+	Errors  []BulkError `json:"errors,omitempty"`
+}
+
+type BulkError struct {
+	// Line is the 1-based line number of the document in the request.
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+// SearchResponse holds a page of search results. It is marshalled by
+// writeSearchResponse with the easyjson marshallers of SearchResult.
+type SearchResponse struct {
+	Total   int                     `json:"total"`
+	From    int                     `json:"from"`
+	Results []visigoth.SearchResult `json:"results"`
+}
+
+type AcknowledgedResponse struct {
+	Acknowledged bool `json:"acknowledged"`
+}
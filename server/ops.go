@@ -0,0 +1,177 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/sonirico/visigoth"
+)
+
+// The functions below run repo operations whose outcome the repo reports as
+// a boolean, returning instead an *APIError explaining any failure, so that
+// every API fails alike. They also check first that the caller is allowed to
+// run them, when the repo is an authorizedRepo.
+
+func createIndex(repo visigoth.Repo, name string) error {
+	if name == "" {
+		return badRequest(errors.New("index with no name"))
+	}
+	if err := authorize(repo, RoleAdmin, name); err != nil {
+		return err
+	}
+	if !repo.Create(name) {
+		return conflict(fmt.Sprintf("index or alias with name '%s' already exists", name))
+	}
+	return nil
+}
+
+func dropIndex(repo visigoth.Repo, name string) error {
+	if err := authorize(repo, RoleAdmin, name); err != nil {
+		return err
+	}
+	if !repo.Drop(name) {
+		return notFound("index", name)
+	}
+	return nil
+}
+
+func renameIndex(repo visigoth.Repo, name, newName string) error {
+	if newName == "" {
+		return badRequest(errors.New("rename with no new name"))
+	}
+	if err := authorize(repo, RoleAdmin, name, newName); err != nil {
+		return err
+	}
+	if repo.Rename(name, newName) {
+		return nil
+	}
+	if !repo.Has(name) {
+		return notFound("index", name)
+	}
+	return conflict(fmt.Sprintf("index or alias with name '%s' already exists", newName))
+}
+
+func addAlias(repo visigoth.Repo, alias, index string) error {
+	if alias == "" {
+		return badRequest(errors.New("alias with no name"))
+	}
+	if err := authorize(repo, RoleAdmin, alias, index); err != nil {
+		return err
+	}
+	if !repo.Has(index) {
+		return notFound("index", index)
+	}
+	if repo.Has(alias) {
+		return conflict(fmt.Sprintf("index with name '%s' already exists", alias))
+	}
+	if !repo.Alias(alias, index) {
+		return conflict(fmt.Sprintf("index '%s' is already aliased as '%s'", index, alias))
+	}
+	return nil
+}
+
+// aliasSetter is implemented by the repos which can point an alias to a
+// single index in one step, as visigoth.IndexRepo does.
+type aliasSetter interface {
+	SetAlias(alias, index string) error
+}
+
+// setAlias points the alias to the index only, creating the alias if needed.
+func setAlias(repo visigoth.Repo, alias, index string) error {
+	if alias == "" {
+		return badRequest(errors.New("alias with no name"))
+	}
+	if err := authorize(repo, RoleAdmin, alias, index); err != nil {
+		return err
+	}
+	if setter, ok := repo.(aliasSetter); ok {
+		return setter.SetAlias(alias, index)
+	}
+	if !repo.Has(index) {
+		return notFound("index", index)
+	}
+	if repo.HasAlias(alias) {
+		if err := removeAlias(repo, alias, ""); err != nil {
+			return err
+		}
+	}
+	return addAlias(repo, alias, index)
+}
+
+// removeAlias removes the index from the alias, or the whole alias if index
+// is empty.
+func removeAlias(repo visigoth.Repo, alias, index string) error {
+	names := []string{alias}
+	if index != "" {
+		names = append(names, index)
+	}
+	if err := authorize(repo, RoleAdmin, names...); err != nil {
+		return err
+	}
+	if !repo.HasAlias(alias) {
+		return notFound("alias", alias)
+	}
+	if !repo.UnAlias(alias, index) {
+		return notFound("index", index)
+	}
+	return nil
+}
+
+// authorizePut returns a forbidden error unless the repo allows writing to
+// the index or alias, or creating the index when neither exists, as putting
+// a document then does.
+func authorizePut(repo visigoth.Repo, index string) error {
+	role := RoleWrite
+	if !repo.Has(index) && !repo.HasAlias(index) {
+		role = RoleAdmin
+	}
+	return authorize(repo, role, index)
+}
+
+func putDocument(repo visigoth.Repo, index string, req visigoth.DocRequest) error {
+	if err := authorizePut(repo, index); err != nil {
+		return err
+	}
+	repo.Put(index, req)
+	return nil
+}
+
+// inserter is implemented by the repos which can put a document unless one
+// with the same ID exists, as visigoth.IndexRepo does.
+type inserter interface {
+	Insert(in string, req visigoth.DocRequest) error
+}
+
+// insertDocument puts the document unless the index, or any index pointed by
+// the alias, has one with the same ID, failing then with a conflict.
+func insertDocument(repo visigoth.Repo, index string, req visigoth.DocRequest) error {
+	if err := authorizePut(repo, index); err != nil {
+		return err
+	}
+	ins, ok := repo.(inserter)
+	if !ok {
+		return fmt.Errorf("repo cannot check the IDs of documents put to '%s'", index)
+	}
+	return ins.Insert(index, req)
+}
+
+// listIndices returns the indices sorted by name.
+func listIndices(repo visigoth.Repo) []string {
+	indices := repo.List()
+	sort.Strings(indices)
+	return indices
+}
+
+// listAliases returns the aliases sorted by name.
+func listAliases(repo visigoth.Repo) []Alias {
+	rows := repo.ListAliases().Aliases
+	aliases := make([]Alias, len(rows))
+	for i, row := range rows {
+		aliases[i] = Alias{Alias: row.Alias, Indices: row.Indices}
+	}
+	sort.Slice(aliases, func(i, j int) bool {
+		return aliases[i].Alias < aliases[j].Alias
+	})
+	return aliases
+}
@@ -0,0 +1,159 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	// maxRESPBulkLen, maxRESPArrayLen and maxRESPLineLen bound what clients
+	// may send, lines being inline commands and the headers of arrays and
+	// bulk strings, while maxRESPCommandLen bounds the bulk strings of a
+	// command altogether.
+	maxRESPBulkLen    = 64 << 20
+	maxRESPArrayLen   = 1 << 20
+	maxRESPLineLen    = 64 << 10
+	maxRESPCommandLen = 64 << 20
+
+	// Connections yet to authenticate may only send commands about the size
+	// of AUTH, as in Redis.
+	maxRESPUnauthBulkLen    = 16 << 10
+	maxRESPUnauthArrayLen   = 10
+	maxRESPUnauthCommandLen = 64 << 10
+)
+
+// errRESPProtocol is returned for malformed input, after which the
+// connection is closed, as Redis does.
+var errRESPProtocol = errors.New("protocol error")
+
+// respReader reads commands in RESP, either as arrays of bulk strings, as
+// sent by clients, or inline, as typed in a terminal.
+type respReader struct {
+	r *bufio.Reader
+	// authenticated lifts the limits of connections yet to authenticate.
+	authenticated bool
+}
+
+// limits returns the maximum length of bulk strings, of arrays and of the
+// bulk strings of a command altogether.
+func (r *respReader) limits() (bulk, array, command int) {
+	if r.authenticated {
+		return maxRESPBulkLen, maxRESPArrayLen, maxRESPCommandLen
+	}
+	return maxRESPUnauthBulkLen, maxRESPUnauthArrayLen, maxRESPUnauthCommandLen
+}
+
+func (r *respReader) line() (string, error) {
+	var line []byte
+	for {
+		chunk, err := r.r.ReadSlice('\n')
+		// The limit leaves room for the terminating CRLF.
+		if len(line)+len(chunk) > maxRESPLineLen+2 {
+			return "", fmt.Errorf("%w: too big inline request", errRESPProtocol)
+		}
+		line = append(line, chunk...)
+		if errors.Is(err, bufio.ErrBufferFull) {
+			continue
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) && len(line) > 0 {
+				return "", io.ErrUnexpectedEOF
+			}
+			return "", err
+		}
+		return strings.TrimSuffix(string(line[:len(line)-1]), "\r"), nil
+	}
+}
+
+// command returns the arguments of the next command, which are empty for
+// blank inline commands.
+func (r *respReader) command() ([]string, error) {
+	line, err := r.line()
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(line, "*") {
+		return strings.Fields(line), nil
+	}
+
+	maxBulk, maxArray, maxCommand := r.limits()
+	n, err := strconv.Atoi(line[1:])
+	if err != nil || n > maxArray {
+		return nil, fmt.Errorf("%w: invalid multibulk length", errRESPProtocol)
+	}
+	// Arguments are allocated as they arrive, not as announced.
+	args := make([]string, 0, min(max(n, 0), 16))
+	var data bytes.Buffer
+	for range n {
+		header, err := r.line()
+		if err != nil {
+			return nil, err
+		}
+		if !strings.HasPrefix(header, "$") {
+			return nil, fmt.Errorf("%w: expected '$', got '%.1s'", errRESPProtocol, header)
+		}
+		size, err := strconv.Atoi(header[1:])
+		if err != nil || size < 0 || size > maxBulk {
+			return nil, fmt.Errorf("%w: invalid bulk length", errRESPProtocol)
+		}
+		if maxCommand -= size; maxCommand < 0 {
+			return nil, fmt.Errorf("%w: too big request", errRESPProtocol)
+		}
+		data.Reset()
+		if _, err := io.CopyN(&data, r.r, int64(size)+2); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+		if !bytes.HasSuffix(data.Bytes(), []byte("\r\n")) {
+			return nil, fmt.Errorf("%w: bulk string not terminated", errRESPProtocol)
+		}
+		args = append(args, string(data.Bytes()[:size]))
+	}
+	return args, nil
+}
+
+// respWriter writes RESP2 replies.
+type respWriter struct {
+	w *bufio.Writer
+}
+
+func (w *respWriter) simple(s string) {
+	w.w.WriteString("+" + s + "\r\n")
+}
+
+// error writes an error reply, prefixed with ERR unless it starts with an
+// error code such as WRONGTYPE.
+func (w *respWriter) error(message string) {
+	message = strings.NewReplacer("\r", " ", "\n", " ").Replace(message)
+	if code, _, _ := strings.Cut(message, " "); code == "" || code != strings.ToUpper(code) {
+		message = "ERR " + message
+	}
+	w.w.WriteString("-" + message + "\r\n")
+}
+
+func (w *respWriter) integer(n int) {
+	w.w.WriteString(":" + strconv.Itoa(n) + "\r\n")
+}
+
+func (w *respWriter) bulk(s string) {
+	w.w.WriteString("$" + strconv.Itoa(len(s)) + "\r\n" + s + "\r\n")
+}
+
+// array writes the header of an array of n elements, to be written next.
+func (w *respWriter) array(n int) {
+	w.w.WriteString("*" + strconv.Itoa(n) + "\r\n")
+}
+
+func (w *respWriter) bulks(items []string) {
+	w.array(len(items))
+	for _, item := range items {
+		w.bulk(item)
+	}
+}
@@ -0,0 +1,569 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sonirico/visigoth"
+)
+
+// ErrRESPServerClosed is returned by RESPServer.Serve after Shutdown.
+var ErrRESPServerClosed = errors.New("resp: server closed")
+
+// defaultRESPLimit is the number of results FT.SEARCH returns with no LIMIT.
+const defaultRESPLimit = 10
+
+// DefaultRESPIdleTimeout is how long a RESPServer waits for the next command
+// of a connection before closing it.
+const DefaultRESPIdleTimeout = 5 * time.Minute
+
+// RESPServer serves a visigoth.Repo over RESP, the Redis protocol, with a
+// subset of the RediSearch commands, so that Redis clients can index and
+// search documents:
+//
+//	FT.CREATE index [ignored schema ...]
+//	FT.ADD index id score [LANGUAGE lang] FIELDS field value [field value ...]
+//	FT.SEARCH index query [NOCONTENT] [WITHSCORES] [LANGUAGE lang] [LIMIT offset num]
+//	FT.ALIASADD alias index | FT.ALIASUPDATE alias index | FT.ALIASDEL alias
+//	FT.DROP index | FT.DROPINDEX index | FT._LIST
+//	AUTH [username] key
+//
+// The fields of documents added with FT.ADD are stored as a JSON object and
+// their values analyzed as the statement. FT.ADD fails for IDs already added,
+// as REPLACE is not supported. FT.SEARCH accepts a search engine with ENGINE
+// name, and the query "*" matches every document. Scores are the hits of each
+// result.
+//
+// With a key store, connections must AUTH with a key of it, the username
+// being ignored, before running any other command, and are then limited to
+// what the key allows. Until then, commands are limited to the size of AUTH.
+type RESPServer struct {
+	repo        visigoth.Repo
+	logger      *slog.Logger
+	keys        *KeyStore
+	idleTimeout time.Duration
+
+	mu       sync.Mutex
+	ln       net.Listener
+	conns    map[net.Conn]struct{}
+	closing  bool
+	handlers sync.WaitGroup
+}
+
+// respCommand runs a command given its arguments, with the name at args[0],
+// against the repo as seen by the connection, writing the reply unless it
+// fails.
+type respCommand struct {
+	// arity is the minimum number of arguments, the name included.
+	arity int
+	run   func(s *RESPServer, repo visigoth.Repo, w *respWriter, args []string) error
+}
+
+var respCommands = map[string]respCommand{
+	"PING":           {1, (*RESPServer).ping},
+	"COMMAND":        {1, (*RESPServer).command},
+	"FT.CREATE":      {2, (*RESPServer).ftCreate},
+	"FT.ADD":         {4, (*RESPServer).ftAdd},
+	"FT.SEARCH":      {3, (*RESPServer).ftSearch},
+	"FT.DROP":        {2, (*RESPServer).ftDrop},
+	"FT.DROPINDEX":   {2, (*RESPServer).ftDrop},
+	"FT._LIST":       {1, (*RESPServer).ftList},
+	"FT.ALIASADD":    {3, (*RESPServer).ftAliasAdd},
+	"FT.ALIASUPDATE": {3, (*RESPServer).ftAliasUpdate},
+	"FT.ALIASDEL":    {2, (*RESPServer).ftAliasDel},
+}
+
+func (s *RESPServer) ping(repo visigoth.Repo, w *respWriter, args []string) error {
+	if len(args) > 1 {
+		w.bulk(args[1])
+		return nil
+	}
+	w.simple("PONG")
+	return nil
+}
+
+// command replies to COMMAND, which some clients send on connecting, with no
+// command details.
+func (s *RESPServer) command(repo visigoth.Repo, w *respWriter, _ []string) error {
+	w.array(0)
+	return nil
+}
+
+func (s *RESPServer) ftCreate(repo visigoth.Repo, w *respWriter, args []string) error {
+	if err := createIndex(repo, args[1]); err != nil {
+		return err
+	}
+	w.simple("OK")
+	return nil
+}
+
+func (s *RESPServer) ftAdd(repo visigoth.Repo, w *respWriter, args []string) error {
+	if _, err := strconv.ParseFloat(args[3], 64); err != nil {
+		return badRequest(fmt.Errorf("invalid score '%s'", args[3]))
+	}
+	var (
+		language string
+		fields   []string
+	)
+	for i := 4; i < len(args); i++ {
+		switch strings.ToUpper(args[i]) {
+		case "FIELDS":
+			fields = args[i+1:]
+			if len(fields) == 0 || len(fields)%2 != 0 {
+				return badRequest(errors.New("fields must be given as field value pairs"))
+			}
+			i = len(args)
+		case "LANGUAGE", "PAYLOAD":
+			if i+1 == len(args) {
+				return respSyntaxError()
+			}
+			if strings.EqualFold(args[i], "LANGUAGE") {
+				language = args[i+1]
+			}
+			i++
+		case "REPLACE", "PARTIAL":
+			return badRequest(errors.New("option REPLACE is not supported"))
+		case "NOSAVE":
+		default:
+			return respSyntaxError()
+		}
+	}
+	if fields == nil {
+		return badRequest(errors.New("no fields given"))
+	}
+
+	var (
+		content   bytes.Buffer
+		statement strings.Builder
+	)
+	content.WriteByte('{')
+	for i := 0; i < len(fields); i += 2 {
+		if i > 0 {
+			content.WriteByte(',')
+			statement.WriteByte('\n')
+		}
+		name, _ := json.Marshal(fields[i])
+		value, _ := json.Marshal(fields[i+1])
+		content.Write(name)
+		content.WriteByte(':')
+		content.Write(value)
+		statement.WriteString(fields[i+1])
+	}
+	content.WriteByte('}')
+
+	req := visigoth.NewDocRequestWith(args[2], content.String(), statement.String())
+	req.MimeType = visigoth.MimeJSON
+	err := insertDocument(repo, args[1], req.WithLanguage(visigoth.Language(language)))
+	if errors.As(err, new(visigoth.ExistsError)) {
+		return conflict("Document already exists")
+	}
+	if err != nil {
+		return err
+	}
+	w.simple("OK")
+	return nil
+}
+
+func (s *RESPServer) ftSearch(repo visigoth.Repo, w *respWriter, args []string) error {
+	p := searchParams{index: args[1], terms: args[2], size: defaultRESPLimit}
+	if strings.TrimSpace(p.terms) == "*" {
+		p.engine = "noop_all"
+	}
+	var noContent, withScores bool
+	for i := 3; i < len(args); i++ {
+		switch strings.ToUpper(args[i]) {
+		case "NOCONTENT":
+			noContent = true
+		case "WITHSCORES":
+			withScores = true
+		case "LANGUAGE":
+			if i+1 == len(args) {
+				return respSyntaxError()
+			}
+			p.languages = append(p.languages, args[i+1])
+			i++
+		case "ENGINE":
+			if i+1 == len(args) {
+				return respSyntaxError()
+			}
+			p.engine = args[i+1]
+			i++
+		case "LIMIT":
+			if i+2 >= len(args) {
+				return respSyntaxError()
+			}
+			from, err := strconv.Atoi(args[i+1])
+			if err != nil || from < 0 {
+				return badRequest(fmt.Errorf("invalid offset '%s'", args[i+1]))
+			}
+			size, err := strconv.Atoi(args[i+2])
+			if err != nil || size < 0 {
+				return badRequest(fmt.Errorf("invalid limit '%s'", args[i+2]))
+			}
+			p.from, p.size = from, size
+			i += 2
+		default:
+			return respSyntaxError()
+		}
+	}
+
+	total, results, err := searchPage(repo, p)
+	if err != nil {
+		return err
+	}
+	perResult := 1
+	if withScores {
+		perResult++
+	}
+	if !noContent {
+		perResult++
+	}
+	w.array(1 + perResult*len(results))
+	w.integer(total)
+	for _, result := range results {
+		w.bulk(result.Document.ID())
+		if withScores {
+			w.bulk(strconv.Itoa(result.Hits))
+		}
+		if !noContent {
+			w.bulks(respFields(result.Document.Raw()))
+		}
+	}
+	return nil
+}
+
+func (s *RESPServer) ftDrop(repo visigoth.Repo, w *respWriter, args []string) error {
+	if err := dropIndex(repo, args[1]); err != nil {
+		return err
+	}
+	w.simple("OK")
+	return nil
+}
+
+func (s *RESPServer) ftList(repo visigoth.Repo, w *respWriter, _ []string) error {
+	w.bulks(listIndices(repo))
+	return nil
+}
+
+func (s *RESPServer) ftAliasAdd(repo visigoth.Repo, w *respWriter, args []string) error {
+	if err := addAlias(repo, args[1], args[2]); err != nil {
+		return err
+	}
+	w.simple("OK")
+	return nil
+}
+
+// ftAliasUpdate points the alias to the index only, creating it if needed.
+func (s *RESPServer) ftAliasUpdate(repo visigoth.Repo, w *respWriter, args []string) error {
+	if err := setAlias(repo, args[1], args[2]); err != nil {
+		return err
+	}
+	w.simple("OK")
+	return nil
+}
+
+func (s *RESPServer) ftAliasDel(repo visigoth.Repo, w *respWriter, args []string) error {
+	if err := removeAlias(repo, args[1], ""); err != nil {
+		return err
+	}
+	w.simple("OK")
+	return nil
+}
+
+// auth returns the repo as seen by the key given to AUTH.
+func (s *RESPServer) auth(args []string) (visigoth.Repo, error) {
+	if len(args) < 2 || len(args) > 3 {
+		return nil, badRequest(errors.New("wrong number of arguments for 'auth' command"))
+	}
+	if s.keys == nil {
+		return nil, badRequest(errors.New("auth called without any API keys configured"))
+	}
+	key, ok := s.keys.Authenticate(args[len(args)-1])
+	if !ok {
+		return nil, unauthorized("WRONGPASS invalid API key")
+	}
+	return AuthorizedRepo(s.repo, key), nil
+}
+
+// respErrorMessage returns the message of the error reply to a failed
+// command, with the NOPERM code of Redis if the key does not allow it.
+func respErrorMessage(err error) string {
+	apiErr := toAPIError(err)
+	if apiErr.Code == CodeForbidden {
+		return "NOPERM " + apiErr.Message
+	}
+	return apiErr.Message
+}
+
+func respSyntaxError() error {
+	return badRequest(errors.New("syntax error"))
+}
+
+// respFields returns the fields and values of a document added with FT.ADD,
+// in order, or the raw content as the "content" field of any other document.
+func respFields(raw string) []string {
+	dec := json.NewDecoder(strings.NewReader(raw))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return []string{"content", raw}
+	}
+	var fields []string
+	for dec.More() {
+		name, err := dec.Token()
+		if err != nil {
+			return []string{"content", raw}
+		}
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return []string{"content", raw}
+		}
+		var text string
+		if err := json.Unmarshal(value, &text); err != nil {
+			text = string(value)
+		}
+		fields = append(fields, name.(string), text)
+	}
+	return fields
+}
+
+// Serve accepts connections on the listener, serving each in its own
+// goroutine, until Shutdown is called, after which it returns
+// ErrRESPServerClosed.
+func (s *RESPServer) Serve(ln net.Listener) error {
+	s.mu.Lock()
+	if s.closing {
+		s.mu.Unlock()
+		ln.Close()
+		return ErrRESPServerClosed
+	}
+	s.ln = ln
+	s.mu.Unlock()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			s.mu.Lock()
+			closing := s.closing
+			s.mu.Unlock()
+			if closing {
+				return ErrRESPServerClosed
+			}
+			return err
+		}
+		if !s.track(conn) {
+			conn.Close()
+			continue
+		}
+		go s.serveConn(conn)
+	}
+}
+
+// track registers the connection, unless the server is shutting down.
+func (s *RESPServer) track(conn net.Conn) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closing {
+		return false
+	}
+	s.conns[conn] = struct{}{}
+	s.handlers.Add(1)
+	return true
+}
+
+func (s *RESPServer) untrack(conn net.Conn) {
+	s.mu.Lock()
+	delete(s.conns, conn)
+	s.mu.Unlock()
+	s.handlers.Done()
+}
+
+func (s *RESPServer) serveConn(conn net.Conn) {
+	defer s.untrack(conn)
+	defer conn.Close()
+
+	r := &respReader{r: bufio.NewReader(conn)}
+	w := &respWriter{w: bufio.NewWriter(conn)}
+	// The repo is nil until the connection authenticates, if it must.
+	var repo visigoth.Repo
+	if s.keys == nil {
+		repo = s.repo
+		r.authenticated = true
+	}
+	for {
+		s.setReadDeadline(conn)
+		args, err := r.command()
+		if err != nil {
+			if errors.Is(err, errRESPProtocol) {
+				w.error(err.Error())
+				w.w.Flush()
+			}
+			var netErr net.Error
+			if !errors.Is(err, io.EOF) && !errors.As(err, &netErr) {
+				s.logger.Warn("reading RESP command", "remote", conn.RemoteAddr(), "error", err)
+			}
+			return
+		}
+		if len(args) == 0 {
+			continue
+		}
+
+		name := strings.ToUpper(args[0])
+		if name == "QUIT" {
+			w.simple("OK")
+			w.w.Flush()
+			return
+		}
+		cmd, ok := respCommands[name]
+		switch {
+		case name == "AUTH":
+			if authRepo, err := s.auth(args); err != nil {
+				w.error(respErrorMessage(err))
+			} else {
+				repo = authRepo
+				r.authenticated = true
+				w.simple("OK")
+			}
+		case repo == nil:
+			w.error("NOAUTH Authentication required.")
+		case !ok:
+			w.error(fmt.Sprintf("unknown command '%s'", args[0]))
+		case len(args) < cmd.arity:
+			w.error(fmt.Sprintf("wrong number of arguments for '%s' command", args[0]))
+		default:
+			if err := cmd.run(s, repo, w, args); err != nil {
+				w.error(respErrorMessage(err))
+			}
+		}
+		s.logger.Debug("resp command", "command", name, "remote", conn.RemoteAddr())
+
+		// Replies to pipelined commands are written together.
+		if r.r.Buffered() == 0 {
+			if err := w.w.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// setReadDeadline gives the connection until the idle timeout to send the
+// next command, or until now if the server is shutting down.
+func (s *RESPServer) setReadDeadline(conn net.Conn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case s.closing:
+		conn.SetReadDeadline(time.Now())
+	case s.idleTimeout > 0:
+		conn.SetReadDeadline(time.Now().Add(s.idleTimeout))
+	default:
+		conn.SetReadDeadline(time.Time{})
+	}
+}
+
+// Shutdown stops accepting connections, closes idle ones and waits for the
+// others to finish their commands, or until ctx is done, in which case it
+// closes them and returns the context error.
+func (s *RESPServer) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	s.closing = true
+	var err error
+	if s.ln != nil {
+		err = s.ln.Close()
+	}
+	// Connections waiting for a command return at once, while those running
+	// one do after replying.
+	for conn := range s.conns {
+		conn.SetReadDeadline(time.Now())
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.handlers.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return err
+	case <-ctx.Done():
+		s.mu.Lock()
+		for conn := range s.conns {
+			conn.Close()
+		}
+		s.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// ServeRESP runs srv on the listener until ctx is done, and then shuts it
+// down gracefully, waiting up to timeout for running commands to finish. It
+// returns nil after a graceful shutdown.
+func ServeRESP(ctx context.Context, srv *RESPServer, ln net.Listener, timeout time.Duration) error {
+	errs := make(chan error, 1)
+	go func() {
+		errs <- srv.Serve(ln)
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errs; !errors.Is(err, ErrRESPServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// RESPServerOption configures a RESPServer.
+type RESPServerOption func(*RESPServer)
+
+// WithRESPKeyStore requires connections to authenticate with a key of the
+// store, and limits them to what the key allows.
+func WithRESPKeyStore(keys *KeyStore) RESPServerOption {
+	return func(s *RESPServer) {
+		s.keys = keys
+	}
+}
+
+// WithRESPIdleTimeout closes connections sending no command for timeout, or
+// never if it is zero, instead of after DefaultRESPIdleTimeout.
+func WithRESPIdleTimeout(timeout time.Duration) RESPServerOption {
+	return func(s *RESPServer) {
+		s.idleTimeout = timeout
+	}
+}
+
+// NewRESPServer returns a server of the repo logging to logger, or nowhere if
+// it is nil.
+func NewRESPServer(repo visigoth.Repo, logger *slog.Logger, opts ...RESPServerOption) *RESPServer {
+	if logger == nil {
+		logger = slog.New(discardHandler{})
+	}
+	s := &RESPServer{
+		repo:        repo,
+		logger:      logger,
+		idleTimeout: DefaultRESPIdleTimeout,
+		conns:       make(map[net.Conn]struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
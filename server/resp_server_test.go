@@ -0,0 +1,292 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sonirico/visigoth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// respError is an error reply read by testRESPClient.
+type respError string
+
+type testRESPClient struct {
+	t    *testing.T
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+func (c *testRESPClient) send(args ...string) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	_, err := c.conn.Write([]byte(b.String()))
+	require.NoError(c.t, err)
+}
+
+// reply reads a reply as a string, respError, int, nil or []any.
+func (c *testRESPClient) reply() any {
+	line, err := c.r.ReadString('\n')
+	require.NoError(c.t, err)
+	line = strings.TrimSuffix(line, "\r\n")
+	switch line[0] {
+	case '+':
+		return line[1:]
+	case '-':
+		return respError(line[1:])
+	case ':':
+		n, err := strconv.Atoi(line[1:])
+		require.NoError(c.t, err)
+		return n
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		require.NoError(c.t, err)
+		if n < 0 {
+			return nil
+		}
+		data := make([]byte, n+2)
+		_, err = io.ReadFull(c.r, data)
+		require.NoError(c.t, err)
+		return string(data[:n])
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		require.NoError(c.t, err)
+		items := make([]any, n)
+		for i := range items {
+			items[i] = c.reply()
+		}
+		return items
+	}
+	c.t.Fatalf("unexpected reply %q", line)
+	return nil
+}
+
+func (c *testRESPClient) do(args ...string) any {
+	c.send(args...)
+	return c.reply()
+}
+
+func newTestRESPServer(t *testing.T, opts ...RESPServerOption) (*RESPServer, string) {
+	repo := visigoth.NewIndexRepo(visigoth.NewMemoryIndexBuilder(
+		visigoth.NewTokenizationPipeline(
+			visigoth.NewKeepAlphanumericTokenizer(),
+			visigoth.NewLowerCaseTokenizer(),
+		),
+	))
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	srv := NewRESPServer(repo, nil, opts...)
+	go func() { _ = srv.Serve(ln) }()
+	t.Cleanup(func() { _ = srv.Shutdown(context.Background()) })
+	return srv, ln.Addr().String()
+}
+
+func dialTestRESP(t *testing.T, addr string) *testRESPClient {
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return &testRESPClient{t: t, conn: conn, r: bufio.NewReader(conn)}
+}
+
+func TestRESPServer_Commands(t *testing.T) {
+	_, addr := newTestRESPServer(t)
+	c := dialTestRESP(t, addr)
+
+	assert.Equal(t, "PONG", c.do("PING"))
+	assert.Equal(t, "OK", c.do("FT.CREATE", "dedos", "SCHEMA", "body", "TEXT"))
+	assert.Equal(t,
+		respError("ERR index or alias with name 'dedos' already exists"),
+		c.do("ft.create", "dedos"))
+
+	assert.Equal(t, "OK", c.do("FT.ADD", "dedos", "pulgar", "1.0",
+		"FIELDS", "title", "pulgar", "body", "este fue a por huevos"))
+	assert.Equal(t, "OK", c.do("FT.ADD", "dedos", "corazon", "1", "LANGUAGE", "es",
+		"FIELDS", "body", "este fue a por huevos y los probo"))
+	assert.Equal(t, respError("ERR Document already exists"),
+		c.do("FT.ADD", "dedos", "pulgar", "1", "FIELDS", "body", "este fue a por sal"))
+	assert.Equal(t, respError("ERR option REPLACE is not supported"),
+		c.do("FT.ADD", "dedos", "pulgar", "1", "REPLACE", "FIELDS", "body", "sal"))
+	assert.Equal(t, respError("ERR syntax error"),
+		c.do("FT.ADD", "dedos", "indice", "1", "BOGUS", "FIELDS", "body", "x"))
+	assert.Equal(t, respError("ERR invalid score 'alto'"),
+		c.do("FT.ADD", "dedos", "indice", "alto", "FIELDS", "body", "x"))
+
+	assert.Equal(t, []any{
+		2,
+		"corazon", []any{"body", "este fue a por huevos y los probo"},
+		"pulgar", []any{"title", "pulgar", "body", "este fue a por huevos"},
+	}, c.do("FT.SEARCH", "dedos", "huevos"))
+	assert.Equal(t, []any{2, "pulgar", "2"},
+		c.do("FT.SEARCH", "dedos", "este huevos", "NOCONTENT", "WITHSCORES", "LIMIT", "1", "1"))
+	assert.Equal(t, []any{1, "corazon"},
+		c.do("FT.SEARCH", "dedos", "huevos", "NOCONTENT", "LANGUAGE", "es"))
+	assert.Equal(t, []any{2, "corazon", "pulgar"},
+		c.do("FT.SEARCH", "dedos", "*", "NOCONTENT"))
+	assert.Equal(t, respError("ERR index with name 'meñique' does not exist"),
+		c.do("FT.SEARCH", "meñique", "huevos"))
+	assert.Equal(t, respError("ERR wrong number of arguments for 'FT.SEARCH' command"),
+		c.do("FT.SEARCH", "dedos"))
+
+	assert.Equal(t, "OK", c.do("FT.ALIASADD", "manos", "dedos"))
+	assert.Equal(t, []any{1, "pulgar"}, c.do("FT.SEARCH", "manos", "pulgar", "NOCONTENT"))
+	assert.Equal(t, "OK", c.do("FT.CREATE", "pies"))
+	assert.Equal(t, "OK", c.do("FT.ALIASUPDATE", "manos", "pies"))
+	assert.Equal(t, []any{0}, c.do("FT.SEARCH", "manos", "pulgar"))
+	assert.Equal(t, "OK", c.do("FT.ALIASDEL", "manos"))
+	assert.Equal(t, respError("ERR alias with name 'manos' does not exist"),
+		c.do("FT.ALIASDEL", "manos"))
+
+	assert.Equal(t, []any{"dedos", "pies"}, c.do("FT._LIST"))
+	assert.Equal(t, "OK", c.do("FT.DROP", "pies"))
+	assert.Equal(t, respError("ERR index with name 'pies' does not exist"),
+		c.do("FT.DROPINDEX", "pies"))
+	assert.Equal(t, respError("ERR unknown command 'GET'"), c.do("GET", "dedos"))
+	assert.Equal(t, "OK", c.do("QUIT"))
+}
+
+func TestRESPServer_Auth(t *testing.T) {
+	_, addr := newTestRESPServer(t, WithRESPKeyStore(testKeys(t)))
+	admin := dialTestRESP(t, addr)
+	writer := dialTestRESP(t, addr)
+
+	assert.Equal(t, respError("NOAUTH Authentication required."), admin.do("FT._LIST"))
+	assert.Equal(t, respError("WRONGPASS invalid API key"), admin.do("AUTH", "nope"))
+	assert.Equal(t, "OK", admin.do("AUTH", "default", "4dm1n"))
+	assert.Equal(t, "OK", writer.do("AUTH", "wr1t3"))
+	reply := writer.do("FT.ADD", "dedos", "pulgar", "1", "FIELDS", "body", "huevos")
+	require.IsType(t, respError(""), reply)
+	assert.True(t, strings.HasPrefix(string(reply.(respError)), "NOPERM "), reply)
+	assert.Equal(t, "OK", admin.do("FT.CREATE", "dedos"))
+	assert.Equal(t, "OK", admin.do("FT.CREATE", "manos"))
+
+	assert.Equal(t, "OK", writer.do("FT.ADD", "dedos", "pulgar", "1", "FIELDS", "body", "huevos"))
+	assert.Equal(t, []any{"dedos"}, writer.do("FT._LIST"))
+	reply = writer.do("FT.ADD", "manos", "palma", "1", "FIELDS", "body", "huevos")
+	require.IsType(t, respError(""), reply)
+	assert.True(t, strings.HasPrefix(string(reply.(respError)), "NOPERM "), reply)
+	reply = writer.do("FT.DROP", "dedos")
+	require.IsType(t, respError(""), reply)
+	assert.True(t, strings.HasPrefix(string(reply.(respError)), "NOPERM "), reply)
+}
+
+func TestRESPServer_AliasUpdate_Forbidden(t *testing.T) {
+	keys, err := NewKeyStore([]APIKey{
+		{Name: "aliaser", Key: "4l14s", Grants: []Grant{
+			{Role: RoleAdmin, Indices: []string{"manos*"}},
+			{Role: RoleRead, Indices: []string{"dedos"}},
+		}},
+		{Name: "admin", Key: "4dm1n", Grants: []Grant{{Role: RoleAdmin, Indices: []string{"*"}}}},
+	})
+	require.NoError(t, err)
+	_, addr := newTestRESPServer(t, WithRESPKeyStore(keys))
+	admin := dialTestRESP(t, addr)
+	aliaser := dialTestRESP(t, addr)
+	assert.Equal(t, "OK", admin.do("AUTH", "4dm1n"))
+	assert.Equal(t, "OK", aliaser.do("AUTH", "4l14s"))
+	assert.Equal(t, "OK", admin.do("FT.CREATE", "pies"))
+	assert.Equal(t, "OK", admin.do("FT.CREATE", "dedos"))
+	assert.Equal(t, "OK", admin.do("FT.ALIASADD", "manos", "pies"))
+
+	reply := aliaser.do("FT.ALIASUPDATE", "manos", "dedos")
+	require.IsType(t, respError(""), reply)
+	assert.True(t, strings.HasPrefix(string(reply.(respError)), "NOPERM "), reply)
+	assert.Equal(t, []any{0}, aliaser.do("FT.SEARCH", "manos", "*"),
+		"the alias should be kept")
+	assert.Equal(t, respError("ERR index with name 'uñas' does not exist"),
+		admin.do("FT.ALIASUPDATE", "manos", "uñas"))
+	assert.Equal(t, "OK", admin.do("FT.ALIASUPDATE", "manos", "dedos"))
+}
+
+func TestRESPServer_Limits(t *testing.T) {
+	_, addr := newTestRESPServer(t, WithRESPKeyStore(testKeys(t)))
+
+	// Connections yet to authenticate may not announce big commands
+	c := dialTestRESP(t, addr)
+	_, err := c.conn.Write([]byte("*1\r\n$67108864\r\n"))
+	require.NoError(t, err)
+	assert.Equal(t, respError("ERR protocol error: invalid bulk length"), c.reply())
+	_, err = c.r.ReadByte()
+	assert.Error(t, err, "connection should be closed")
+
+	c = dialTestRESP(t, addr)
+	_, err = c.conn.Write([]byte("*11\r\n"))
+	require.NoError(t, err)
+	assert.Equal(t, respError("ERR protocol error: invalid multibulk length"), c.reply())
+
+	c = dialTestRESP(t, addr)
+	c.send("PING", strings.Repeat("x", maxRESPUnauthBulkLen/2),
+		strings.Repeat("x", maxRESPUnauthBulkLen/2))
+	assert.Equal(t, respError("NOAUTH Authentication required."), c.reply())
+	c.send("AUTH", strings.Repeat("x", maxRESPUnauthBulkLen),
+		strings.Repeat("x", maxRESPUnauthBulkLen), strings.Repeat("x", maxRESPUnauthBulkLen),
+		strings.Repeat("x", maxRESPUnauthBulkLen), strings.Repeat("x", maxRESPUnauthBulkLen))
+	assert.Equal(t, respError("ERR protocol error: too big request"), c.reply())
+
+	// and those authenticated may
+	c = dialTestRESP(t, addr)
+	assert.Equal(t, "OK", c.do("AUTH", "4dm1n"))
+	big := strings.Repeat("x", maxRESPUnauthCommandLen)
+	assert.True(t, c.do("PING", big) == big, "big commands should be read")
+}
+
+func TestRESPServer_IdleTimeout(t *testing.T) {
+	_, addr := newTestRESPServer(t, WithRESPIdleTimeout(50*time.Millisecond))
+	c := dialTestRESP(t, addr)
+	assert.Equal(t, "PONG", c.do("PING"))
+
+	require.NoError(t, c.conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	_, err := c.r.ReadByte()
+	var netErr net.Error
+	require.Error(t, err)
+	assert.False(t, errors.As(err, &netErr) && netErr.Timeout(), "idle connections are closed")
+}
+
+func TestRESPServer_InlineAndPipelined(t *testing.T) {
+	_, addr := newTestRESPServer(t)
+	c := dialTestRESP(t, addr)
+
+	_, err := c.conn.Write([]byte("PING\r\n\r\nFT.CREATE dedos\r\n" +
+		"*2\r\n$4\r\nPING\r\n$4\r\nhola\r\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "PONG", c.reply())
+	assert.Equal(t, "OK", c.reply())
+	assert.Equal(t, "hola", c.reply())
+
+	_, err = c.conn.Write([]byte("*1\r\n:4\r\n"))
+	require.NoError(t, err)
+	assert.Equal(t, respError("ERR protocol error: expected '$', got ':'"), c.reply())
+
+	c = dialTestRESP(t, addr)
+	_, err = c.conn.Write([]byte("PING " + strings.Repeat("x", maxRESPLineLen) + "\r\n"))
+	require.NoError(t, err)
+	assert.Equal(t, respError("ERR protocol error: too big inline request"), c.reply())
+	_, err = c.r.ReadByte()
+	assert.Error(t, err, "connection should be closed")
+}
+
+func TestRESPServer_Shutdown(t *testing.T) {
+	srv, addr := newTestRESPServer(t)
+	c := dialTestRESP(t, addr)
+	assert.Equal(t, "PONG", c.do("PING"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, srv.Shutdown(ctx))
+
+	_, err := c.r.ReadByte()
+	assert.Error(t, err, "idle connections are closed")
+	_, err = net.Dial("tcp", addr)
+	assert.Error(t, err)
+}
@@ -0,0 +1,56 @@
+package server
+
+import (
+	"sort"
+
+	"github.com/sonirico/vago/streams"
+	"github.com/sonirico/visigoth"
+)
+
+// searchParams are the parameters of a search common to every API.
+type searchParams struct {
+	index  string
+	terms  string
+	engine string
+	from   int
+	// size limits the number of results, all of them if negative.
+	size      int
+	languages []string
+}
+
+// searchPage searches the repo, returning the total number of results and the
+// requested page of them, sorted by relevance.
+func searchPage(repo visigoth.Repo, p searchParams) (int, []visigoth.SearchResult, error) {
+	if p.engine == "" {
+		p.engine = DefaultEngine
+	}
+	engine, err := visigoth.EngineByName(p.engine)
+	if err != nil {
+		return 0, nil, badRequest(err)
+	}
+	var opts []visigoth.SearchOption
+	if len(p.languages) > 0 {
+		languages := make([]visigoth.Language, len(p.languages))
+		for i, lang := range p.languages {
+			languages[i] = visigoth.Language(lang)
+		}
+		opts = append(opts, visigoth.WithLanguages(languages...))
+	}
+
+	stream, err := repo.SearchWith(p.index, p.terms, engine, opts...)
+	if err != nil {
+		return 0, nil, err
+	}
+	results, err := streams.Consume(stream)
+	if err != nil {
+		return 0, nil, err
+	}
+	sort.Sort(visigoth.SearchResults(results))
+
+	total := len(results)
+	results = results[min(p.from, total):]
+	if p.size >= 0 && p.size < len(results) {
+		results = results[:p.size]
+	}
+	return total, results, nil
+}
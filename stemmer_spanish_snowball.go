@@ -1,5 +1,10 @@
 package visigoth
 
+import (
+	"bytes"
+	"unicode/utf8"
+)
+
 var vowels = map[rune]struct{}{
 	'a': {},
 	'e': {},
@@ -19,104 +24,309 @@ func isVowel(r rune) (ok bool) {
 	return
 }
 
-//nolint:nakedret,gomnd
-func R1R2RV(w []byte) (r1 []byte, r2 []byte, rv []byte) {
-	// UTF8 implicit!
-	foundVowel := false
-	foundNonVowel := false
-	type runeSlot struct {
-		Index   int
-		Value   rune
-		IsVowel bool
-	}
-	runes := make([]runeSlot, 0)
-	for index, runeValue := range string(w) {
-		runes = append(runes, runeSlot{Index: index, Value: runeValue, IsVowel: isVowel(runeValue)})
-	}
-	runesLength := len(runes)
-	// R1
-	for _, slot := range runes {
-		if foundNonVowel {
-			r1 = w[slot.Index:]
-			break
-		}
-		if foundVowel {
-			if !slot.IsVowel {
-				foundNonVowel = true
-			}
-		}
-		if slot.IsVowel {
-			foundVowel = true
-		}
-	}
-	if r1 == nil {
+// regions returns the byte offsets where the R1, R2 and RV regions of w
+// start. Offsets equal to len(w) mean the region is empty.
+//
+// R1 is the region after the first non-vowel following a vowel, or the end of
+// the word if there is no such non-vowel. R2 is the R1 region of R1.
+//
+// RV is defined as follows: if the second letter is a consonant, RV is the
+// region after the next following vowel, or if the first two letters are
+// vowels, RV is the region after the next consonant, and otherwise
+// (consonant-vowel case) RV is the region after the third letter. But RV is
+// the end of the word if these positions cannot be found.
+func regions(w []byte) (r1 int, r2 int, rv int) {
+	r1 = vnvSuffix(w, 0)
+	r2 = vnvSuffix(w, r1)
+	rv = len(w)
+
+	c0, s0 := utf8.DecodeRune(w)
+	c1, s1 := utf8.DecodeRune(w[s0:])
+	if utf8.RuneCount(w) < 3 {
 		return
 	}
-	// R2
-	foundVowel = false
-	foundNonVowel = false
-	for index, runeValue := range string(r1) {
-		if foundNonVowel {
-			r2 = r1[index:]
-			break
+	switch {
+	case !isVowel(c1):
+		if i := nextRune(w, s0+s1, true); i >= 0 {
+			rv = i
 		}
-		if foundVowel {
-			if !isVowel(runeValue) {
-				foundNonVowel = true
-			}
-		}
-		if isVowel(runeValue) {
-			foundVowel = true
+	case isVowel(c0):
+		if i := nextRune(w, s0+s1, false); i >= 0 {
+			rv = i
 		}
+	default:
+		_, s2 := utf8.DecodeRune(w[s0+s1:])
+		rv = s0 + s1 + s2
 	}
-	// RV. If the second letter is a consonant, RV is the region after the next following vowel, or if the first
-	// two letters are vowels, RV is the region after the next consonant, and otherwise (consonant-vowel case) RV
-	// is the region after the third letter. But RV is the end of the word if these positions cannot be found.
-	if len(runes) < 3 {
-		return
+	return
+}
+
+// vnvSuffix returns the offset after the first non-vowel following a vowel,
+// starting at from, or len(w) if there is none.
+func vnvSuffix(w []byte, from int) int {
+	prevVowel := false
+	for i := from; i < len(w); {
+		r, size := utf8.DecodeRune(w[i:])
+		vowel := isVowel(r)
+		if prevVowel && !vowel {
+			return i + size
+		}
+		prevVowel = vowel
+		i += size
 	}
-	foundNonVowel = false
-	foundVowel = false
-	if runes[0].IsVowel && runes[1].IsVowel {
-		i := 2
-		for ; i < runesLength; i++ {
-			run := runes[i]
-			if foundNonVowel {
-				rv = w[run.Index:]
-				return
-			}
-			if !run.IsVowel {
-				foundNonVowel = true
-			}
+	return len(w)
+}
+
+// nextRune returns the offset after the first vowel (or non-vowel) found
+// starting at from, or -1 if there is none.
+func nextRune(w []byte, from int, vowel bool) int {
+	for i := from; i < len(w); {
+		r, size := utf8.DecodeRune(w[i:])
+		i += size
+		if isVowel(r) == vowel {
+			return i
 		}
 	}
+	return -1
+}
+
+// R1R2RV returns the R1, R2 and RV regions of w. See regions.
+//
+//nolint:nakedret
+func R1R2RV(w []byte) (r1 []byte, r2 []byte, rv []byte) {
+	p1, p2, pv := regions(w)
+	r1, r2, rv = w[p1:], w[p2:], w[pv:]
+	return
+}
+
+// Suffix lists, sorted from longest to shortest so that the first match is
+// the longest one.
+var (
+	spanishPronounSuffixes = []string{
+		"selas", "selos", "sela", "selo", "las", "les", "los", "nos", "me", "se", "la", "le", "lo",
+	}
+	spanishPronounVerbSuffixes = []string{
+		"iéndo", "iendo", "yendo", "ándo", "ando", "ár", "ér", "ír", "ar", "er", "ir",
+	}
+	spanishStandardSuffixes = []string{
+		"amientos", "imientos", "aciones", "amiento", "imiento", "uciones", "logías", "idades",
+		"encias", "ancias", "amente", "adores", "adoras", "ución", "mente", "logía", "istas",
+		"ismos", "ibles", "encia", "anzas", "antes", "ancia", "adora", "ación", "ables", "osos",
+		"osas", "ivos", "ivas", "ista", "ismo", "idad", "icos", "icas", "ible", "anza", "ante",
+		"ador", "able", "oso", "osa", "ivo", "iva", "ico", "ica",
+	}
+	spanishYVerbSuffixes = []string{
+		"yeron", "yendo", "yamos", "yais", "yan", "yen", "yas", "yes", "ya", "ye", "yo", "yó",
+	}
+	spanishVerbSuffixes = []string{
+		"iésemos", "iéramos", "iríamos", "eríamos", "aríamos", "ásemos", "áramos", "ábamos",
+		"isteis", "iríais", "iremos", "ieseis", "ierais", "eríais", "eremos", "asteis",
+		"aríais", "aremos", "íamos", "irías", "irían", "iréis", "ieses", "iesen", "ieron",
+		"ieras", "ieran", "iendo", "erías", "erían", "eréis", "aseis", "arías", "arían",
+		"aréis", "arais", "abais", "íais", "iste", "iría", "irás", "irán", "imos", "iese",
+		"iera", "idos", "idas", "ería", "erás", "erán", "aste", "ases", "asen", "aría",
+		"arás", "arán", "aron", "aras", "aran", "ando", "amos", "ados", "adas", "abas",
+		"aban", "emos", "ías", "ían", "éis", "áis", "iré", "irá", "ido", "ida", "eré",
+		"erá", "ase", "aré", "ará", "ara", "ado", "ada", "aba", "ís", "ía", "ió", "ir",
+		"id", "es", "er", "en", "ed", "as", "ar", "an", "ad",
+	}
+	spanishResidualSuffixes = []string{"os", "a", "o", "á", "í", "ó", "e", "é"}
+)
 
-	if !runes[1].IsVowel {
-		i := 2
-		for ; i < runesLength; i++ {
-			run := runes[i]
-			if foundVowel {
-				rv = w[run.Index:]
-				return
-			}
-			if run.IsVowel {
-				foundVowel = true
-			}
+// longestSuffix returns the first suffix of the list w ends with, or "".
+func longestSuffix(w []byte, suffixes []string) string {
+	for _, suffix := range suffixes {
+		if len(suffix) <= len(w) && string(w[len(w)-len(suffix):]) == suffix {
+			return suffix
 		}
 	}
+	return ""
+}
 
-	if runesLength > 3 && !runes[0].IsVowel && runes[1].IsVowel {
-		rv = w[runes[3].Index:]
-		return
+// removeSuffixIn removes the longest suffix of the list w ends with, provided
+// it starts at or after the region offset. The removed suffix is returned.
+func removeSuffixIn(w []byte, region int, suffixes ...string) ([]byte, string) {
+	suffix := longestSuffix(w, suffixes)
+	if suffix == "" || len(w)-len(suffix) < region {
+		return w, ""
 	}
+	return w[:len(w)-len(suffix)], suffix
+}
 
-	return
+// removeSuffixWithin removes the longest suffix of the list w ends with among
+// those lying entirely after the region offset. The removed suffix is
+// returned.
+func removeSuffixWithin(w []byte, region int, suffixes ...string) ([]byte, string) {
+	if region > len(w) {
+		return w, ""
+	}
+	suffix := longestSuffix(w[region:], suffixes)
+	return w[:len(w)-len(suffix)], suffix
+}
+
+func replaceSuffix(w []byte, suffix string, repl string) []byte {
+	return append(w[:len(w)-len(suffix)], repl...)
 }
 
+// Stemmer is a native implementation of the Snowball Spanish stemming
+// algorithm, https://snowballstem.org/algorithms/spanish/stemmer.html
 type Stemmer struct{}
 
+// Stem returns the stem of the lower cased word w. Stems are never longer
+// than words, so w is stemmed in place: the result shares its backing array.
 func (s *Stemmer) Stem(w []byte) []byte {
-	return nil
+	r1, r2, rv := regions(w)
+
+	w = s.step0(w, rv)
+	if next, ok := s.step1(w, r1, r2); ok {
+		w = next
+	} else if next, ok := s.step2a(w, rv); ok {
+		w = next
+	} else {
+		w = s.step2b(w, rv)
+	}
+	w = s.step3(w, rv)
+	return removeAcuteAccents(w)
+}
+
+// step0 removes attached pronouns following a gerund or infinitive in RV,
+// e.g. "haciéndola" -> "haciendo".
+func (s *Stemmer) step0(w []byte, rv int) []byte {
+	pronoun := longestSuffix(w, spanishPronounSuffixes)
+	if pronoun == "" {
+		return w
+	}
+	stem := w[:len(w)-len(pronoun)]
+	verb := longestSuffix(stem, spanishPronounVerbSuffixes)
+	if verb == "" || len(stem)-len(verb) < rv {
+		return w
+	}
+	switch verb {
+	case "iéndo":
+		return replaceSuffix(stem, verb, "iendo")
+	case "ándo":
+		return replaceSuffix(stem, verb, "ando")
+	case "ár":
+		return replaceSuffix(stem, verb, "ar")
+	case "ér":
+		return replaceSuffix(stem, verb, "er")
+	case "ír":
+		return replaceSuffix(stem, verb, "ir")
+	case "yendo":
+		if i := len(stem) - len(verb) - 1; i < 0 || stem[i] != 'u' {
+			return w
+		}
+	}
+	return stem
+}
+
+// step1 removes standard suffixes. It reports whether a suffix was removed.
+func (s *Stemmer) step1(w []byte, r1 int, r2 int) ([]byte, bool) {
+	suffix := longestSuffix(w, spanishStandardSuffixes)
+	if suffix == "" {
+		return w, false
+	}
+	start := len(w) - len(suffix)
+
+	if suffix == "amente" {
+		if start < r1 {
+			return w, false
+		}
+		w, removed := removeSuffixIn(w[:start], r2, "iv", "os", "ic", "ad")
+		if removed == "iv" {
+			w, _ = removeSuffixIn(w, r2, "at")
+		}
+		return w, true
+	}
+
+	if start < r2 {
+		return w, false
+	}
+	switch suffix {
+	case "adora", "ador", "ación", "adoras", "adores", "aciones",
+		"ante", "antes", "ancia", "ancias":
+		w, _ = removeSuffixIn(w[:start], r2, "ic")
+	case "logía", "logías":
+		w = replaceSuffix(w, suffix, "log")
+	case "ución", "uciones":
+		w = replaceSuffix(w, suffix, "u")
+	case "encia", "encias":
+		w = replaceSuffix(w, suffix, "ente")
+	case "mente":
+		w, _ = removeSuffixIn(w[:start], r2, "ante", "able", "ible")
+	case "idad", "idades":
+		w, _ = removeSuffixIn(w[:start], r2, "abil", "ic", "iv")
+	case "iva", "ivo", "ivas", "ivos":
+		w, _ = removeSuffixIn(w[:start], r2, "at")
+	default:
+		w = w[:start]
+	}
+	return w, true
+}
+
+// step2a removes verb suffixes beginning with "y" in RV when preceded by "u".
+// It reports whether a suffix was removed.
+func (s *Stemmer) step2a(w []byte, rv int) ([]byte, bool) {
+	stem, suffix := removeSuffixWithin(w, rv, spanishYVerbSuffixes...)
+	if suffix == "" || len(stem) == 0 || stem[len(stem)-1] != 'u' {
+		return w, false
+	}
+	return stem, true
+}
+
+// step2b removes other verb suffixes in RV.
+func (s *Stemmer) step2b(w []byte, rv int) []byte {
+	w, suffix := removeSuffixWithin(w, rv, spanishVerbSuffixes...)
+	switch suffix {
+	case "en", "es", "éis", "emos":
+		// Delete the "u" of a preceding "gu", which need not be in RV
+		if bytes.HasSuffix(w, []byte("gu")) {
+			w = w[:len(w)-1]
+		}
+	}
+	return w
+}
+
+// step3 removes residual suffixes in RV.
+func (s *Stemmer) step3(w []byte, rv int) []byte {
+	w, suffix := removeSuffixIn(w, rv, spanishResidualSuffixes...)
+	switch suffix {
+	case "e", "é":
+		// Delete the "u" of a preceding "gu" if the "u" is in RV
+		if bytes.HasSuffix(w, []byte("gu")) && len(w)-1 >= rv {
+			w = w[:len(w)-1]
+		}
+	}
+	return w
+}
+
+// removeAcuteAccents replaces "á", "é", "í", "ó" and "ú" by their unaccented
+// vowel, in place.
+func removeAcuteAccents(w []byte) []byte {
+	n := 0
+	for i := 0; i < len(w); {
+		r, size := utf8.DecodeRune(w[i:])
+		switch r {
+		case 'á':
+			r = 'a'
+		case 'é':
+			r = 'e'
+		case 'í':
+			r = 'i'
+		case 'ó':
+			r = 'o'
+		case 'ú':
+			r = 'u'
+		}
+		if r < utf8.RuneSelf {
+			w[n] = byte(r)
+			n++
+		} else {
+			n += copy(w[n:], w[i:i+size])
+		}
+		i += size
+	}
+	return w[:n]
 }
 
 func NewSnowball() *Stemmer {
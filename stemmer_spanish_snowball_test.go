@@ -1,7 +1,9 @@
 package visigoth
 
 import (
+	"bufio"
 	"bytes"
+	"os"
 	"testing"
 )
 
@@ -70,3 +72,53 @@ func TestRV(t *testing.T) {
 		}
 	}
 }
+
+func TestRegions_ShortR1(t *testing.T) {
+	// RV must be computed even when R1 is empty
+	r1, r2, rv := R1R2RV([]byte("crear"))
+	if len(r1) != 0 || len(r2) != 0 {
+		t.Fatalf("unexpected R1/R2, want empty, have '%s'/'%s'", string(r1), string(r2))
+	}
+	if string(rv) != "ar" {
+		t.Fatalf("unexpected RV, want 'ar', have '%s'", string(rv))
+	}
+}
+
+// TestStemmer_Vocabulary validates the stemmer against the Snowball sample
+// vocabulary and expected output, as shipped by github.com/kljensen/snowball.
+func TestStemmer_Vocabulary(t *testing.T) {
+	// The reference output was produced by an implementation leaving words
+	// of up to two bytes untouched, while the algorithm removes the accent.
+	divergences := map[string]string{"ó": "o"}
+
+	voc, err := os.Open("testdata/snowball/spanish/voc.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer voc.Close()
+	output, err := os.Open("testdata/snowball/spanish/output.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer output.Close()
+
+	stemmer := NewSnowball()
+	words, stems := bufio.NewScanner(voc), bufio.NewScanner(output)
+	var checked int
+	for words.Scan() {
+		if !stems.Scan() {
+			t.Fatalf("output list is shorter than vocabulary")
+		}
+		word, want := words.Text(), stems.Text()
+		if stem, ok := divergences[word]; ok {
+			want = stem
+		}
+		if have := string(stemmer.Stem([]byte(word))); have != want {
+			t.Errorf("unexpected stem for '%s', want '%s', have '%s'", word, want, have)
+		}
+		checked++
+	}
+	if checked == 0 {
+		t.Fatalf("empty vocabulary")
+	}
+}